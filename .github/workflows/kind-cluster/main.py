@@ -116,6 +116,9 @@ def main():
         model_url, model_commit = get_model_git_info(model_version, hf_username, hf_token)
         download_new_model(model_name, model_url)
         update_model(model_name, model_commit)
+    else:
+        # Images of models without a version, like the huggingface preset, are built with an empty weights directory.
+        os.makedirs(get_weights_path(model_name), exist_ok=True)
     clone_and_checkout_pr_branch(pr_branch)
 
     job_names = []
@@ -16,6 +16,9 @@ const (
 	// ConditionTypeResourceStatus is the state when Resource has been created.
 	ConditionTypeResourceStatus = ConditionType("ResourceReady")
 
+	// ConditionTypeGPUQuotaStatus is the state when checking the nodes to create against the GPU quotas of the namespace.
+	ConditionTypeGPUQuotaStatus = ConditionType("GPUQuotaSatisfied")
+
 	// WorkspaceConditionTypeInferenceStatus is the state when Inference service has been ready.
 	WorkspaceConditionTypeInferenceStatus = ConditionType("InferenceReady")
 
@@ -34,10 +37,25 @@ const (
 	//WorkspaceConditionTypeDeleting is the Workspace state when starts to get deleted.
 	WorkspaceConditionTypeDeleting = ConditionType("WorkspaceDeleting")
 
+	//WorkspaceConditionTypeRolledBack is the state of the last rollback requested by the rollback-to-revision annotation.
+	WorkspaceConditionTypeRolledBack ConditionType = ConditionType("RolledBack")
+
+	//WorkspaceConditionTypeScaledToZero is the state when an idle workspace has been scaled to zero by its idle policy.
+	WorkspaceConditionTypeScaledToZero ConditionType = ConditionType("ScaledToZero")
+
+	//WorkspaceConditionTypeScheduleActive is the state when the schedule of a workspace lets the inference workload run.
+	WorkspaceConditionTypeScheduleActive ConditionType = ConditionType("ScheduleActive")
+
+	//WorkspaceConditionTypePresetUpgradeAvailable is the state when a newer tag of the preset image than the one used by the workspace is released.
+	WorkspaceConditionTypePresetUpgradeAvailable ConditionType = ConditionType("PresetUpgradeAvailable")
+
 	//WorkspaceConditionTypeSucceeded is the Workspace state that summarizes all operations' states.
 	//For inference, the "True" condition means the inference service is ready to serve requests.
 	//For fine tuning, the "True" condition means the tuning job completes successfully.
 	WorkspaceConditionTypeSucceeded ConditionType = ConditionType("WorkspaceSucceeded")
 
 	RAGEngineConditionTypeSucceeded ConditionType = ConditionType("RAGEngineSucceeded")
+
+	//ModelPresetConditionTypeRegistered is the state when the ModelPreset is registered as a preset model.
+	ModelPresetConditionTypeRegistered ConditionType = ConditionType("Registered")
 )
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GPUQuotaSpec defines the limits applied to the Workspaces and RAGEngines of the selected namespaces.
+type GPUQuotaSpec struct {
+	// Namespaces is the list of namespaces the quota applies to. The limits are enforced for
+	// each namespace separately, they are not shared among the namespaces.
+	Namespaces []string `json:"namespaces"`
+	// MaxGPUs is the maximum number of GPUs that the nodes requested by all Workspaces and RAGEngines
+	// in a namespace can have. The number of GPUs of an instance type comes from the SKU data of the cloud provider.
+	// +optional
+	MaxGPUs *int `json:"maxGPUs,omitempty"`
+	// MaxNodes is the maximum number of nodes that all Workspaces and RAGEngines in a namespace can request.
+	// +optional
+	MaxNodes *int `json:"maxNodes,omitempty"`
+	// InstanceTypeFamilies is the list of instance type prefixes allowed in a namespace, e.g., Standard_NC.
+	// If not specified, any instance type is allowed.
+	// +optional
+	InstanceTypeFamilies []string `json:"instanceTypeFamilies,omitempty"`
+}
+
+// GPUQuota is the Schema for the gpuquotas API
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=gpuquotas,scope=Cluster,categories=workspace
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="MaxGPUs",type="integer",JSONPath=".spec.maxGPUs",description=""
+// +kubebuilder:printcolumn:name="MaxNodes",type="integer",JSONPath=".spec.maxNodes",description=""
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+type GPUQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GPUQuotaSpec `json:"spec,omitempty"`
+}
+
+// GPUQuotaList contains a list of GPUQuota
+// +kubebuilder:object:root=true
+type GPUQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GPUQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GPUQuota{}, &GPUQuotaList{})
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/samber/lo"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrGPUQuotaExceeded is wrapped by the errors returned by ValidateGPUQuota when the requested nodes do not fit in a
+// GPUQuota, as opposed to failures to read the quotas or the usage of the namespace.
+var ErrGPUQuotaExceeded = errors.New("GPU quota exceeded")
+
+// ValidateGPUQuota checks that count nodes of the given instance types, together with the nodes requested by the
+// other Workspaces and RAGEngines in the namespace of obj, fit in every GPUQuota that applies to the namespace.
+// Each instance type is checked on its own because any of them may be used to provision the nodes of obj.
+func ValidateGPUQuota(ctx context.Context, c client.Client, obj client.Object, instanceTypes []string, count int) error {
+	quotaList := &GPUQuotaList{}
+	if err := c.List(ctx, quotaList); err != nil {
+		return fmt.Errorf("failed to list GPU quotas: %w", err)
+	}
+	quotas := lo.Filter(quotaList.Items, func(q GPUQuota, _ int) bool {
+		return lo.Contains(q.Spec.Namespaces, obj.GetNamespace())
+	})
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	gpuConfigs := map[string]sku.GPUConfig{}
+	if skuHandler, err := utils.GetSKUHandler(); err == nil {
+		gpuConfigs = skuHandler.GetGPUConfigs()
+	}
+	usedNodes, usedGPUs, err := getNamespaceGPUUsage(ctx, c, obj, gpuConfigs)
+	if err != nil {
+		return err
+	}
+
+	for _, quota := range quotas {
+		for _, instanceType := range instanceTypes {
+			families := quota.Spec.InstanceTypeFamilies
+			if len(families) > 0 && !lo.SomeBy(families, func(family string) bool { return strings.HasPrefix(instanceType, family) }) {
+				return fmt.Errorf("%w: instance type %s is not in the families %v allowed by quota %s",
+					ErrGPUQuotaExceeded, instanceType, families, quota.Name)
+			}
+			if quota.Spec.MaxNodes != nil && usedNodes+count > *quota.Spec.MaxNodes {
+				return fmt.Errorf("%w: quota %s allows %d nodes in namespace %s, %d are used and %d more are requested",
+					ErrGPUQuotaExceeded, quota.Name, *quota.Spec.MaxNodes, obj.GetNamespace(), usedNodes, count)
+			}
+			if quota.Spec.MaxGPUs != nil {
+				gpuConfig, ok := gpuConfigs[instanceType]
+				if !ok {
+					return fmt.Errorf("%w: quota %s limits GPUs but the number of GPUs of instance type %s is unknown",
+						ErrGPUQuotaExceeded, quota.Name, instanceType)
+				}
+				if requested := count * gpuConfig.GPUCount; usedGPUs+requested > *quota.Spec.MaxGPUs {
+					return fmt.Errorf("%w: quota %s allows %d GPUs in namespace %s, %d are used and %d more are requested",
+						ErrGPUQuotaExceeded, quota.Name, *quota.Spec.MaxGPUs, obj.GetNamespace(), usedGPUs, requested)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getNamespaceGPUUsage returns the number of nodes and GPUs requested by the Workspaces and RAGEngines in the
+// namespace of obj, excluding obj itself. Instance types without SKU data are counted as nodes without GPUs.
+func getNamespaceGPUUsage(ctx context.Context, c client.Client, obj client.Object, gpuConfigs map[string]sku.GPUConfig) (int, int, error) {
+	var nodes, gpus int
+	addUsage := func(instanceType string, count int) {
+		nodes += count
+		gpus += count * gpuConfigs[instanceType].GPUCount
+	}
+
+	workspaceList := &WorkspaceList{}
+	if err := c.List(ctx, workspaceList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return 0, 0, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for i := range workspaceList.Items {
+		ws := &workspaceList.Items[i]
+		if _, ok := obj.(*Workspace); ok && ws.Name == obj.GetName() {
+			continue
+		}
+		addUsage(GetWorkspaceInstanceType(ws), lo.FromPtr(ws.Resource.Count))
+	}
+
+	ragEngineList := &RAGEngineList{}
+	if err := c.List(ctx, ragEngineList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return 0, 0, fmt.Errorf("failed to list ragengines: %w", err)
+	}
+	for i := range ragEngineList.Items {
+		rag := &ragEngineList.Items[i]
+		if _, ok := obj.(*RAGEngine); ok && rag.Name == obj.GetName() {
+			continue
+		}
+		if rag.Spec == nil || rag.Spec.Compute == nil {
+			continue
+		}
+		addUsage(rag.Spec.Compute.InstanceType, lo.FromPtr(rag.Spec.Compute.Count))
+	}
+	return nodes, gpus, nil
+}
+
+// validateGPUQuota rejects the request if count nodes of the given instance types exceed a GPUQuota of the namespace.
+// The check is skipped if the webhook has no client, the controllers enforce the quotas before creating nodes anyway.
+func validateGPUQuota(ctx context.Context, obj client.Object, instanceTypes []string, count int) (errs *apis.FieldError) {
+	if k8sclient.Client == nil {
+		return nil
+	}
+	if err := ValidateGPUQuota(ctx, k8sclient.Client, obj, instanceTypes, count); err != nil {
+		errs = errs.Also(apis.ErrGeneric(err.Error(), "count"))
+	}
+	return errs
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func quotaTestWorkspace(name string, instanceType string, count int) *Workspace {
+	return &Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+		Resource:   ResourceSpec{InstanceType: instanceType, Count: lo.ToPtr(count)},
+	}
+}
+
+func TestValidateGPUQuota(t *testing.T) {
+	os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	defer os.Unsetenv("CLOUD_PROVIDER")
+
+	tests := []struct {
+		name          string
+		quota         GPUQuotaSpec
+		existing      []client.Object
+		obj           client.Object
+		instanceTypes []string
+		count         int
+		errContent    string // Content expected error to include, if any
+	}{
+		{
+			name:          "No quota for the namespace",
+			quota:         GPUQuotaSpec{Namespaces: []string{"team-b"}, MaxGPUs: lo.ToPtr(0)},
+			obj:           quotaTestWorkspace("ws", "Standard_NC12s_v3", 2),
+			instanceTypes: []string{"Standard_NC12s_v3"},
+			count:         2,
+		},
+		{
+			name:  "Within GPU and node quota",
+			quota: GPUQuotaSpec{Namespaces: []string{"team-a"}, MaxGPUs: lo.ToPtr(4), MaxNodes: lo.ToPtr(3)},
+			existing: []client.Object{
+				quotaTestWorkspace("other", "Standard_NC6s_v3", 1),
+			},
+			obj:           quotaTestWorkspace("ws", "Standard_NC6s_v3", 2),
+			instanceTypes: []string{"Standard_NC6s_v3"},
+			count:         2,
+		},
+		{
+			name:  "GPU quota exceeded together with other workspaces",
+			quota: GPUQuotaSpec{Namespaces: []string{"team-a"}, MaxGPUs: lo.ToPtr(4)},
+			existing: []client.Object{
+				quotaTestWorkspace("other", "Standard_NC12s_v3", 1),
+			},
+			obj:           quotaTestWorkspace("ws", "Standard_NC12s_v3", 2),
+			instanceTypes: []string{"Standard_NC12s_v3"},
+			count:         2,
+			errContent:    "quota team-quota allows 4 GPUs in namespace team-a, 2 are used and 4 more are requested",
+		},
+		{
+			name:  "The object itself is not counted as used",
+			quota: GPUQuotaSpec{Namespaces: []string{"team-a"}, MaxGPUs: lo.ToPtr(4)},
+			existing: []client.Object{
+				quotaTestWorkspace("ws", "Standard_NC12s_v3", 1),
+			},
+			obj:           quotaTestWorkspace("ws", "Standard_NC12s_v3", 2),
+			instanceTypes: []string{"Standard_NC12s_v3"},
+			count:         2,
+		},
+		{
+			name:  "Node quota exceeded with RAGEngines",
+			quota: GPUQuotaSpec{Namespaces: []string{"team-a"}, MaxNodes: lo.ToPtr(2)},
+			existing: []client.Object{
+				&RAGEngine{
+					ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "team-a"},
+					Spec:       &RAGEngineSpec{Compute: &ResourceSpec{InstanceType: "Standard_NC6s_v3", Count: lo.ToPtr(1)}},
+				},
+			},
+			obj:           quotaTestWorkspace("ws", "Standard_NC6s_v3", 2),
+			instanceTypes: []string{"Standard_NC6s_v3"},
+			count:         2,
+			errContent:    "quota team-quota allows 2 nodes in namespace team-a, 1 are used and 2 more are requested",
+		},
+		{
+			name:          "Instance type family not allowed",
+			quota:         GPUQuotaSpec{Namespaces: []string{"team-a"}, InstanceTypeFamilies: []string{"Standard_NC"}},
+			obj:           quotaTestWorkspace("ws", "Standard_NC6s_v3", 1),
+			instanceTypes: []string{"Standard_NC6s_v3", "Standard_ND96asr_v4"},
+			count:         1,
+			errContent:    "instance type Standard_ND96asr_v4 is not in the families [Standard_NC] allowed by quota team-quota",
+		},
+		{
+			name:          "Unknown GPU count with a GPU quota",
+			quota:         GPUQuotaSpec{Namespaces: []string{"team-a"}, MaxGPUs: lo.ToPtr(8)},
+			obj:           quotaTestWorkspace("ws", "Standard_D4s_v3", 1),
+			instanceTypes: []string{"Standard_D4s_v3"},
+			count:         1,
+			errContent:    "the number of GPUs of instance type Standard_D4s_v3 is unknown",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			quota := &GPUQuota{ObjectMeta: metav1.ObjectMeta{Name: "team-quota"}, Spec: tc.quota}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tc.existing, quota)...).Build()
+
+			err := ValidateGPUQuota(context.Background(), c, tc.obj, tc.instanceTypes, tc.count)
+			if tc.errContent == "" {
+				if err != nil {
+					t.Errorf("ValidateGPUQuota() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errContent) {
+				t.Errorf("ValidateGPUQuota() error = %v, expected to contain %s", err, tc.errContent)
+			}
+			if !errors.Is(err, ErrGPUQuotaExceeded) {
+				t.Errorf("ValidateGPUQuota() error = %v, expected to wrap ErrGPUQuotaExceeded", err)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
 )
 
 const (
@@ -22,6 +23,9 @@ const (
 	// LabelWorkspaceName is the label for workspace name.
 	LabelWorkspaceName = KAITOPrefix + "workspace"
 
+	// LabelActivatorName is the label for the activator pods of a workspace, the value is the workspace name.
+	LabelActivatorName = KAITOPrefix + "activator"
+
 	// LabelRAGEngineName is the label for ragengine name.
 	LabelRAGEngineName = KAITOPrefix + "ragengine"
 
@@ -39,26 +43,78 @@ const (
 
 	// AnnotationWorkspaceRuntime is the annotation for runtime selection.
 	AnnotationWorkspaceRuntime = KAITOPrefix + "runtime"
+
+	// AnnotationRollbackToRevision is the annotation for rolling back the workspace to the given revision number.
+	// The annotation is removed by the controller once the rollback is processed.
+	AnnotationRollbackToRevision = KAITOPrefix + "rollback-to-revision"
+
+	// AnnotationUpgradePreset is the annotation for upgrading the preset image of the workspace to a newer tag. The
+	// value is either a released tag of the preset or "latest", and the tag is pinned as the version of the preset.
+	// The annotation is removed by the controller once the upgrade is processed.
+	AnnotationUpgradePreset = KAITOPrefix + "upgrade-preset"
+
+	// AnnotationScheduleOverride is the annotation for overriding the schedule of the workspace. The value is either
+	// "running" or "stopped", and the override stays in effect until the annotation is removed.
+	AnnotationScheduleOverride = KAITOPrefix + "schedule-override"
+
+	// PresetUpgradeLatest is the value of the upgrade-preset annotation for upgrading to the newest released tag.
+	PresetUpgradeLatest = "latest"
 )
 
-// GetWorkspaceRuntimeName returns the runtime name of the workspace.
+// defaultRuntimeNames are the runtimes tried in order for the workspaces that do not select a runtime.
+var defaultRuntimeNames = []model.RuntimeName{model.RuntimeNameVLLM, model.RuntimeNameHuggingfaceTransformers}
+
+// GetWorkspaceRuntimeName returns the runtime name of the workspace, which is the runtime of the inference spec, or the
+// runtime of the annotation, or else the first default runtime that can serve the preset model with the pinned version
+// of the preset.
 func GetWorkspaceRuntimeName(ws *Workspace) model.RuntimeName {
 	if ws == nil {
 		panic("workspace is nil")
 	}
 
+	if ws.Inference != nil && ws.Inference.Runtime != "" {
+		return model.RuntimeName(ws.Inference.Runtime)
+	}
+
 	if !featuregates.FeatureGates[consts.FeatureFlagVLLM] {
 		return model.RuntimeNameHuggingfaceTransformers
 	}
 
-	runtime := model.RuntimeNameVLLM
-	name := ws.Annotations[AnnotationWorkspaceRuntime]
-	switch name {
-	case string(model.RuntimeNameHuggingfaceTransformers):
-		runtime = model.RuntimeNameHuggingfaceTransformers
-	case string(model.RuntimeNameVLLM):
-		runtime = model.RuntimeNameVLLM
+	name := model.RuntimeName(ws.Annotations[AnnotationWorkspaceRuntime])
+	if model.GetRuntime(name) != nil {
+		return name
 	}
 
-	return runtime
+	var presetModel model.Model
+	if ws.Inference != nil && ws.Inference.Preset != nil {
+		presetModel = plugin.KaitoModelRegister.Get(string(ws.Inference.Preset.Name))
+	}
+	for _, name := range defaultRuntimeNames {
+		if presetModel == nil {
+			return name
+		}
+		if rt := model.GetRuntime(name); RuntimeSupportsModel(rt, presetModel) && PresetVersionSupportsRuntime(ws.Inference.Preset, presetModel, rt) {
+			return name
+		}
+	}
+	return model.RuntimeNameVLLM
+}
+
+// RuntimeSupportsModel returns whether the runtime can serve the preset model, including across nodes if the model
+// uses distributed inference.
+func RuntimeSupportsModel(runtime model.Runtime, m model.Model) bool {
+	if !runtime.SupportsModel(m.GetInferenceParameters()) {
+		return false
+	}
+	return !m.SupportDistributedInference() || runtime.SupportsFeature(model.RuntimeFeatureDistributedInference)
+}
+
+// PresetVersionSupportsRuntime returns whether the image of the pinned version of the preset can serve the model with
+// the runtime. Runtimes that do not serve the model from the image of the preset support all versions.
+func PresetVersionSupportsRuntime(preset *PresetSpec, m model.Model, runtime model.Runtime) bool {
+	version := preset.PresetOptions.Version
+	if version == "" || runtime.GetImage(m.GetInferenceParameters()) != "" {
+		return true
+	}
+	return model.PresetTagSupportsRuntime(string(preset.Name), version, runtime.Name())
 }
@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+)
+
+// SetDefaults for the ModelPreset
+func (m *ModelPreset) SetDefaults(_ context.Context) {
+}
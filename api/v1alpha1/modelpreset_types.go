@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ModelPresetSpec describes a model that can be referenced by name in the preset of a workspace, like the presets
+// built into Kaito.
+type ModelPresetSpec struct {
+	// ModelFamilyName is the name of the model family, e.g., Llama3.
+	ModelFamilyName string `json:"modelFamilyName"`
+	// Inference is the configuration used to run inference with the model.
+	Inference ModelPresetParams `json:"inference"`
+	// Tuning is the configuration used to fine tune the model. If not specified, the model does not support tuning.
+	// +optional
+	Tuning *ModelPresetParams `json:"tuning,omitempty"`
+	// SupportDistributedInference specifies whether the model can run inference on multiple nodes, in which case
+	// the inference workload is a StatefulSet using the torch elastic runtime.
+	// +optional
+	SupportDistributedInference bool `json:"supportDistributedInference,omitempty"`
+}
+
+// ModelPresetParams are the requirements and the runtime parameters of a model.
+type ModelPresetParams struct {
+	// Tag is the tag of the model image, which is <preset registry>/kaito-<preset name>:<tag> for public images.
+	Tag string `json:"tag"`
+	// ImageAccessMode specifies whether the model image is accessible via public or private registry.
+	// This field defaults to "public" if not specified.
+	// +kubebuilder:validation:Enum=public;private
+	// +kubebuilder:default:=public
+	// +optional
+	ImageAccessMode ModelImageAccessMode `json:"imageAccessMode,omitempty"`
+	// DiskStorageRequirement is the disk storage required for the model, e.g., 100Gi.
+	DiskStorageRequirement string `json:"diskStorageRequirement"`
+	// GPUCountRequirement is the number of GPUs required for the model.
+	GPUCountRequirement string `json:"gpuCountRequirement"`
+	// TotalGPUMemoryRequirement is the total GPU memory required for the model, e.g., 16Gi.
+	TotalGPUMemoryRequirement string `json:"totalGPUMemoryRequirement"`
+	// PerGPUMemoryRequirement is the GPU memory required per GPU, e.g., 16Gi. 0Gi means the model can be split
+	// across GPUs without a per GPU requirement.
+	// +optional
+	PerGPUMemoryRequirement string `json:"perGPUMemoryRequirement,omitempty"`
+	// TuningPerGPUMemoryRequirement is the minimum GPU memory in Gi per tuning method with a batch size of 1.
+	// +optional
+	TuningPerGPUMemoryRequirement map[string]int `json:"tuningPerGPUMemoryRequirement,omitempty"`
+	// WorldSize is the number of processes required for distributed inference.
+	// +optional
+	WorldSize int `json:"worldSize,omitempty"`
+	// ReadinessTimeout is the maximum duration for the workload to become ready, including pulling the image.
+	// This field defaults to 30m if not specified.
+	// +optional
+	ReadinessTimeout *metav1.Duration `json:"readinessTimeout,omitempty"`
+	// Runtime is the parameters of the runtimes that serve the model.
+	// +optional
+	Runtime ModelPresetRuntimeParams `json:"runtime,omitempty"`
+}
+
+// ModelPresetRuntimeParams are the parameters of the runtimes that serve a model.
+type ModelPresetRuntimeParams struct {
+	// Transformers is the parameters of the Hugging Face transformers runtime.
+	// +optional
+	Transformers *TransformersRuntimeParams `json:"transformers,omitempty"`
+	// VLLM is the parameters of the vLLM runtime.
+	// +optional
+	VLLM *VLLMRuntimeParams `json:"vllm,omitempty"`
+	// DisableTensorParallelism disables tensor parallelism across the GPUs of a node.
+	// +optional
+	DisableTensorParallelism bool `json:"disableTensorParallelism,omitempty"`
+}
+
+// TransformersRuntimeParams are the parameters of the Hugging Face transformers runtime.
+type TransformersRuntimeParams struct {
+	// BaseCommand is the command that launches the model, e.g., "accelerate launch".
+	BaseCommand string `json:"baseCommand"`
+	// TorchRunParams are the parameters of the torchrun command.
+	// +optional
+	TorchRunParams map[string]string `json:"torchRunParams,omitempty"`
+	// TorchRunRdzvParams are the rendezvous parameters of the torchrun command for distributed inference.
+	// +optional
+	TorchRunRdzvParams map[string]string `json:"torchRunRdzvParams,omitempty"`
+	// InferenceMainFile is the main file for inference.
+	// +optional
+	InferenceMainFile string `json:"inferenceMainFile,omitempty"`
+	// ModelRunParams are the parameters passed to the main file.
+	// +optional
+	ModelRunParams map[string]string `json:"modelRunParams,omitempty"`
+}
+
+// VLLMRuntimeParams are the parameters of the vLLM runtime.
+type VLLMRuntimeParams struct {
+	// BaseCommand is the command that launches the vLLM server.
+	BaseCommand string `json:"baseCommand"`
+	// ModelName is the model name used in the OpenAI serving API.
+	// +optional
+	ModelName string `json:"modelName,omitempty"`
+	// DistributionParams are the parameters for distributed inference.
+	// +optional
+	DistributionParams map[string]string `json:"distributionParams,omitempty"`
+	// ModelRunParams are the parameters passed to the vLLM server.
+	// +optional
+	ModelRunParams map[string]string `json:"modelRunParams,omitempty"`
+}
+
+// ModelPresetStatus defines the observed state of ModelPreset
+type ModelPresetStatus struct {
+	// Conditions report the current conditions of the model preset.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ModelPreset is the Schema for the modelpresets API. The name of the ModelPreset is the preset name referenced by
+// workspaces.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=modelpresets,scope=Cluster,categories=workspace
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Family",type="string",JSONPath=".spec.modelFamilyName",description=""
+// +kubebuilder:printcolumn:name="Tag",type="string",JSONPath=".spec.inference.tag",description=""
+// +kubebuilder:printcolumn:name="Registered",type="string",JSONPath=".status.conditions[?(@.type==\"Registered\")].status",description=""
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+type ModelPreset struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModelPresetSpec   `json:"spec,omitempty"`
+	Status ModelPresetStatus `json:"status,omitempty"`
+}
+
+// ModelPresetList contains a list of ModelPreset
+// +kubebuilder:object:root=true
+type ModelPresetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ModelPreset `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ModelPreset{}, &ModelPresetList{})
+}
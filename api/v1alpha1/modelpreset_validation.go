@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"knative.dev/pkg/apis"
+)
+
+func (m *ModelPreset) SupportedVerbs() []admissionregistrationv1.OperationType {
+	return []admissionregistrationv1.OperationType{
+		admissionregistrationv1.Create,
+		admissionregistrationv1.Update,
+	}
+}
+
+func (m *ModelPreset) Validate(ctx context.Context) (errs *apis.FieldError) {
+	// Preset names may contain dots, e.g., phi-3.5-mini-instruct.
+	errmsgs := validation.IsDNS1123Subdomain(m.Name)
+	if len(errmsgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "name"))
+	}
+	if apis.GetBaseline(ctx) == nil {
+		klog.InfoS("Validate creation", "modelpreset", m.Name)
+		// The presets built into Kaito cannot be replaced. The name of a deleted ModelPreset can be reused even if
+		// the controller has not unregistered it yet.
+		if plugin.KaitoModelRegister.IsBuiltIn(m.Name) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("preset %s is built into Kaito", m.Name), "name"))
+		}
+	} else {
+		klog.InfoS("Validate update", "modelpreset", m.Name)
+	}
+	return errs.Also(m.Spec.validate().ViaField("spec"))
+}
+
+func (s *ModelPresetSpec) validate() (errs *apis.FieldError) {
+	if s.ModelFamilyName == "" {
+		errs = errs.Also(apis.ErrMissingField("modelFamilyName"))
+	}
+	errs = errs.Also(s.Inference.validate().ViaField("inference"))
+	if s.Tuning != nil {
+		errs = errs.Also(s.Tuning.validate().ViaField("tuning"))
+		// The tuning job is always run with the transformers runtime.
+		if s.Tuning.Runtime.Transformers == nil {
+			errs = errs.Also(apis.ErrMissingField("runtime.transformers").ViaField("tuning"))
+		}
+	}
+	return errs
+}
+
+func (p *ModelPresetParams) validate() (errs *apis.FieldError) {
+	if p.Tag == "" {
+		errs = errs.Also(apis.ErrMissingField("tag"))
+	}
+	for field, value := range map[string]string{
+		"diskStorageRequirement":    p.DiskStorageRequirement,
+		"totalGPUMemoryRequirement": p.TotalGPUMemoryRequirement,
+		"perGPUMemoryRequirement":   p.PerGPUMemoryRequirement,
+	} {
+		if value == "" {
+			if field != "perGPUMemoryRequirement" {
+				errs = errs.Also(apis.ErrMissingField(field))
+			}
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s is not a valid quantity: %v", value, err), field))
+		}
+	}
+	if count, err := strconv.Atoi(p.GPUCountRequirement); err != nil || count < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be a non-negative integer", p.GPUCountRequirement), "gpuCountRequirement"))
+	}
+	for method, memory := range p.TuningPerGPUMemoryRequirement {
+		if memory <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%d must be positive", memory), "tuningPerGPUMemoryRequirement", method))
+		}
+	}
+	if p.WorldSize < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%d must not be negative", p.WorldSize), "worldSize"))
+	}
+	if p.ReadinessTimeout != nil && p.ReadinessTimeout.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be positive", p.ReadinessTimeout.Duration), "readinessTimeout"))
+	}
+
+	if p.Runtime.Transformers == nil && p.Runtime.VLLM == nil {
+		errs = errs.Also(apis.ErrMissingOneOf("runtime.transformers", "runtime.vllm"))
+	}
+	if p.Runtime.Transformers != nil && p.Runtime.Transformers.BaseCommand == "" {
+		errs = errs.Also(apis.ErrMissingField("runtime.transformers.baseCommand"))
+	}
+	if p.Runtime.VLLM != nil && p.Runtime.VLLM.BaseCommand == "" {
+		errs = errs.Also(apis.ErrMissingField("runtime.vllm.baseCommand"))
+	}
+	return errs
+}
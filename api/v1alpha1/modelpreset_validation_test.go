@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func validModelPreset(name string) *ModelPreset {
+	return &ModelPreset{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: ModelPresetSpec{
+			ModelFamilyName: "Custom",
+			Inference: ModelPresetParams{
+				Tag:                       "0.0.1",
+				DiskStorageRequirement:    "50Gi",
+				GPUCountRequirement:       "1",
+				TotalGPUMemoryRequirement: "16Gi",
+				Runtime: ModelPresetRuntimeParams{
+					VLLM: &VLLMRuntimeParams{BaseCommand: "python3 /workspace/vllm/inference_api.py"},
+				},
+			},
+		},
+	}
+}
+
+func TestModelPresetValidate(t *testing.T) {
+	RegisterValidationTestModels()
+	// A ModelPreset that has been deleted but not yet unregistered by the controller.
+	plugin.KaitoModelRegister.Register(&plugin.Registration{Name: "custom-deleted", Instance: &testModel{}, Custom: true})
+	defer plugin.KaitoModelRegister.Unregister("custom-deleted")
+	tests := []struct {
+		name       string
+		preset     func() *ModelPreset
+		update     bool
+		errContent string // Content expected error to include, if any
+	}{
+		{
+			name:   "Valid model preset",
+			preset: func() *ModelPreset { return validModelPreset("custom-7b.v1") },
+		},
+		{
+			name:       "Invalid name",
+			preset:     func() *ModelPreset { return validModelPreset("Custom_7B") },
+			errContent: "name",
+		},
+		{
+			name:       "Name of a registered preset",
+			preset:     func() *ModelPreset { return validModelPreset("test-validation") },
+			errContent: "preset test-validation is built into Kaito",
+		},
+		{
+			name:   "Name of a registered ModelPreset",
+			preset: func() *ModelPreset { return validModelPreset("custom-deleted") },
+		},
+		{
+			name:   "Update of a registered preset",
+			preset: func() *ModelPreset { return validModelPreset("test-validation") },
+			update: true,
+		},
+		{
+			name: "Invalid quantity",
+			preset: func() *ModelPreset {
+				p := validModelPreset("custom")
+				p.Spec.Inference.TotalGPUMemoryRequirement = "16 gigabytes"
+				return p
+			},
+			errContent: "spec.inference.totalGPUMemoryRequirement",
+		},
+		{
+			name: "Invalid GPU count",
+			preset: func() *ModelPreset {
+				p := validModelPreset("custom")
+				p.Spec.Inference.GPUCountRequirement = "two"
+				return p
+			},
+			errContent: "spec.inference.gpuCountRequirement",
+		},
+		{
+			name: "Missing tag",
+			preset: func() *ModelPreset {
+				p := validModelPreset("custom")
+				p.Spec.Inference.Tag = ""
+				return p
+			},
+			errContent: "spec.inference.tag",
+		},
+		{
+			name: "No runtime",
+			preset: func() *ModelPreset {
+				p := validModelPreset("custom")
+				p.Spec.Inference.Runtime.VLLM = nil
+				return p
+			},
+			errContent: "expected exactly one, got neither",
+		},
+		{
+			name: "Tuning without the transformers runtime",
+			preset: func() *ModelPreset {
+				p := validModelPreset("custom")
+				p.Spec.Tuning = p.Spec.Inference.DeepCopy()
+				return p
+			},
+			errContent: "spec.tuning.runtime.transformers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset := tt.preset()
+			ctx := context.Background()
+			if tt.update {
+				ctx = apis.WithinUpdate(ctx, preset.DeepCopy())
+			}
+			errs := preset.Validate(ctx)
+			if tt.errContent == "" {
+				if errs != nil {
+					t.Errorf("Validate() returned unexpected error: %v", errs)
+				}
+				return
+			}
+			if errs == nil || !strings.Contains(errs.Error(), tt.errContent) {
+				t.Errorf("Validate() error = %v, want error containing %s", errs, tt.errContent)
+			}
+		})
+	}
+}
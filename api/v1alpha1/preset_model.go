@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaito-project/kaito/pkg/huggingface"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetInferencePresetModel returns the model of the inference preset of the workspace. For the generic huggingface
+// preset, the requirements of the model are read from the model repository named by the workspace.
+func GetInferencePresetModel(ctx context.Context, c client.Reader, wObj *Workspace) (model.Model, error) {
+	return getPresetModel(ctx, c, wObj.Namespace, wObj.Inference.Preset)
+}
+
+func getPresetModel(ctx context.Context, c client.Reader, namespace string, preset *PresetSpec) (model.Model, error) {
+	presetModel := plugin.KaitoModelRegister.MustGet(string(preset.Name))
+	hf := preset.PresetOptions.HuggingFace
+	if string(preset.Name) != huggingface.PresetName || hf == nil {
+		return presetModel, nil
+	}
+
+	var token string
+	if hf.TokenSecretRef != nil {
+		if c == nil {
+			return nil, fmt.Errorf("no client to read the token secret %s", hf.TokenSecretRef.Name)
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: hf.TokenSecretRef.Name}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get the token secret %s: %w", hf.TokenSecretRef.Name, err)
+		}
+		token = string(secret.Data[hf.TokenSecretRef.Key])
+	}
+	info, err := huggingface.GetModelInfo(ctx, hf.ModelID, hf.Revision, token)
+	if err != nil {
+		return nil, err
+	}
+	return huggingface.NewModel(presetModel, hf.ModelID, info), nil
+}
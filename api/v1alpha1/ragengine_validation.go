@@ -13,6 +13,7 @@ import (
 
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -30,18 +31,29 @@ func (w *RAGEngine) Validate(ctx context.Context) (errs *apis.FieldError) {
 	base := apis.GetBaseline(ctx)
 	if base == nil {
 		klog.InfoS("Validate creation", "ragengine", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
-		errs = errs.Also(w.validateCreate().ViaField("spec"))
+		errs = errs.Also(w.validateCreate().ViaField("spec"), w.validateGPUQuota(ctx).ViaField("spec.compute"))
 	} else {
 		klog.InfoS("Validate update", "ragengine", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
 		old := base.(*RAGEngine)
 		errs = errs.Also(
 			w.validateCreate().ViaField("spec"),
-			w.Spec.Compute.validateUpdate(old.Spec.Compute).ViaField("resource"),
+			w.Spec.Compute.validateUpdate(old.Spec.Compute, nil).ViaField("resource"),
 		)
+		if w.Spec.Compute != nil && old.Spec.Compute != nil && lo.FromPtr(w.Spec.Compute.Count) > lo.FromPtr(old.Spec.Compute.Count) {
+			errs = errs.Also(w.validateGPUQuota(ctx).ViaField("spec.compute"))
+		}
 	}
 	return errs
 }
 
+// validateGPUQuota checks the nodes of the RAGEngine against the GPU quotas of its namespace.
+func (w *RAGEngine) validateGPUQuota(ctx context.Context) (errs *apis.FieldError) {
+	if w.Spec == nil || w.Spec.Compute == nil {
+		return nil
+	}
+	return validateGPUQuota(ctx, w, []string{w.Spec.Compute.InstanceType}, lo.FromPtr(w.Spec.Compute.Count))
+}
+
 func (w *RAGEngine) validateCreate() (errs *apis.FieldError) {
 	if w.Spec.InferenceService == nil {
 		errs = errs.Also(apis.ErrGeneric("InferenceService must be specified", ""))
@@ -86,6 +98,10 @@ func (r *ResourceSpec) validateRAGCreate() (errs *apis.FieldError) {
 		}
 	}
 
+	if len(r.FallbackInstanceTypes) > 0 {
+		errs = errs.Also(apis.ErrGeneric("Fallback instance types are not supported for RAGEngine", "fallbackInstanceTypes"))
+	}
+
 	// Validate labelSelector
 	if _, err := metav1.LabelSelectorAsMap(r.LabelSelector); err != nil {
 		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
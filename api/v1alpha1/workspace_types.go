@@ -11,6 +11,9 @@ import (
 const (
 	ModelImageAccessModePublic  ModelImageAccessMode = "public"
 	ModelImageAccessModePrivate ModelImageAccessMode = "private"
+
+	ImageFormatContainer ImageFormat = "container"
+	ImageFormatArtifact  ImageFormat = "artifact"
 )
 
 // ResourceSpec describes the resource requirement of running the workload.
@@ -30,6 +33,13 @@ type ResourceSpec struct {
 	// +kubebuilder:default:="Standard_NC12s_v3"
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// FallbackInstanceTypes is an ordered list of alternative GPU node SKUs. If new nodes cannot be provisioned because
+	// the current instance type is unavailable, the controller releases the nodes of the current instance type and
+	// retries with the next instance type in the list. The instance type in use is reported in the workspace status.
+	// Previous instance types are not retried, even if they become available again.
+	// +optional
+	FallbackInstanceTypes []string `json:"fallbackInstanceTypes,omitempty"`
+
 	// LabelSelector specifies the required labels for the GPU nodes.
 	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
 
@@ -62,6 +72,28 @@ type PresetOptions struct {
 	// ImagePullSecrets is a list of secret names in the same namespace used for pulling the model image.
 	// +optional
 	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	// HuggingFace is the model repository served by the generic huggingface preset. The weights of the model are
+	// downloaded when the inference workload starts, so no model image is needed.
+	// +optional
+	HuggingFace *HuggingFaceModelSpec `json:"huggingFace,omitempty"`
+	// Version pins the tag of the preset image, which is one of the released tags of the preset listed in
+	// supported_models.yaml. This field defaults to the tag of the preset in the Kaito release if not specified, so
+	// that the image changes when Kaito is upgraded.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// HuggingFaceModelSpec describes a model repository on the Hugging Face Hub.
+type HuggingFaceModelSpec struct {
+	// ModelID is the ID of the model repository, e.g., mistralai/Mistral-7B-Instruct-v0.3.
+	ModelID string `json:"modelID"`
+	// Revision is the branch, tag or commit of the model repository. This field defaults to "main" if not specified.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// TokenSecretRef selects the key of a secret in the namespace of the workspace that holds the access token
+	// for gated or private model repositories.
+	// +optional
+	TokenSecretRef *v1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
 }
 
 // PresetSpec provides the information for rendering preset configurations to run the model inference service.
@@ -82,10 +114,99 @@ type InferenceSpec struct {
 	// +kubebuilder:validation:Schemaless
 	// +optional
 	Template *v1.PodTemplateSpec `json:"template,omitempty"`
+	// Runtime is the runtime serving the preset model, e.g., vllm or transformers. It takes precedence over the
+	// kaito.sh/runtime annotation. If not specified, vllm is used for the presets it supports and transformers otherwise.
+	// +optional
+	Runtime string `json:"runtime,omitempty"`
 	// Adapters are integrated into the base model for inference.
 	// Users can specify multiple adapters for the model and the respective weight of using each of them.
 	// +optional
 	Adapters []AdapterSpec `json:"adapters,omitempty"`
+	// Autoscaling enables the controller to adjust Resource.Count, hence the number of inference replicas and
+	// GPU nodes, based on the metrics reported by the inference runtime.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// IdlePolicy enables scaling the inference workload to zero when it does not receive requests. Requests must be
+	// sent to the activator service of the workspace, which brings the workspace back when they arrive.
+	// +optional
+	IdlePolicy *IdlePolicySpec `json:"idlePolicy,omitempty"`
+	// Schedule restricts the inference workload to recurring time windows. Outside the windows the inference workload
+	// is scaled to zero and the nodes created for the workspace are deleted, while the workspace itself is kept.
+	// +optional
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+}
+
+// IdlePolicySpec describes when an idle inference workload is scaled to zero.
+type IdlePolicySpec struct {
+	// IdleMinutes is the number of minutes without requests after which the inference workload is scaled to zero
+	// and the nodes created for the workspace are deleted.
+	// +kubebuilder:validation:Minimum=1
+	IdleMinutes int `json:"idleMinutes"`
+}
+
+// ScheduleSpec describes the time windows in which the inference workload runs.
+type ScheduleSpec struct {
+	// Windows are the recurring time windows in which the inference workload runs.
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+	// TimeZone is the IANA name of the time zone the windows are defined in, e.g., "Europe/Berlin".
+	// This field defaults to UTC if not specified.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// LeadMinutes is the number of minutes before the start of a window at which the nodes are provisioned and the
+	// model is deployed, so that the inference service is ready when the window starts.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LeadMinutes int `json:"leadMinutes,omitempty"`
+}
+
+// ScheduleWindow is a recurring time window.
+type ScheduleWindow struct {
+	// Start is a cron expression in the standard five-field format at which the window starts,
+	// e.g., "0 8 * * 1-5" for 08:00 from Monday to Friday.
+	Start string `json:"start"`
+	// Duration is the length of the window, e.g., "12h".
+	Duration metav1.Duration `json:"duration"`
+}
+
+const (
+	// ScheduleOverrideRunning keeps the inference workload running regardless of the schedule.
+	ScheduleOverrideRunning = "running"
+	// ScheduleOverrideStopped keeps the inference workload stopped regardless of the schedule.
+	ScheduleOverrideStopped = "stopped"
+)
+
+// +kubebuilder:validation:Enum=PendingRequests;KVCacheUsage
+type AutoscalingMetricType string
+
+const (
+	// AutoscalingMetricPendingRequests scales on the number of requests waiting to be scheduled by the runtime.
+	AutoscalingMetricPendingRequests AutoscalingMetricType = "PendingRequests"
+	// AutoscalingMetricKVCacheUsage scales on the percentage of GPU KV-cache blocks in use.
+	AutoscalingMetricKVCacheUsage AutoscalingMetricType = "KVCacheUsage"
+)
+
+// AutoscalingSpec describes how the inference workload is scaled based on the runtime metrics.
+type AutoscalingSpec struct {
+	// MinCount is the lower limit of Resource.Count.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinCount int `json:"minCount,omitempty"`
+	// MaxCount is the upper limit of Resource.Count.
+	// +kubebuilder:validation:Minimum=1
+	MaxCount int `json:"maxCount"`
+	// Metric is the runtime metric used to compute the desired count.
+	Metric AutoscalingMetricType `json:"metric"`
+	// TargetValue is the desired average value of the metric per replica. It is the number of waiting
+	// requests for PendingRequests and a percentage between 1 and 100 for KVCacheUsage.
+	// +kubebuilder:validation:Minimum=1
+	TargetValue int `json:"targetValue"`
+	// ScaleDownStabilizationSeconds is the minimum time since the last scaling before the count can be reduced.
+	// This field defaults to 300 if not specified.
+	// +kubebuilder:default:=300
+	// +optional
+	ScaleDownStabilizationSeconds *int32 `json:"scaleDownStabilizationSeconds,omitempty"`
 }
 
 type AdapterSpec struct {
@@ -98,6 +219,12 @@ type AdapterSpec struct {
 	Strength *string `json:"strength,omitempty"`
 }
 
+// ImageFormat is the format of the image that contains the data. A container image keeps the data in the `data`
+// directory of its filesystem, while an OCI artifact keeps each file of the data in its own layer and is pushed and
+// pulled with the oras CLI, without running the image.
+// +kubebuilder:validation:Enum=container;artifact
+type ImageFormat string
+
 type DataSource struct {
 	// The name of the dataset. The same name will be used as a container name.
 	// It must be a valid DNS subdomain value,
@@ -110,6 +237,10 @@ type DataSource struct {
 	// +kubebuilder:validation:Schemaless
 	// +optional
 	Volume *v1.VolumeSource `json:"volumeSource,omitempty"`
+	// SubPath is the directory in the volume that contains the data. The data is read from the root of the volume
+	// if it is not specified. It can only be used with the volume.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
 	// The name of the image that contains the source data. The assumption is that the source data locates in the
 	// `data` directory in the image.
 	// +optional
@@ -117,10 +248,15 @@ type DataSource struct {
 	// ImagePullSecrets is a list of secret names in the same namespace used for pulling the data image.
 	// +optional
 	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	// ImageFormat is the format of the image, either container or artifact. This field defaults to "container"
+	// if not specified. Only one image pull secret can be used to pull an artifact.
+	// +optional
+	ImageFormat ImageFormat `json:"imageFormat,omitempty"`
 }
 
 type DataDestination struct {
-	// The mounted volume that is used to save the output data.
+	// The mounted volume that is used to save the output data. The results of each run of the tuning job are saved in
+	// the `<workspace name>/<workspace revision>` directory of the volume.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	// +optional
@@ -132,6 +268,11 @@ type DataDestination struct {
 	// information that is needed for running `docker push`.
 	// +optional
 	ImagePushSecret string `json:"imagePushSecret,omitempty"`
+	// ImageFormat is the format of the image, either container or artifact. This field defaults to "container"
+	// if not specified. A container image is built and pushed by a privileged container, while an artifact is pushed
+	// by an unprivileged one.
+	// +optional
+	ImageFormat ImageFormat `json:"imageFormat,omitempty"`
 }
 
 type TuningMethod string
@@ -157,10 +298,176 @@ type TuningSpec struct {
 	Input *DataSource `json:"input"`
 	// Output specified where to store the tuning output.
 	Output *DataDestination `json:"output"`
+	// Checkpoint configures the periodic checkpoints of the tuning job. If specified, a tuning pod that is evicted
+	// or whose node is lost is recreated, and resumes the training from the latest checkpoint.
+	// +optional
+	Checkpoint *CheckpointSpec `json:"checkpoint,omitempty"`
+}
+
+// CheckpointSpec describes how often and where the tuning job saves its checkpoints.
+type CheckpointSpec struct {
+	// Interval is the number of training steps between two checkpoints.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=500
+	// +optional
+	Interval int32 `json:"interval,omitempty"`
+	// Volume is the volume where the checkpoints are saved, which must outlive the tuning pod, e.g., a persistent
+	// volume claim. The checkpoints of each run are saved in the `<workspace name>/<workspace revision>/checkpoints`
+	// directory of the volume.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Volume *v1.VolumeSource `json:"volumeSource"`
+}
+
+// WorkspacePhase is a label for the lifecycle stage of a workspace.
+// +kubebuilder:validation:Enum=ProvisioningNodes;InstallingPlugins;Deploying;WaitingReady;Ready;ScaledToZero;Stopped;Failed
+type WorkspacePhase string
+
+const (
+	// WorkspacePhaseProvisioningNodes means the controller is waiting for new GPU nodes to be provisioned.
+	WorkspacePhaseProvisioningNodes WorkspacePhase = "ProvisioningNodes"
+	// WorkspacePhaseInstallingPlugins means the nodes are provisioned and the GPU device plugins are being installed.
+	WorkspacePhaseInstallingPlugins WorkspacePhase = "InstallingPlugins"
+	// WorkspacePhaseDeploying means the workload is being created or updated.
+	WorkspacePhaseDeploying WorkspacePhase = "Deploying"
+	// WorkspacePhaseWaitingReady means the workload is created and the controller is waiting for it to become ready.
+	WorkspacePhaseWaitingReady WorkspacePhase = "WaitingReady"
+	// WorkspacePhaseReady means the workload is up and running.
+	WorkspacePhaseReady WorkspacePhase = "Ready"
+	// WorkspacePhaseScaledToZero means the workspace is idle, its workload has no replica and its nodes are released.
+	WorkspacePhaseScaledToZero WorkspacePhase = "ScaledToZero"
+	// WorkspacePhaseStopped means the workspace is outside its schedule, its workload has no replica and its nodes are
+	// released.
+	WorkspacePhaseStopped WorkspacePhase = "Stopped"
+	// WorkspacePhaseFailed means the workspace cannot make progress without user intervention.
+	WorkspacePhaseFailed WorkspacePhase = "Failed"
+)
+
+// InferenceStatus reports the resolved configuration of the inference service.
+type InferenceStatus struct {
+	// Endpoint is the in-cluster URL of the inference service.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Port is the port the inference service listens on.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Runtime is the effective runtime serving the preset model, e.g., transformers or vllm.
+	// +optional
+	Runtime string `json:"runtime,omitempty"`
+
+	// Image is the image of the inference container.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// PresetTag is the tag of the preset model image.
+	// +optional
+	PresetTag string `json:"presetTag,omitempty"`
+
+	// Adapters is the list of adapters loaded into the inference service.
+	// +optional
+	Adapters []string `json:"adapters,omitempty"`
+}
+
+// TuningStatus reports the results of the tuning job.
+type TuningStatus struct {
+	// OutputPath is the directory in the output volume that contains the results of the last completed tuning job.
+	// It can be used as the subPath of a data source that mounts the same volume.
+	// +optional
+	OutputPath string `json:"outputPath,omitempty"`
+
+	// Progress reports the progress of the running tuning job, as scraped from the metrics server of the job.
+	// +optional
+	Progress *TuningProgress `json:"progress,omitempty"`
+
+	// ResumeCount is the number of times the tuning job resumed from a checkpoint after its pod was recreated.
+	// +optional
+	ResumeCount int32 `json:"resumeCount,omitempty"`
+}
+
+// TuningProgress reports the progress of the running tuning job. Losses and rates are strings because float
+// fields are discouraged in the API.
+type TuningProgress struct {
+	// Epoch is the current epoch, which is fractional within an epoch, e.g., "1.50".
+	// +optional
+	Epoch string `json:"epoch,omitempty"`
+
+	// TotalEpochs is the number of epochs of the tuning job.
+	// +optional
+	TotalEpochs int64 `json:"totalEpochs,omitempty"`
+
+	// Step is the current training step.
+	// +optional
+	Step int64 `json:"step,omitempty"`
+
+	// TotalSteps is the number of training steps of the tuning job.
+	// +optional
+	TotalSteps int64 `json:"totalSteps,omitempty"`
+
+	// TrainingLoss is the latest training loss.
+	// +optional
+	TrainingLoss string `json:"trainingLoss,omitempty"`
+
+	// EvalLoss is the latest evaluation loss, if the tuning job has an evaluation dataset.
+	// +optional
+	EvalLoss string `json:"evalLoss,omitempty"`
+
+	// LearningRate is the current learning rate.
+	// +optional
+	LearningRate string `json:"learningRate,omitempty"`
+
+	// StepsPerSecond is the average training throughput.
+	// +optional
+	StepsPerSecond string `json:"stepsPerSecond,omitempty"`
+
+	// EstimatedTimeRemaining is the estimated time until the training completes.
+	// +optional
+	EstimatedTimeRemaining *metav1.Duration `json:"estimatedTimeRemaining,omitempty"`
+
+	// LastCheckpoint is the name of the latest checkpoint saved in the output directory, e.g., "checkpoint-500".
+	// +optional
+	LastCheckpoint string `json:"lastCheckpoint,omitempty"`
 }
 
 // WorkspaceStatus defines the observed state of Workspace
 type WorkspaceStatus struct {
+	// ObservedGeneration is the most recent generation of the workspace reflected by the status.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is the current lifecycle stage of the workspace.
+	// +optional
+	Phase WorkspacePhase `json:"phase,omitempty"`
+
+	// InstanceType is the GPU node SKU chosen from InstanceType and FallbackInstanceTypes to provision the nodes.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// ReadyReplicas is the number of inference replicas that are ready to serve requests.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// DesiredReplicas is the number of inference replicas requested for the workload.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// Inference reports the resolved configuration of the inference service.
+	// +optional
+	Inference *InferenceStatus `json:"inference,omitempty"`
+
+	// Tuning reports the results of the tuning job.
+	// +optional
+	Tuning *TuningStatus `json:"tuning,omitempty"`
+
+	// LastScaleTime is the last time the count of the workspace was changed by the autoscaler.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// NextScheduleTransitionTime is the next time the inference workload is started or stopped by the schedule.
+	// +optional
+	NextScheduleTransitionTime *metav1.Time `json:"nextScheduleTransitionTime,omitempty"`
+
 	// WorkerNodes is the list of nodes chosen to run the workload based on the workspace resource requirement.
 	// +optional
 	WorkerNodes []string `json:"workerNodes,omitempty"`
@@ -168,18 +475,39 @@ type WorkspaceStatus struct {
 	// Conditions report the current conditions of the workspace.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Selector is the label selector of the inference pods, which is reported by the scale subresource.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+}
+
+// ScaleSpec holds the fields of the workspace that are changed through the scale subresource, whose replicas must be
+// under .spec.
+type ScaleSpec struct {
+	// Replicas is the number of inference replicas, which is the node count of the workspace. The controller sets it
+	// from resource.count, and applies a change made through the scale subresource, e.g., by an HPA or KEDA, to
+	// resource.count.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
 }
 
 // Workspace is the Schema for the workspaces API
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.desiredReplicas,selectorpath=.status.selector
 // +kubebuilder:resource:path=workspaces,scope=Namespaced,categories=workspace,shortName={wk,wks}
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".resource.instanceType",description=""
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description=""
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas",description=""
+// +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".status.desiredReplicas",description=""
 // +kubebuilder:printcolumn:name="ResourceReady",type="string",JSONPath=".status.conditions[?(@.type==\"ResourceReady\")].status",description=""
 // +kubebuilder:printcolumn:name="InferenceReady",type="string",JSONPath=".status.conditions[?(@.type==\"InferenceReady\")].status",description=""
 // +kubebuilder:printcolumn:name="JobStarted",type="string",JSONPath=".status.conditions[?(@.type==\"JobStarted\")].status",description=""
 // +kubebuilder:printcolumn:name="WorkspaceSucceeded",type="string",JSONPath=".status.conditions[?(@.type==\"WorkspaceSucceeded\")].status",description=""
+// +kubebuilder:printcolumn:name="Runtime",type="string",JSONPath=".status.inference.runtime",priority=1,description=""
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.inference.image",priority=1,description=""
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".status.inference.endpoint",priority=1,description=""
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 type Workspace struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -188,6 +516,7 @@ type Workspace struct {
 	Resource  ResourceSpec    `json:"resource,omitempty"`
 	Inference *InferenceSpec  `json:"inference,omitempty"`
 	Tuning    *TuningSpec     `json:"tuning,omitempty"`
+	Spec      *ScaleSpec      `json:"spec,omitempty"`
 	Status    WorkspaceStatus `json:"status,omitempty"`
 }
 
@@ -202,3 +531,26 @@ type WorkspaceList struct {
 func init() {
 	SchemeBuilder.Register(&Workspace{}, &WorkspaceList{})
 }
+
+// GetWorkspaceInstanceType returns the instance type used to provision the nodes of the workspace. It is the
+// instance type recorded in the status if it is still one of the candidates in the resource spec, and the
+// primary instance type otherwise.
+func GetWorkspaceInstanceType(ws *Workspace) string {
+	if ws.Status.InstanceType != "" && ws.Status.InstanceType != ws.Resource.InstanceType {
+		for _, instanceType := range ws.Resource.FallbackInstanceTypes {
+			if instanceType == ws.Status.InstanceType {
+				return instanceType
+			}
+		}
+	}
+	return ws.Resource.InstanceType
+}
+
+// GetPresetTag returns the tag of the preset image, which is the version pinned by the preset spec if any, and the
+// default tag of the preset otherwise.
+func GetPresetTag(preset *PresetSpec, defaultTag string) string {
+	if preset != nil && preset.PresetOptions.Version != "" {
+		return preset.PresetOptions.Version
+	}
+	return defaultTag
+}
@@ -7,24 +7,45 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
-
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/huggingface"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/sku/recommender"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/robfig/cron/v3"
+	"github.com/samber/lo"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 )
 
+// huggingFaceModelIDRegex matches the IDs of model repositories, which have an optional organization.
+var huggingFaceModelIDRegex = regexp.MustCompile(`^([\w.-]+/)?[\w.-]+$`)
+
+// cpuInstanceTypeRegexes match the general-purpose, compute- and memory-optimized instance types of each cloud
+// provider, which are not listed by the SKU handlers because they have no GPUs.
+var cpuInstanceTypeRegexes = map[string]*regexp.Regexp{
+	consts.AzureCloudName: regexp.MustCompile(`^Standard_[BDEF]\d`),
+	consts.AWSCloudName:   regexp.MustCompile(`^[mcrt]\d+[a-z-]*\.\w+$`),
+}
+
 const (
 	N_SERIES_PREFIX = "Standard_N"
 	D_SERIES_PREFIX = "Standard_D"
@@ -32,6 +53,12 @@ const (
 	DefaultLoraConfigMapTemplate  = "lora-params-template"
 	DefaultQloraConfigMapTemplate = "qlora-params-template"
 	MaxAdaptersNumber             = 10
+
+	// maxSuggestedInstanceTypes is the number of instance types suggested when the instance type is too small.
+	maxSuggestedInstanceTypes = 3
+	// huggingFaceHubTimeout bounds the requests to the Hugging Face Hub made while validating a workspace, well below
+	// the 10s timeout of the API server for the webhook.
+	huggingFaceHubTimeout = 4 * time.Second
 )
 
 func (w *Workspace) SupportedVerbs() []admissionregistrationv1.OperationType {
@@ -46,33 +73,84 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 	if len(errmsgs) > 0 {
 		errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "name"))
 	}
+	if value, found := w.Annotations[AnnotationRollbackToRevision]; found {
+		if revision, err := strconv.ParseInt(value, 10, 64); err != nil || revision <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be a positive revision number", AnnotationRollbackToRevision), "metadata.annotations"))
+		}
+	}
+	if value, found := w.Annotations[AnnotationScheduleOverride]; found && value != ScheduleOverrideRunning && value != ScheduleOverrideStopped {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be either %s or %s", AnnotationScheduleOverride, ScheduleOverrideRunning, ScheduleOverrideStopped), "metadata.annotations"))
+	}
+	if value, found := w.Annotations[AnnotationUpgradePreset]; found {
+		if w.Inference == nil || w.Inference.Preset == nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s is only supported for inference presets", AnnotationUpgradePreset), "metadata.annotations"))
+		} else if value != PresetUpgradeLatest {
+			if err := validatePresetVersion(w.Inference.Preset, value); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be %s or a released version of the preset: %s", AnnotationUpgradePreset, PresetUpgradeLatest, err.Message), "metadata.annotations"))
+			} else if presetModel := plugin.KaitoModelRegister.Get(string(w.Inference.Preset.Name)); presetModel != nil {
+				upgraded := w.DeepCopy()
+				upgraded.Inference.Preset.PresetOptions.Version = value
+				if rt := model.GetRuntime(GetWorkspaceRuntimeName(upgraded)); rt != nil && !PresetVersionSupportsRuntime(upgraded.Inference.Preset, presetModel, rt) {
+					errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be a version of the preset that can be served by the %s runtime", AnnotationUpgradePreset, rt.Name()), "metadata.annotations"))
+				}
+			}
+		}
+	}
 	base := apis.GetBaseline(ctx)
 	if base == nil {
 		klog.InfoS("Validate creation", "workspace", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
 		errs = errs.Also(w.validateCreate().ViaField("spec"))
 		if w.Inference != nil {
 			// TODO: Add Adapter Spec Validation - Including DataSource Validation for Adapter
-			errs = errs.Also(w.Resource.validateCreateWithInference(w.Inference).ViaField("resource"),
-				w.Inference.validateCreate().ViaField("inference"))
+			errs = errs.Also(w.Resource.validateCreateWithInference(ctx, w.Inference, w.Namespace, GetWorkspaceRuntimeName(w)).ViaField("resource"),
+				w.Inference.validateCreate().ViaField("inference"),
+				w.Inference.validateRuntime(GetWorkspaceRuntimeName(w)).ViaField("inference"))
+			if w.Inference.Autoscaling != nil {
+				errs = errs.Also(w.Inference.Autoscaling.validate(w).ViaField("inference.autoscaling"))
+			}
+			if w.Inference.IdlePolicy != nil {
+				errs = errs.Also(w.Inference.IdlePolicy.validate().ViaField("inference.idlePolicy"))
+			}
+			if w.Inference.Schedule != nil {
+				errs = errs.Also(w.Inference.Schedule.validate().ViaField("inference.schedule"))
+			}
 		}
 		if w.Tuning != nil {
 			// TODO: Add validate resource based on Tuning Spec
 			errs = errs.Also(w.Resource.validateCreateWithTuning(w.Tuning).ViaField("resource"),
 				w.Tuning.validateCreate(ctx, w.Namespace).ViaField("tuning"))
 		}
+		errs = errs.Also(w.validateGPUQuota(ctx).ViaField("resource"),
+			w.validateScale(nil))
 	} else {
 		klog.InfoS("Validate update", "workspace", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
 		old := base.(*Workspace)
 		errs = errs.Also(
 			w.validateUpdate(old).ViaField("spec"),
-			w.Resource.validateUpdate(&old.Resource).ViaField("resource"),
+			w.Resource.validateUpdate(&old.Resource, w.Inference).ViaField("resource"),
+			w.validateScale(old),
 		)
 		if w.Inference != nil {
-			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"))
+			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"),
+				w.Inference.validateRuntime(GetWorkspaceRuntimeName(w)).ViaField("inference"))
+			if w.Inference.Autoscaling != nil {
+				errs = errs.Also(w.Inference.Autoscaling.validate(w).ViaField("inference.autoscaling"))
+			}
+			if w.Inference.IdlePolicy != nil {
+				errs = errs.Also(w.Inference.IdlePolicy.validate().ViaField("inference.idlePolicy"))
+			}
+			if w.Inference.Schedule != nil {
+				errs = errs.Also(w.Inference.Schedule.validate().ViaField("inference.schedule"))
+			}
 		}
 		if w.Tuning != nil {
 			errs = errs.Also(w.Tuning.validateUpdate(old.Tuning).ViaField("tuning"))
 		}
+		// Only check the quota when more nodes are requested, so that workspaces admitted before a quota was
+		// created can still be updated or scaled down.
+		if lo.FromPtr(w.Resource.Count) > lo.FromPtr(old.Resource.Count) {
+			errs = errs.Also(w.validateGPUQuota(ctx).ViaField("resource"))
+		}
 	}
 	return errs
 }
@@ -87,6 +165,13 @@ func (w *Workspace) validateCreate() (errs *apis.FieldError) {
 	return errs
 }
 
+// validateGPUQuota checks the nodes of the workspace against the GPU quotas of its namespace. All candidate
+// instance types are checked since the nodes may be provisioned with any of them.
+func (w *Workspace) validateGPUQuota(ctx context.Context) (errs *apis.FieldError) {
+	instanceTypes := append([]string{w.Resource.InstanceType}, w.Resource.FallbackInstanceTypes...)
+	return validateGPUQuota(ctx, w, instanceTypes, lo.FromPtr(w.Resource.Count))
+}
+
 func (w *Workspace) validateUpdate(old *Workspace) (errs *apis.FieldError) {
 	if (old.Inference == nil && w.Inference != nil) || (old.Inference != nil && w.Inference == nil) {
 		errs = errs.Also(apis.ErrGeneric("Inference field cannot be toggled once set", "inference"))
@@ -98,6 +183,31 @@ func (w *Workspace) validateUpdate(old *Workspace) (errs *apis.FieldError) {
 	return errs
 }
 
+// validateScale checks that the replicas of the scale subresource are only set for the workspaces whose count can be
+// changed, and that the count of a workspace is changed through its replicas once they are set.
+func (w *Workspace) validateScale(old *Workspace) (errs *apis.FieldError) {
+	if w.Spec == nil || w.Spec.Replicas == nil {
+		return nil
+	}
+	if !IsCountMutable(w.Inference) {
+		return apis.ErrGeneric("replicas are only supported for inference workspaces that run one replica per node", "spec.replicas")
+	}
+	replicas, count := *w.Spec.Replicas, lo.FromPtr(w.Resource.Count)
+	if replicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue("replicas must be at least 1", "spec.replicas"))
+	}
+	if int(replicas) == count {
+		return errs
+	}
+	if old == nil {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("replicas %d must be equal to resource.count %d", replicas, count), "spec.replicas"))
+	} else if old.Spec != nil && lo.FromPtr(old.Spec.Replicas) != replicas && lo.FromPtr(old.Resource.Count) != count {
+		// A change of either side alone is copied to the other one by the controller.
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("replicas %d must be equal to resource.count %d when both are changed", replicas, count), "spec.replicas"))
+	}
+	return errs
+}
+
 func (r *AdapterSpec) validateCreateorUpdate() (errs *apis.FieldError) {
 	if r.Source == nil {
 		errs = errs.Also(apis.ErrMissingField("Source"))
@@ -157,17 +267,27 @@ func (r *TuningSpec) validateCreate(ctx context.Context, workspaceNamespace stri
 		errs = errs.Also(apis.ErrMissingField("Input"))
 	} else {
 		errs = errs.Also(r.Input.validateCreate().ViaField("Input"))
+		if r.Input.ImageFormat == ImageFormatArtifact {
+			errs = errs.Also(apis.ErrInvalidValue("Tuning input does not support the artifact image format", "Input.ImageFormat"))
+		}
 	}
 	if r.Output == nil {
 		errs = errs.Also(apis.ErrMissingField("Output"))
 	} else {
 		errs = errs.Also(r.Output.validateCreate().ViaField("Output"))
 	}
+	if r.Checkpoint != nil {
+		errs = errs.Also(r.Checkpoint.validate().ViaField("Checkpoint"))
+	}
 	// Currently require a preset to specified, in future we can consider defining a template
 	if r.Preset == nil {
 		errs = errs.Also(apis.ErrMissingField("Preset"))
 	} else if presetName := string(r.Preset.Name); !plugin.IsValidPreset(presetName) {
 		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported tuning preset name %s", presetName), "presetName"))
+	} else if !plugin.KaitoModelRegister.MustGet(presetName).SupportTuning() {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Preset %s does not support tuning", presetName), "presetName"))
+	} else if r.Preset.PresetOptions.Version != "" {
+		errs = errs.Also(validatePresetVersion(r.Preset, r.Preset.PresetOptions.Version))
 	}
 	return errs
 }
@@ -183,8 +303,13 @@ func (r *TuningSpec) validateUpdate(old *TuningSpec) (errs *apis.FieldError) {
 	} else {
 		errs = errs.Also(r.Output.validateUpdate().ViaField("Output"))
 	}
-	if !reflect.DeepEqual(old.Preset, r.Preset) {
+	if r.Checkpoint != nil {
+		errs = errs.Also(r.Checkpoint.validate().ViaField("Checkpoint"))
+	}
+	if !reflect.DeepEqual(presetWithoutVersion(old.Preset), presetWithoutVersion(r.Preset)) {
 		errs = errs.Also(apis.ErrGeneric("Preset cannot be changed", "Preset"))
+	} else if r.Preset != nil && r.Preset.PresetOptions.Version != "" && r.Preset.PresetOptions.Version != old.Preset.PresetOptions.Version {
+		errs = errs.Also(validatePresetVersion(r.Preset, r.Preset.PresetOptions.Version))
 	}
 	oldMethod, newMethod := strings.ToLower(string(old.Method)), strings.ToLower(string(r.Method))
 	if !reflect.DeepEqual(oldMethod, newMethod) {
@@ -200,9 +325,10 @@ func (r *DataSource) validateCreate() (errs *apis.FieldError) {
 		sourcesSpecified++
 	}
 	if r.Volume != nil {
-		errs = errs.Also(apis.ErrInvalidValue("Volume support is not implemented yet", "Volume"))
+		errs = errs.Also(validateDataVolume(r.Volume).ViaField("Volume"))
 		sourcesSpecified++
 	}
+	errs = errs.Also(r.validateSubPath())
 	// Regex checks for a / and a colon followed by a tag
 	if r.Image != "" {
 		re := regexp.MustCompile(`^(.+/[^:/]+):([^:/]+)$`)
@@ -217,6 +343,10 @@ func (r *DataSource) validateCreate() (errs *apis.FieldError) {
 		}
 		sourcesSpecified++
 	}
+	errs = errs.Also(validateImageFormat(r.ImageFormat, r.Image))
+	if r.ImageFormat == ImageFormatArtifact && len(r.ImagePullSecrets) > 1 {
+		errs = errs.Also(apis.ErrInvalidValue("Only one image pull secret can be used to pull an artifact", "ImagePullSecrets"))
+	}
 
 	// Ensure exactly one of URLs, Volume, or Image is specified
 	if sourcesSpecified != 1 {
@@ -231,17 +361,100 @@ func (r *DataSource) validateUpdate(old *DataSource, isTuning bool) (errs *apis.
 		errs = errs.Also(apis.ErrInvalidValue("During tuning Name field cannot be changed once set", "Name"))
 	}
 	if r.Volume != nil {
-		errs = errs.Also(apis.ErrInvalidValue("Volume support is not implemented yet", "Volume"))
+		errs = errs.Also(validateDataVolume(r.Volume).ViaField("Volume"))
+	}
+	errs = errs.Also(r.validateSubPath())
+	errs = errs.Also(validateImageFormat(r.ImageFormat, r.Image))
+
+	return errs
+}
+
+// validateImageFormat checks that the image format is supported and that it is only set with an image.
+func validateImageFormat(format ImageFormat, image string) (errs *apis.FieldError) {
+	if format == "" {
+		return nil
+	}
+	if format != ImageFormatContainer && format != ImageFormatArtifact {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported image format %s, supported formats are %s and %s", format, ImageFormatContainer, ImageFormatArtifact), "ImageFormat"))
+	}
+	if image == "" {
+		errs = errs.Also(apis.ErrGeneric("ImageFormat can only be specified with Image", "ImageFormat"))
+	}
+	return errs
+}
+
+// validateSubPath checks that the subpath stays inside the volume of the data source.
+func (r *DataSource) validateSubPath() (errs *apis.FieldError) {
+	if r.SubPath == "" {
+		return nil
 	}
+	if r.Volume == nil {
+		return apis.ErrGeneric("SubPath can only be specified with Volume", "SubPath")
+	}
+	if filepath.IsAbs(r.SubPath) {
+		errs = errs.Also(apis.ErrInvalidValue("SubPath must be a relative path", "SubPath"))
+	}
+	for _, part := range strings.Split(filepath.ToSlash(r.SubPath), "/") {
+		if part == ".." {
+			errs = errs.Also(apis.ErrInvalidValue("SubPath must not contain '..'", "SubPath"))
+			break
+		}
+	}
+	return errs
+}
 
+// validateDataVolume checks that the volume of tuning data is backed by exactly one shared storage, which can be
+// mounted by the tuning job on any node.
+func validateDataVolume(volume *v1.VolumeSource) (errs *apis.FieldError) {
+	sourcesSpecified := 0
+	value := reflect.ValueOf(volume).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			sourcesSpecified++
+		}
+	}
+	switch {
+	case sourcesSpecified != 1:
+		return apis.ErrGeneric("Exactly one volume source must be specified")
+	case volume.PersistentVolumeClaim != nil:
+		if volume.PersistentVolumeClaim.ClaimName == "" {
+			errs = errs.Also(apis.ErrMissingField("claimName").ViaField("persistentVolumeClaim"))
+		}
+	case volume.AzureFile != nil:
+		if volume.AzureFile.SecretName == "" {
+			errs = errs.Also(apis.ErrMissingField("secretName").ViaField("azureFile"))
+		}
+		if volume.AzureFile.ShareName == "" {
+			errs = errs.Also(apis.ErrMissingField("shareName").ViaField("azureFile"))
+		}
+	case volume.AzureDisk != nil:
+		if volume.AzureDisk.DiskName == "" {
+			errs = errs.Also(apis.ErrMissingField("diskName").ViaField("azureDisk"))
+		}
+		if volume.AzureDisk.DataDiskURI == "" {
+			errs = errs.Also(apis.ErrMissingField("diskURI").ViaField("azureDisk"))
+		}
+	case volume.NFS != nil:
+		if volume.NFS.Server == "" {
+			errs = errs.Also(apis.ErrMissingField("server").ViaField("nfs"))
+		}
+		if volume.NFS.Path == "" {
+			errs = errs.Also(apis.ErrMissingField("path").ViaField("nfs"))
+		}
+	case volume.CSI != nil:
+		if volume.CSI.Driver == "" {
+			errs = errs.Also(apis.ErrMissingField("driver").ViaField("csi"))
+		}
+	default:
+		errs = errs.Also(apis.ErrGeneric("Unsupported volume source, supported sources are persistentVolumeClaim, azureFile, azureDisk, nfs and csi"))
+	}
 	return errs
 }
 
 func (r *DataDestination) validateCreate() (errs *apis.FieldError) {
 	destinationsSpecified := 0
-	// TODO: Implement Volumes
 	if r.Volume != nil {
-		errs = errs.Also(apis.ErrInvalidValue("Volume support is not implemented yet", "Volume"))
+		errs = errs.Also(validateDataVolume(r.Volume).ViaField("Volume"))
 		destinationsSpecified++
 	}
 	if r.Image != "" {
@@ -256,12 +469,14 @@ func (r *DataDestination) validateCreate() (errs *apis.FieldError) {
 				errs = errs.Also(apis.ErrInvalidValue(err.Error(), "Image"))
 			}
 		}
-		// Cloud Provider requires credentials to push image
-		if r.ImagePushSecret == "" {
+		// Cloud Provider requires credentials to push image. Artifacts can be pushed without credentials, e.g., to a
+		// local registry.
+		if r.ImagePushSecret == "" && r.ImageFormat != ImageFormatArtifact {
 			errs = errs.Also(apis.ErrMissingField("Must specify imagePushSecret with destination image"))
 		}
 		destinationsSpecified++
 	}
+	errs = errs.Also(validateImageFormat(r.ImageFormat, r.Image))
 
 	// If no destination is specified, return an error
 	if destinationsSpecified == 0 {
@@ -271,113 +486,229 @@ func (r *DataDestination) validateCreate() (errs *apis.FieldError) {
 }
 
 func (r *DataDestination) validateUpdate() (errs *apis.FieldError) {
-	// TODO: Implement Volumes
 	if r.Volume != nil {
-		errs = errs.Also(apis.ErrInvalidValue("Volume support is not implemented yet", "Volume"))
+		errs = errs.Also(validateDataVolume(r.Volume).ViaField("Volume"))
 	}
+	errs = errs.Also(validateImageFormat(r.ImageFormat, r.Image))
 
 	return errs
 }
 
+func (r *CheckpointSpec) validate() (errs *apis.FieldError) {
+	if r.Interval < 0 {
+		errs = errs.Also(apis.ErrInvalidValue("Interval must be a positive number of steps", "Interval"))
+	}
+	if r.Volume == nil {
+		errs = errs.Also(apis.ErrMissingField("Volume"))
+	} else {
+		errs = errs.Also(validateDataVolume(r.Volume).ViaField("Volume"))
+	}
+	return errs
+}
+
 func (r *ResourceSpec) validateCreateWithTuning(tuning *TuningSpec) (errs *apis.FieldError) {
 	if *r.Count > 1 {
 		errs = errs.Also(apis.ErrInvalidValue("Tuning does not currently support multinode configurations. Please set the node count to 1. Future support with DeepSpeed will allow this.", "count"))
 	}
+	if skuHandler, err := utils.GetSKUHandler(); err == nil {
+		if skuConfig, exists := skuHandler.GetGPUConfigs()[r.InstanceType]; exists {
+			errs = errs.Also(validateGPUVendor(r.InstanceType, skuConfig, "instanceType"))
+		}
+	}
 	return errs
 }
 
-func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec) (errs *apis.FieldError) {
+func (r *ResourceSpec) validateCreateWithInference(ctx context.Context, inference *InferenceSpec, namespace string, runtime model.RuntimeName) (errs *apis.FieldError) {
 	var presetName string
-	if inference.Preset != nil {
+	var presetModel model.Model
+	if inference.Preset != nil && plugin.IsValidPreset(string(inference.Preset.Name)) {
 		presetName = strings.ToLower(string(inference.Preset.Name))
+		hubCtx, cancel := context.WithTimeout(ctx, huggingFaceHubTimeout)
+		defer cancel()
+		var err error
+		if presetModel, err = getPresetModel(hubCtx, k8sclient.Client, namespace, inference.Preset); err != nil {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Failed to get the requirements of preset %s: %v", presetName, err), "instanceType"))
+		}
 	}
-	instanceType := string(r.InstanceType)
 
 	skuHandler, err := utils.GetSKUHandler()
 	if err != nil {
 		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Failed to get SKU handler: %v", err), "instanceType"))
 		return errs
 	}
+
+	// Models served on CPUs run on instance types without GPUs, so only the existence of the instance types is checked.
+	rt := model.GetRuntime(runtime)
+	cpuInference := inference.Preset != nil && rt != nil && rt.SupportsFeature(model.RuntimeFeatureCPU)
+	validate := func(instanceType, fieldPath string) *apis.FieldError {
+		if cpuInference {
+			return validateCPUInstanceType(skuHandler, instanceType, fieldPath)
+		}
+		return validateInstanceType(skuHandler, instanceType, presetName, presetModel, runtime, *r.Count, fieldPath)
+	}
+	errs = errs.Also(validate(r.InstanceType, "instanceType"))
+
+	// Every fallback instance type must be able to run the workload on its own.
+	candidates := sets.New(r.InstanceType)
+	for i, instanceType := range r.FallbackInstanceTypes {
+		if candidates.Has(instanceType) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Duplicate instance type %s", instanceType), "fallbackInstanceTypes").ViaIndex(i))
+			continue
+		}
+		candidates.Insert(instanceType)
+		errs = errs.Also(validate(instanceType, "").ViaFieldIndex("fallbackInstanceTypes", i))
+	}
+
+	// Validate labelSelector
+	if _, err := metav1.LabelSelectorAsMap(r.LabelSelector); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
+	}
+
+	return errs
+}
+
+// validateInstanceType checks that the instance type is supported and, for preset models, that count nodes of
+// this instance type meet the GPU requirements of the preset.
+func validateInstanceType(skuHandler sku.CloudSKUHandler, instanceType, presetName string, model model.Model, runtime model.RuntimeName, count int, fieldPath string) (errs *apis.FieldError) {
 	gpuConfigs := skuHandler.GetGPUConfigs()
 
 	// Check if instancetype exists in our SKUs map for the particular cloud provider
 	if skuConfig, exists := gpuConfigs[instanceType]; exists {
-		if presetName != "" {
-			model := plugin.KaitoModelRegister.MustGet(presetName) // InferenceSpec has been validated so the name is valid.
-
-			machineCount := *r.Count
-			machineTotalNumGPUs := resource.NewQuantity(int64(machineCount*skuConfig.GPUCount), resource.DecimalSI)
-			machinePerGPUMemory := resource.NewQuantity(int64(skuConfig.GPUMem/skuConfig.GPUCount)*consts.GiBToBytes, resource.BinarySI) // Ensure it's per GPU
-			machineTotalGPUMem := resource.NewQuantity(int64(machineCount*skuConfig.GPUMem)*consts.GiBToBytes, resource.BinarySI)        // Total GPU memory
-
-			modelGPUCount := resource.MustParse(model.GetInferenceParameters().GPUCountRequirement)
-			modelPerGPUMemory := resource.MustParse(model.GetInferenceParameters().PerGPUMemoryRequirement)
-			modelTotalGPUMemory := resource.MustParse(model.GetInferenceParameters().TotalGPUMemoryRequirement)
+		if err := validateGPUVendor(instanceType, skuConfig, fieldPath); err != nil {
+			return err
+		}
+		if model != nil {
+			eval := recommender.Evaluate(model, runtime, skuConfig, count)
 
 			// Separate the checks for specific error messages
-			if machineTotalNumGPUs.Cmp(modelGPUCount) < 0 {
+			if eval.AvailableGPUs < eval.RequiredGPUs {
 				errs = errs.Also(apis.ErrInvalidValue(
 					fmt.Sprintf(
-						"Insufficient number of GPUs: Instance type %s provides %s, but preset %s requires at least %d",
+						"Insufficient number of GPUs: Instance type %s provides %d, but preset %s requires at least %d",
 						instanceType,
-						machineTotalNumGPUs.String(),
+						eval.AvailableGPUs,
 						presetName,
-						modelGPUCount.Value(),
+						eval.RequiredGPUs,
 					),
-					"instanceType",
+					fieldPath,
 				))
 			}
 
-			if machinePerGPUMemory.Cmp(modelPerGPUMemory) < 0 {
-				errs = errs.Also(apis.ErrInvalidValue(
-					fmt.Sprintf(
-						"Insufficient per GPU memory: Instance type %s provides %s per GPU, but preset %s requires at least %s per GPU",
-						instanceType,
-						machinePerGPUMemory.String(),
-						presetName,
-						modelPerGPUMemory.String(),
-					),
-					"instanceType",
-				))
+			switch {
+			case eval.EstimateError != nil:
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Failed to estimate the GPU memory of preset %s: %v", presetName, eval.EstimateError), fieldPath))
+			case eval.Estimate != nil:
+				// The estimate is more precise than the fixed requirements, which cover the default configuration only.
+				if eval.AvailableGPUMemory.Cmp(eval.RequiredGPUMemory) < 0 {
+					gpus := "SKU has"
+					if eval.Estimate.NumGPUs > 1 {
+						gpus = fmt.Sprintf("%d GPUs of the SKU have", eval.Estimate.NumGPUs)
+					}
+					errs = errs.Also(apis.ErrInvalidValue(
+						fmt.Sprintf(
+							"Insufficient GPU memory: preset %s needs %dGiB at max-model-len %d (%s), %s %dGiB on instance type %s",
+							presetName,
+							eval.Estimate.TotalGiB(),
+							eval.Estimate.MaxModelLen,
+							eval.Estimate,
+							gpus,
+							eval.AvailableGPUMemory.Value()/consts.GiBToBytes,
+							instanceType,
+						),
+						fieldPath,
+					))
+				}
+			default:
+				if eval.AvailablePerGPUMemory.Cmp(eval.RequiredPerGPUMemory) < 0 {
+					errs = errs.Also(apis.ErrInvalidValue(
+						fmt.Sprintf(
+							"Insufficient per GPU memory: Instance type %s provides %s per GPU, but preset %s requires at least %s per GPU",
+							instanceType,
+							eval.AvailablePerGPUMemory.String(),
+							presetName,
+							eval.RequiredPerGPUMemory.String(),
+						),
+						fieldPath,
+					))
+				}
+
+				if eval.AvailableGPUMemory.Cmp(eval.RequiredGPUMemory) < 0 {
+					errs = errs.Also(apis.ErrInvalidValue(
+						fmt.Sprintf(
+							"Insufficient total GPU memory: Instance type %s has a total of %s, but preset %s requires at least %s",
+							instanceType,
+							eval.AvailableGPUMemory.String(),
+							presetName,
+							eval.RequiredGPUMemory.String(),
+						),
+						fieldPath,
+					))
+				}
 			}
 
-			if machineTotalGPUMem.Cmp(modelTotalGPUMemory) < 0 {
-				errs = errs.Also(apis.ErrInvalidValue(
-					fmt.Sprintf(
-						"Insufficient total GPU memory: Instance type %s has a total of %s, but preset %s requires at least %s",
-						instanceType,
-						machineTotalGPUMem.String(),
-						presetName,
-						modelTotalGPUMemory.String(),
-					),
-					"instanceType",
-				))
+			if errs != nil {
+				errs = errs.Also(suggestInstanceTypes(skuHandler, presetName, model, runtime, count, fieldPath))
 			}
 		}
 	} else {
 		provider := os.Getenv("CLOUD_PROVIDER")
 		// Check for other instance types pattern matches if cloud provider is Azure
 		if provider != consts.AzureCloudName || (!strings.HasPrefix(instanceType, N_SERIES_PREFIX) && !strings.HasPrefix(instanceType, D_SERIES_PREFIX)) {
-			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), "instanceType"))
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), fieldPath))
 		}
 	}
+	return errs
+}
 
-	// Validate labelSelector
-	if _, err := metav1.LabelSelectorAsMap(r.LabelSelector); err != nil {
-		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
+// validateGPUVendor checks that workloads can run on the GPUs of the vendor of the instance type.
+func validateGPUVendor(instanceType string, skuConfig sku.GPUConfig, fieldPath string) *apis.FieldError {
+	vendor := skuConfig.GPUVendor()
+	if sku.IsEnabledGPUVendor(vendor) {
+		return nil
 	}
+	return apis.ErrInvalidValue(fmt.Sprintf("Instance type %s has %s GPUs, which are disabled by the %s feature gate",
+		instanceType, vendor, sku.GetGPUVendorConfig(vendor).FeatureGate), fieldPath)
+}
 
-	return errs
+// validateCPUInstanceType checks that the instance type is a SKU of the cloud provider that can serve models on CPUs,
+// either a SKU known to the SKU handler or a general-purpose, compute- or memory-optimized instance type.
+func validateCPUInstanceType(skuHandler sku.CloudSKUHandler, instanceType, fieldPath string) *apis.FieldError {
+	if _, exists := skuHandler.GetGPUConfigs()[instanceType]; exists {
+		return nil
+	}
+	if re, ok := cpuInstanceTypeRegexes[os.Getenv("CLOUD_PROVIDER")]; ok && re.MatchString(instanceType) {
+		return nil
+	}
+	return apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s for CPU inference. Supported SKUs: general-purpose, compute- and memory-optimized SKUs, and %s",
+		instanceType, skuHandler.GetSupportedSKUs()), fieldPath)
+}
+
+// suggestInstanceTypes returns an error listing the smallest instance types that meet the GPU requirements of the
+// preset, if any.
+func suggestInstanceTypes(skuHandler sku.CloudSKUHandler, presetName string, model model.Model, runtime model.RuntimeName, count int, fieldPath string) *apis.FieldError {
+	recommendations := recommender.Recommend(model, runtime, recommender.Constraints{NodeCount: count},
+		map[string]sku.CloudSKUHandler{os.Getenv("CLOUD_PROVIDER"): skuHandler})
+	if len(recommendations) == 0 {
+		return nil
+	}
+	suggestions := make([]string, 0, maxSuggestedInstanceTypes)
+	for _, r := range lo.Slice(recommendations, 0, maxSuggestedInstanceTypes) {
+		suggestions = append(suggestions, fmt.Sprintf("%s (%d x %s, %dGiB)", r.SKU, r.GPUCount, r.GPUModel, r.GPUMem))
+	}
+	return apis.ErrGeneric(fmt.Sprintf("Instance types that meet the requirements of preset %s: %s", presetName, strings.Join(suggestions, ", ")), fieldPath)
 }
 
-func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError) {
-	// We disable changing node count for now.
-	if r.Count != nil && old.Count != nil && *r.Count != *old.Count {
+func (r *ResourceSpec) validateUpdate(old *ResourceSpec, inference *InferenceSpec) (errs *apis.FieldError) {
+	// Changing node count is only supported for inference workloads that run one replica per node.
+	if r.Count != nil && old.Count != nil && *r.Count != *old.Count && !IsCountMutable(inference) {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "count"))
 	}
 	if r.InstanceType != old.InstanceType {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "instanceType"))
 	}
+	if !reflect.DeepEqual(r.FallbackInstanceTypes, old.FallbackInstanceTypes) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "fallbackInstanceTypes"))
+	}
 	newLabels, err0 := metav1.LabelSelectorAsMap(r.LabelSelector)
 	oldLabels, err1 := metav1.LabelSelectorAsMap(old.LabelSelector)
 	if err0 != nil || err1 != nil {
@@ -390,6 +721,93 @@ func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError)
 	return errs
 }
 
+// IsCountMutable returns true if the inference workload is a Deployment whose replicas follow the node count.
+// Tuning jobs and distributed inference, where all nodes form a single model instance, cannot be resized.
+func IsCountMutable(inference *InferenceSpec) bool {
+	if inference == nil {
+		return false
+	}
+	if inference.Preset == nil {
+		return true
+	}
+	presetName := string(inference.Preset.Name)
+	if !plugin.KaitoModelRegister.Has(presetName) {
+		return false
+	}
+	return !plugin.KaitoModelRegister.MustGet(presetName).SupportDistributedInference()
+}
+
+func (a *AutoscalingSpec) validate(w *Workspace) (errs *apis.FieldError) {
+	minCount := a.MinCount
+	if minCount == 0 {
+		minCount = 1
+	}
+	if minCount < 1 {
+		errs = errs.Also(apis.ErrInvalidValue("minCount must be at least 1", "minCount"))
+	}
+	if a.MaxCount < minCount {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("maxCount %d must not be less than minCount %d", a.MaxCount, minCount), "maxCount"))
+	}
+	if a.TargetValue < 1 {
+		errs = errs.Also(apis.ErrInvalidValue("targetValue must be at least 1", "targetValue"))
+	}
+	switch a.Metric {
+	case AutoscalingMetricPendingRequests:
+	case AutoscalingMetricKVCacheUsage:
+		if a.TargetValue > 100 {
+			errs = errs.Also(apis.ErrInvalidValue("targetValue of KVCacheUsage must be a percentage between 1 and 100", "targetValue"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported metric %s", a.Metric), "metric"))
+	}
+	if a.ScaleDownStabilizationSeconds != nil && *a.ScaleDownStabilizationSeconds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue("scaleDownStabilizationSeconds must not be negative", "scaleDownStabilizationSeconds"))
+	}
+
+	if w.Inference.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("Autoscaling is only supported for preset models", "preset"))
+	} else if !IsCountMutable(w.Inference) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Autoscaling is not supported for preset %s which uses distributed inference", w.Inference.Preset.Name), "preset"))
+	}
+	// The autoscaler reads the metrics exposed by the inference server of the runtime.
+	runtime := GetWorkspaceRuntimeName(w)
+	if r := model.GetRuntime(runtime); r == nil || !r.SupportsFeature(model.RuntimeFeatureAutoscaling) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Autoscaling requires the %s runtime, but the workspace uses %s",
+			joinRuntimeNames(model.RuntimeNamesWithFeature(model.RuntimeFeatureAutoscaling), " or "), runtime), "runtime"))
+	}
+	return errs
+}
+
+func (p *IdlePolicySpec) validate() (errs *apis.FieldError) {
+	if p.IdleMinutes < 1 {
+		errs = errs.Also(apis.ErrInvalidValue("idleMinutes must be at least 1", "idleMinutes"))
+	}
+	return errs
+}
+
+func (s *ScheduleSpec) validate() (errs *apis.FieldError) {
+	if len(s.Windows) == 0 {
+		errs = errs.Also(apis.ErrMissingField("windows"))
+	}
+	for i, window := range s.Windows {
+		if _, err := cron.ParseStandard(window.Start); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid cron expression %q: %v", window.Start, err), "start").ViaFieldIndex("windows", i))
+		}
+		if window.Duration.Duration <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue("duration must be positive", "duration").ViaFieldIndex("windows", i))
+		}
+	}
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unknown time zone %s", s.TimeZone), "timeZone"))
+		}
+	}
+	if s.LeadMinutes < 0 {
+		errs = errs.Also(apis.ErrInvalidValue("leadMinutes must not be negative", "leadMinutes"))
+	}
+	return errs
+}
+
 func (i *InferenceSpec) validateCreate() (errs *apis.FieldError) {
 	// Check if both Preset and Template are not set
 	if i.Preset == nil && i.Template == nil {
@@ -417,6 +835,19 @@ func (i *InferenceSpec) validateCreate() (errs *apis.FieldError) {
 			errs = errs.Also(apis.ErrGeneric("When AccessMode is private, an image must be provided in PresetOptions"))
 		}
 		// Note: we don't enforce private access mode to have image secrets, in case anonymous pulling is enabled
+		// The model repository is required by the generic huggingface preset and unused by the others.
+		if hf := i.Preset.PresetOptions.HuggingFace; presetName == huggingface.PresetName {
+			if hf == nil {
+				errs = errs.Also(apis.ErrMissingField("presetOptions.huggingFace"))
+			} else if !huggingFaceModelIDRegex.MatchString(hf.ModelID) {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Invalid model ID %s, expected <organization>/<model>", hf.ModelID), "presetOptions.huggingFace.modelID"))
+			}
+		} else if hf != nil {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("presetOptions.huggingFace is only supported by the %s preset", huggingface.PresetName), "presetOptions.huggingFace"))
+		}
+		if version := i.Preset.PresetOptions.Version; version != "" && plugin.IsValidPreset(presetName) {
+			errs = errs.Also(validatePresetVersion(i.Preset, version))
+		}
 	}
 	if len(i.Adapters) > MaxAdaptersNumber {
 		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Number of Adapters exceeds the maximum limit, maximum of %s allowed", strconv.Itoa(MaxAdaptersNumber))))
@@ -431,9 +862,55 @@ func (i *InferenceSpec) validateCreate() (errs *apis.FieldError) {
 	return errs
 }
 
+// validateRuntime checks that the runtime of the workspace is registered and can serve the preset model with the
+// features used by the inference spec.
+func (i *InferenceSpec) validateRuntime(runtime model.RuntimeName) (errs *apis.FieldError) {
+	if i.Preset == nil {
+		if i.Runtime != "" {
+			errs = errs.Also(apis.ErrGeneric("runtime is only supported for preset models", "runtime"))
+		}
+		return errs
+	}
+	r := model.GetRuntime(runtime)
+	if r == nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("Unsupported runtime %s, supported runtimes are %s", runtime, joinRuntimeNames(model.RuntimeNames(), ", ")), "runtime")
+	}
+	if runtime == model.RuntimeNameVLLM && !featuregates.FeatureGates[consts.FeatureFlagVLLM] {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("The %s runtime is disabled by the %s feature gate", runtime, consts.FeatureFlagVLLM), "runtime"))
+	}
+	// An unsupported preset is reported by validateCreate.
+	presetModel := plugin.KaitoModelRegister.Get(string(i.Preset.Name))
+	if presetModel == nil {
+		return errs
+	}
+	if !r.SupportsModel(presetModel.GetInferenceParameters()) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Preset %s cannot be served by the %s runtime", i.Preset.Name, runtime), "runtime"))
+	} else if !RuntimeSupportsModel(r, presetModel) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Preset %s uses distributed inference, which is not supported by the %s runtime", i.Preset.Name, runtime), "runtime"))
+	} else if !PresetVersionSupportsRuntime(i.Preset, presetModel, r) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Version %s of preset %s cannot be served by the %s runtime, pin a newer version or select another runtime",
+			i.Preset.PresetOptions.Version, i.Preset.Name, runtime), "runtime"))
+	}
+	if len(i.Adapters) > 0 && !r.SupportsFeature(model.RuntimeFeatureAdapters) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Adapters are not supported by the %s runtime", runtime), "runtime"))
+	}
+	return errs
+}
+
+func joinRuntimeNames(names []model.RuntimeName, sep string) string {
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = string(name)
+	}
+	return strings.Join(s, sep)
+}
+
 func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldError) {
-	if !reflect.DeepEqual(i.Preset, old.Preset) {
+	// Only the version of the preset can be changed, which upgrades or downgrades the preset image.
+	if !reflect.DeepEqual(presetWithoutVersion(i.Preset), presetWithoutVersion(old.Preset)) {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "preset"))
+	} else if i.Preset != nil && i.Preset.PresetOptions.Version != "" && i.Preset.PresetOptions.Version != old.Preset.PresetOptions.Version {
+		errs = errs.Also(validatePresetVersion(i.Preset, i.Preset.PresetOptions.Version))
 	}
 	// inference.template can be changed, but cannot be set/unset.
 	if (i.Template != nil && old.Template == nil) || (i.Template == nil && old.Template != nil) {
@@ -454,6 +931,33 @@ func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldErro
 	return errs
 }
 
+// validatePresetVersion checks that the version is a released tag of the image of the preset.
+func validatePresetVersion(preset *PresetSpec, version string) (errs *apis.FieldError) {
+	presetName := string(preset.Name)
+	if !plugin.IsValidPreset(presetName) {
+		return nil
+	}
+	params := plugin.KaitoModelRegister.MustGet(presetName).GetInferenceParameters()
+	if preset.AccessMode == ModelImageAccessModePrivate || params.ImageAccessMode == string(ModelImageAccessModePrivate) {
+		return apis.ErrGeneric("The version of a preset with a private image cannot be set, the image is specified in presetOptions.image", "presetOptions.version")
+	}
+	if !model.IsKnownPresetTag(presetName, params.Tag, version) {
+		return apis.ErrInvalidValue(fmt.Sprintf("Unsupported version %s of preset %s, released versions are %s", version, presetName,
+			strings.Join(model.GetPresetTags(presetName, params.Tag), ", ")), "presetOptions.version")
+	}
+	return nil
+}
+
+// presetWithoutVersion returns a copy of the preset without its version, for comparing the immutable fields.
+func presetWithoutVersion(preset *PresetSpec) *PresetSpec {
+	if preset == nil {
+		return nil
+	}
+	out := preset.DeepCopy()
+	out.PresetOptions.Version = ""
+	return out
+}
+
 func validateDuplicateName(adapters []AdapterSpec, nameMap map[string]bool) (errs *apis.FieldError) {
 	for _, adapter := range adapters {
 		if _, ok := nameMap[adapter.Source.Name]; ok {
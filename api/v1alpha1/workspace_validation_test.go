@@ -9,13 +9,17 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/huggingface"
 	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -70,6 +74,10 @@ func (*testModelStatic) GetInferenceParameters() *model.PresetParam {
 		GPUCountRequirement:       "1",
 		TotalGPUMemoryRequirement: "16Gi",
 		PerGPUMemoryRequirement:   "16Gi",
+		RuntimeParam: model.RuntimeParam{
+			Transformers: model.HuggingfaceTransformersParam{BaseCommand: "accelerate launch"},
+			VLLM:         model.VLLMParam{BaseCommand: "python3 /workspace/vllm/inference_api.py"},
+		},
 	}
 }
 func (*testModelStatic) GetTuningParameters() *model.PresetParam {
@@ -111,10 +119,30 @@ func (*testModelPrivate) SupportTuning() bool {
 	return true
 }
 
+type testModelDistributed struct{}
+
+func (*testModelDistributed) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		GPUCountRequirement:       gpuCountRequirement,
+		TotalGPUMemoryRequirement: totalGPUMemoryRequirement,
+		PerGPUMemoryRequirement:   perGPUMemoryRequirement,
+	}
+}
+func (*testModelDistributed) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+func (*testModelDistributed) SupportDistributedInference() bool {
+	return true
+}
+func (*testModelDistributed) SupportTuning() bool {
+	return false
+}
+
 func RegisterValidationTestModels() {
 	var test testModel
 	var testPrivate testModelPrivate
 	var testStatic testModelStatic
+	var testDistributed testModelDistributed
 	plugin.KaitoModelRegister.Register(&plugin.Registration{
 		Name:     "test-validation",
 		Instance: &test,
@@ -127,12 +155,28 @@ func RegisterValidationTestModels() {
 		Name:     "test-validation-static",
 		Instance: &testStatic,
 	})
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     "test-validation-distributed",
+		Instance: &testDistributed,
+	})
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     huggingface.PresetName,
+		Instance: &testDistributed,
+	})
+	model.RegisterPresetTags("test-validation", []string{"0.0.2", "0.0.1"})
+	model.RegisterPresetTags("test-validation-static", []string{"0.0.2", "0.0.1"})
+	model.RegisterPresetTagRuntimes("test-validation-static", "0.0.2", []model.RuntimeName{model.RuntimeNameHuggingfaceTransformers, model.RuntimeNameVLLM})
+	model.RegisterPresetTagRuntimes("test-validation-static", "0.0.1", []model.RuntimeName{model.RuntimeNameHuggingfaceTransformers})
 }
 
 func pointerToInt(i int) *int {
 	return &i
 }
 
+func pointerToInt32(i int32) *int32 {
+	return &i
+}
+
 func defaultConfigMapManifest() *v1.ConfigMap {
 	return &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -225,7 +269,8 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 		modelPerGPUMemory   string
 		modelTotalGPUMemory string
 		preset              bool
-		errContent          string // Content expect error to include, if any
+		runtime             model.RuntimeName // Defaults to vllm
+		errContent          string            // Content expect error to include, if any
 		expectErrs          bool
 		validateTuning      bool // To indicate if we are testing tuning validation
 	}{
@@ -343,6 +388,123 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 			expectErrs:     false,
 			validateTuning: false,
 		},
+		{
+			name: "Valid fallback instance types",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC24ads_A100_v4",
+				FallbackInstanceTypes: []string{"Standard_NC48ads_A100_v4", "Standard_NC12s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "Fallback instance type with insufficient GPU memory",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC12s_v3",
+				FallbackInstanceTypes: []string{"Standard_NV6"},
+				Count:                 pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "0",
+			modelTotalGPUMemory: "14Gi",
+			preset:              true,
+			errContent:          "Insufficient total GPU memory: Instance type Standard_NV6",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Unsupported fallback instance type",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC12s_v3",
+				FallbackInstanceTypes: []string{"Standard_invalid_sku"},
+				Count:                 pointerToInt(1),
+			},
+			errContent:     "fallbackInstanceTypes[0]",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Duplicate fallback instance type",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC12s_v3",
+				FallbackInstanceTypes: []string{"Standard_NC24s_v3", "Standard_NC12s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			errContent:     "Duplicate instance type Standard_NC12s_v3",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "CPU instance type with a CPU runtime",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_E8s_v5",
+				Count:        pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameLlamaCpp,
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "GPU fallback instance type with a CPU runtime",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_D8s_v5",
+				FallbackInstanceTypes: []string{"Standard_NC6s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			preset:         true,
+			runtime:        model.RuntimeNameLlamaCpp,
+			expectErrs:     false,
+			validateTuning: false,
+		},
+		{
+			name: "Unknown instance type with a CPU runtime",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_invalid_sku",
+				Count:        pointerToInt(1),
+			},
+			preset:         true,
+			runtime:        model.RuntimeNameLlamaCpp,
+			errContent:     "Unsupported instance type Standard_invalid_sku for CPU inference",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Unknown fallback instance type with a CPU runtime",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_E8s_v5",
+				FallbackInstanceTypes: []string{"Standard_invalid_sku"},
+				Count:                 pointerToInt(1),
+			},
+			preset:         true,
+			runtime:        model.RuntimeNameLlamaCpp,
+			errContent:     "fallbackInstanceTypes[0]",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "CPU instance type with a GPU runtime",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_E8s_v5",
+				Count:        pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			errContent:          "Unsupported instance type Standard_E8s_v5",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
 		{
 			name: "Tuning validation with single node",
 			resourceSpec: &ResourceSpec{
@@ -404,7 +566,11 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 				totalGPUMemoryRequirement = tc.modelTotalGPUMemory
 				perGPUMemoryRequirement = tc.modelPerGPUMemory
 
-				errs := tc.resourceSpec.validateCreateWithInference(&spec)
+				runtime := tc.runtime
+				if runtime == "" {
+					runtime = model.RuntimeNameVLLM
+				}
+				errs := tc.resourceSpec.validateCreateWithInference(context.Background(), &spec, "", runtime)
 				hasErrs := errs != nil
 				if hasErrs != tc.expectErrs {
 					t.Errorf("validateCreate() errors = %v, expectErrs %v", errs, tc.expectErrs)
@@ -422,12 +588,129 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 	}
 }
 
-func TestResourceSpecValidateUpdate(t *testing.T) {
+type testModelArchitecture struct {
+	runParams map[string]string
+}
+
+func (m *testModelArchitecture) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "16Gi",
+		PerGPUMemoryRequirement:   "0Gi",
+		Architecture: &model.ModelArchitecture{
+			ParameterCount:        7241732096,
+			DType:                 "bfloat16",
+			NumHiddenLayers:       32,
+			HiddenSize:            4096,
+			IntermediateSize:      14336,
+			NumAttentionHeads:     32,
+			NumKeyValueHeads:      8,
+			MaxPositionEmbeddings: 32768,
+		},
+		RuntimeParam: model.RuntimeParam{
+			VLLM: model.VLLMParam{ModelRunParams: m.runParams},
+		},
+	}
+}
+func (*testModelArchitecture) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+func (*testModelArchitecture) SupportDistributedInference() bool {
+	return false
+}
+func (*testModelArchitecture) SupportTuning() bool {
+	return false
+}
+
+func TestValidateInstanceTypeWithGPUMemoryEstimate(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceType string
+		runParams    map[string]string
+		amdEnabled   bool
+		errContent   string
+	}{
+		{
+			name:         "Default context fits",
+			instanceType: "Standard_NC6s_v3",
+		},
+		{
+			name:         "Long context does not fit",
+			instanceType: "Standard_NC6s_v3",
+			runParams:    map[string]string{"max-model-len": "32768"},
+			errContent:   "preset test-architecture needs 22GiB at max-model-len 32768 (weights 13.5GiB, KV cache 4.0GiB, activations 2.8GiB, runtime overhead 1.0GiB), SKU has 16GiB on instance type Standard_NC6s_v3",
+		},
+		{
+			name:         "Smallest instance types that fit are suggested",
+			instanceType: "Standard_NC6s_v3",
+			runParams:    map[string]string{"max-model-len": "32768"},
+			errContent: "Instance types that meet the requirements of preset test-architecture: Standard_NC24ads_A100_v4 (1 x NVIDIA A100, 80GiB), " +
+				"Standard_NC12s_v3 (2 x NVIDIA V100, 32GiB), Standard_NC48ads_A100_v4 (2 x NVIDIA A100, 160GiB)",
+		},
+		{
+			name:         "Instance types with AMD GPUs are suggested with the AMDGPU feature gate",
+			instanceType: "Standard_NC6s_v3",
+			runParams:    map[string]string{"max-model-len": "32768"},
+			amdEnabled:   true,
+			errContent: "Instance types that meet the requirements of preset test-architecture: Standard_NG32adms_V620_v1 (1 x AMD Radeon PRO V620, 32GiB), " +
+				"Standard_NG32ads_V620_v1 (1 x AMD Radeon PRO V620, 32GiB), Standard_NC24ads_A100_v4 (1 x NVIDIA A100, 80GiB)",
+		},
+		{
+			name:         "Instance type with AMD GPUs without the AMDGPU feature gate",
+			instanceType: "Standard_NG32ads_V620_v1",
+			errContent:   "Instance type Standard_NG32ads_V620_v1 has amd GPUs, which are disabled by the AMDGPU feature gate",
+		},
+		{
+			name:         "Instance type with AMD GPUs with the AMDGPU feature gate",
+			instanceType: "Standard_NG32ads_V620_v1",
+			amdEnabled:   true,
+		},
+		{
+			name:         "Long context fits with tensor parallelism",
+			instanceType: "Standard_NC12s_v3",
+			runParams:    map[string]string{"max-model-len": "32768"},
+		},
+		{
+			name:         "Long context fits with quantization",
+			instanceType: "Standard_NC6s_v3",
+			runParams:    map[string]string{"max-model-len": "32768", "quantization": "awq"},
+		},
+		{
+			name:         "Invalid max-model-len",
+			instanceType: "Standard_NC6s_v3",
+			runParams:    map[string]string{"max-model-len": "long"},
+			errContent:   "Failed to estimate the GPU memory of preset test-architecture: invalid max-model-len long",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			original := featuregates.FeatureGates[consts.FeatureFlagAMDGPU]
+			defer func() { featuregates.FeatureGates[consts.FeatureFlagAMDGPU] = original }()
+			featuregates.FeatureGates[consts.FeatureFlagAMDGPU] = tc.amdEnabled
+
+			m := &testModelArchitecture{runParams: tc.runParams}
+			errs := validateInstanceType(sku.NewAzureSKUHandler(), tc.instanceType, "test-architecture", m, model.RuntimeNameVLLM, 1, "instanceType")
+			if tc.errContent == "" {
+				if errs != nil {
+					t.Errorf("validateInstanceType() unexpected errors = %v", errs)
+				}
+				return
+			}
+			if errs == nil || !strings.Contains(errs.Error(), tc.errContent) {
+				t.Errorf("validateInstanceType() errors = %v, expected to contain = %v", errs, tc.errContent)
+			}
+		})
+	}
+}
 
+func TestResourceSpecValidateUpdate(t *testing.T) {
+	RegisterValidationTestModels()
 	tests := []struct {
 		name        string
 		newResource *ResourceSpec
 		oldResource *ResourceSpec
+		inference   *InferenceSpec
 		errContent  string // Content expected error to include, if any
 		expectErrs  bool
 	}{
@@ -442,6 +725,46 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Immutable Count For Distributed Inference",
+			newResource: &ResourceSpec{
+				Count: pointerToInt(10),
+			},
+			oldResource: &ResourceSpec{
+				Count: pointerToInt(5),
+			},
+			inference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation-distributed")}},
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Mutable Count For Preset Inference",
+			newResource: &ResourceSpec{
+				Count: pointerToInt(10),
+			},
+			oldResource: &ResourceSpec{
+				Count: pointerToInt(5),
+			},
+			inference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			expectErrs: false,
+		},
+		{
+			name: "Mutable Count For Template Inference",
+			newResource: &ResourceSpec{
+				Count: pointerToInt(1),
+			},
+			oldResource: &ResourceSpec{
+				Count: pointerToInt(5),
+			},
+			inference: &InferenceSpec{
+				Template: &v1.PodTemplateSpec{},
+			},
+			expectErrs: false,
+		},
 		{
 			name: "Immutable InstanceType",
 			newResource: &ResourceSpec{
@@ -453,6 +776,17 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Immutable FallbackInstanceTypes",
+			newResource: &ResourceSpec{
+				FallbackInstanceTypes: []string{"new_type"},
+			},
+			oldResource: &ResourceSpec{
+				FallbackInstanceTypes: []string{"old_type"},
+			},
+			errContent: "field is immutable: fallbackInstanceTypes",
+			expectErrs: true,
+		},
 		{
 			name: "Immutable LabelSelector",
 			newResource: &ResourceSpec{
@@ -484,7 +818,7 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 	// Run the tests
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			errs := tc.newResource.validateUpdate(tc.oldResource)
+			errs := tc.newResource.validateUpdate(tc.oldResource, tc.inference)
 			hasErrs := errs != nil
 			if hasErrs != tc.expectErrs {
 				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
@@ -501,91 +835,328 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 	}
 }
 
-func TestInferenceSpecValidateCreate(t *testing.T) {
+func TestAutoscalingSpecValidate(t *testing.T) {
 	RegisterValidationTestModels()
+	presetInference := func(name string, autoscaling *AutoscalingSpec) *InferenceSpec {
+		return &InferenceSpec{
+			Preset:      &PresetSpec{PresetMeta: PresetMeta{Name: ModelName(name)}},
+			Autoscaling: autoscaling,
+		}
+	}
 	tests := []struct {
-		name          string
-		inferenceSpec *InferenceSpec
-		errContent    string // Content expected error to include, if any
-		expectErrs    bool
+		name        string
+		inference   *InferenceSpec
+		annotations map[string]string
+		vllmEnabled bool
+		errContent  string // Content expected error to include, if any
+		expectErrs  bool
 	}{
 		{
-			name: "Invalid Preset Name",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name: ModelName("Invalid-Preset-Name"),
-					},
-				},
-			},
-			errContent: "model is not registered",
-			expectErrs: true,
+			name: "Valid PendingRequests",
+			inference: presetInference("test-validation", &AutoscalingSpec{
+				MinCount: 1, MaxCount: 3, Metric: AutoscalingMetricPendingRequests, TargetValue: 5,
+			}),
+			vllmEnabled: true,
+			expectErrs:  false,
 		},
 		{
-			name: "Only Template set",
-			inferenceSpec: &InferenceSpec{
-				Template: &v1.PodTemplateSpec{}, // Assuming a non-nil TemplateSpec implies it's set
-			},
-			errContent: "",
-			expectErrs: false,
+			name: "MaxCount Less Than MinCount",
+			inference: presetInference("test-validation", &AutoscalingSpec{
+				MinCount: 3, MaxCount: 2, Metric: AutoscalingMetricPendingRequests, TargetValue: 5,
+			}),
+			vllmEnabled: true,
+			errContent:  "maxCount 2 must not be less than minCount 3",
+			expectErrs:  true,
 		},
 		{
-			name:          "Preset and Template Unset",
-			inferenceSpec: &InferenceSpec{},
-			errContent:    "Preset or Template must be specified",
-			expectErrs:    true,
+			name: "KVCacheUsage Target Out Of Range",
+			inference: presetInference("test-validation", &AutoscalingSpec{
+				MaxCount: 2, Metric: AutoscalingMetricKVCacheUsage, TargetValue: 120,
+			}),
+			vllmEnabled: true,
+			errContent:  "percentage between 1 and 100",
+			expectErrs:  true,
 		},
 		{
-			name: "Preset and Template Set",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name: ModelName("test-validation"),
-					},
-				},
-				Template: &v1.PodTemplateSpec{}, // Assuming a non-nil TemplateSpec implies it's set
-			},
-			errContent: "Preset and Template cannot be set at the same time",
-			expectErrs: true,
+			name: "Distributed Preset",
+			inference: presetInference("test-validation-distributed", &AutoscalingSpec{
+				MaxCount: 2, Metric: AutoscalingMetricKVCacheUsage, TargetValue: 80,
+			}),
+			vllmEnabled: true,
+			errContent:  "uses distributed inference",
+			expectErrs:  true,
 		},
 		{
-			name: "Private Access Without Image",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name:       ModelName("test-validation"),
-						AccessMode: ModelImageAccessModePrivate,
-					},
-					PresetOptions: PresetOptions{},
+			name: "Template Inference",
+			inference: &InferenceSpec{
+				Template: &v1.PodTemplateSpec{},
+				Autoscaling: &AutoscalingSpec{
+					MaxCount: 2, Metric: AutoscalingMetricPendingRequests, TargetValue: 5,
 				},
 			},
-			errContent: "When AccessMode is private, an image must be provided",
-			expectErrs: true,
+			vllmEnabled: true,
+			errContent:  "only supported for preset models",
+			expectErrs:  true,
 		},
 		{
-			name: "Private Preset With Public AccessMode",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name: ModelName("private-test-validation"),
-					},
-					PresetOptions: PresetOptions{},
-				},
-			},
-			errContent: "This preset only supports private AccessMode, AccessMode must be private to continue",
-			expectErrs: true,
+			name: "Transformers Runtime",
+			inference: presetInference("test-validation", &AutoscalingSpec{
+				MaxCount: 2, Metric: AutoscalingMetricPendingRequests, TargetValue: 5,
+			}),
+			annotations: map[string]string{AnnotationWorkspaceRuntime: string(model.RuntimeNameHuggingfaceTransformers)},
+			vllmEnabled: true,
+			errContent:  "requires the vllm runtime",
+			expectErrs:  true,
 		},
 		{
-			name: "Adapeters more than 10",
-			inferenceSpec: func() *InferenceSpec {
-				spec := &InferenceSpec{
-					Preset: &PresetSpec{
-						PresetMeta: PresetMeta{
-							Name:       ModelName("test-validation"),
-							AccessMode: ModelImageAccessModePublic,
-						},
-					},
-				}
+			name: "VLLM Disabled",
+			inference: presetInference("test-validation", &AutoscalingSpec{
+				MaxCount: 2, Metric: AutoscalingMetricPendingRequests, TargetValue: 5,
+			}),
+			vllmEnabled: false,
+			errContent:  "requires the vllm runtime",
+			expectErrs:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			original := featuregates.FeatureGates[consts.FeatureFlagVLLM]
+			defer func() { featuregates.FeatureGates[consts.FeatureFlagVLLM] = original }()
+			featuregates.FeatureGates[consts.FeatureFlagVLLM] = tc.vllmEnabled
+
+			w := &Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: tc.annotations},
+				Inference:  tc.inference,
+			}
+			errs := tc.inference.Autoscaling.validate(w)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+			if hasErrs && tc.errContent != "" && !strings.Contains(errs.Error(), tc.errContent) {
+				t.Errorf("validate() error message = %v, expected to contain = %v", errs.Error(), tc.errContent)
+			}
+		})
+	}
+}
+
+func TestIdlePolicySpecValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		idlePolicy *IdlePolicySpec
+		expectErrs bool
+	}{
+		{
+			name:       "Valid Idle Minutes",
+			idlePolicy: &IdlePolicySpec{IdleMinutes: 30},
+			expectErrs: false,
+		},
+		{
+			name:       "Zero Idle Minutes",
+			idlePolicy: &IdlePolicySpec{},
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.idlePolicy.validate()
+			if hasErrs := errs != nil; hasErrs != tc.expectErrs {
+				t.Errorf("validate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+		})
+	}
+}
+
+func TestScheduleSpecValidate(t *testing.T) {
+	weekdays := ScheduleWindow{Start: "0 8 * * 1-5", Duration: metav1.Duration{Duration: 12 * time.Hour}}
+	tests := []struct {
+		name       string
+		schedule   *ScheduleSpec
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name:       "Valid Schedule",
+			schedule:   &ScheduleSpec{Windows: []ScheduleWindow{weekdays}, TimeZone: "Europe/Berlin", LeadMinutes: 15},
+			expectErrs: false,
+		},
+		{
+			name:       "No Windows",
+			schedule:   &ScheduleSpec{},
+			errContent: "missing field(s): windows",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Cron Expression",
+			schedule: &ScheduleSpec{Windows: []ScheduleWindow{
+				weekdays,
+				{Start: "every morning", Duration: metav1.Duration{Duration: time.Hour}},
+			}},
+			errContent: "windows[1].start",
+			expectErrs: true,
+		},
+		{
+			name:       "Zero Duration",
+			schedule:   &ScheduleSpec{Windows: []ScheduleWindow{{Start: "0 8 * * *"}}},
+			errContent: "duration must be positive",
+			expectErrs: true,
+		},
+		{
+			name:       "Unknown Time Zone",
+			schedule:   &ScheduleSpec{Windows: []ScheduleWindow{weekdays}, TimeZone: "Mars/Olympus"},
+			errContent: "unknown time zone Mars/Olympus",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.schedule.validate()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+			if hasErrs && !strings.Contains(errs.Error(), tc.errContent) {
+				t.Errorf("validate() error = %v, expected to contain %s", errs, tc.errContent)
+			}
+		})
+	}
+}
+
+func TestInferenceSpecValidateCreate(t *testing.T) {
+	RegisterValidationTestModels()
+	tests := []struct {
+		name          string
+		inferenceSpec *InferenceSpec
+		errContent    string // Content expected error to include, if any
+		expectErrs    bool
+	}{
+		{
+			name: "Invalid Preset Name",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("Invalid-Preset-Name"),
+					},
+				},
+			},
+			errContent: "model is not registered",
+			expectErrs: true,
+		},
+		{
+			name: "Only Template set",
+			inferenceSpec: &InferenceSpec{
+				Template: &v1.PodTemplateSpec{}, // Assuming a non-nil TemplateSpec implies it's set
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Released Preset Version",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("test-validation"),
+					},
+					PresetOptions: PresetOptions{
+						Version: "0.0.1",
+					},
+				},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Unreleased Preset Version",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("test-validation"),
+					},
+					PresetOptions: PresetOptions{
+						Version: "0.0.9",
+					},
+				},
+			},
+			errContent: "Unsupported version 0.0.9 of preset test-validation, released versions are 0.0.2, 0.0.1",
+			expectErrs: true,
+		},
+		{
+			name: "Version of Private Preset",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name:       ModelName("private-test-validation"),
+						AccessMode: ModelImageAccessModePrivate,
+					},
+					PresetOptions: PresetOptions{
+						Image:   "private-image:latest",
+						Version: "0.0.1",
+					},
+				},
+			},
+			errContent: "version of a preset with a private image cannot be set",
+			expectErrs: true,
+		},
+		{
+			name:          "Preset and Template Unset",
+			inferenceSpec: &InferenceSpec{},
+			errContent:    "Preset or Template must be specified",
+			expectErrs:    true,
+		},
+		{
+			name: "Preset and Template Set",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("test-validation"),
+					},
+				},
+				Template: &v1.PodTemplateSpec{}, // Assuming a non-nil TemplateSpec implies it's set
+			},
+			errContent: "Preset and Template cannot be set at the same time",
+			expectErrs: true,
+		},
+		{
+			name: "Private Access Without Image",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name:       ModelName("test-validation"),
+						AccessMode: ModelImageAccessModePrivate,
+					},
+					PresetOptions: PresetOptions{},
+				},
+			},
+			errContent: "When AccessMode is private, an image must be provided",
+			expectErrs: true,
+		},
+		{
+			name: "Private Preset With Public AccessMode",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("private-test-validation"),
+					},
+					PresetOptions: PresetOptions{},
+				},
+			},
+			errContent: "This preset only supports private AccessMode, AccessMode must be private to continue",
+			expectErrs: true,
+		},
+		{
+			name: "Adapeters more than 10",
+			inferenceSpec: func() *InferenceSpec {
+				spec := &InferenceSpec{
+					Preset: &PresetSpec{
+						PresetMeta: PresetMeta{
+							Name:       ModelName("test-validation"),
+							AccessMode: ModelImageAccessModePublic,
+						},
+					},
+				}
 				for i := 1; i <= 11; i++ {
 					spec.Adapters = append(spec.Adapters, AdapterSpec{
 						Source: &DataSource{
@@ -625,6 +1196,63 @@ func TestInferenceSpecValidateCreate(t *testing.T) {
 			errContent: "",
 			expectErrs: true,
 		},
+		{
+			name: "Hugging Face Preset Without Model",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName(huggingface.PresetName),
+					},
+				},
+			},
+			errContent: "missing field(s): presetOptions.huggingFace",
+			expectErrs: true,
+		},
+		{
+			name: "Hugging Face Preset With Invalid Model ID",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName(huggingface.PresetName),
+					},
+					PresetOptions: PresetOptions{
+						HuggingFace: &HuggingFaceModelSpec{ModelID: "https://huggingface.co/org/model"},
+					},
+				},
+			},
+			errContent: "Invalid model ID https://huggingface.co/org/model",
+			expectErrs: true,
+		},
+		{
+			name: "Hugging Face Model With Other Preset",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("test-validation"),
+					},
+					PresetOptions: PresetOptions{
+						HuggingFace: &HuggingFaceModelSpec{ModelID: "org/model"},
+					},
+				},
+			},
+			errContent: "presetOptions.huggingFace is only supported by the huggingface preset",
+			expectErrs: true,
+		},
+		{
+			name: "Valid Hugging Face Preset",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName(huggingface.PresetName),
+					},
+					PresetOptions: PresetOptions{
+						HuggingFace: &HuggingFaceModelSpec{ModelID: "mistralai/Mistral-7B-Instruct-v0.3", Revision: "v1.0"},
+					},
+				},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
 		{
 			name: "Valid Preset",
 			inferenceSpec: &InferenceSpec{
@@ -768,6 +1396,7 @@ func TestAdapterSpecValidateCreateorUpdate(t *testing.T) {
 }
 
 func TestInferenceSpecValidateUpdate(t *testing.T) {
+	RegisterValidationTestModels()
 	tests := []struct {
 		name         string
 		newInference *InferenceSpec
@@ -794,6 +1423,39 @@ func TestInferenceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Preset Version Changed",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta:    PresetMeta{Name: ModelName("test-validation")},
+					PresetOptions: PresetOptions{Version: "0.0.2"},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta:    PresetMeta{Name: ModelName("test-validation")},
+					PresetOptions: PresetOptions{Version: "0.0.1"},
+				},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Preset Version Changed To Unreleased Version",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta:    PresetMeta{Name: ModelName("test-validation")},
+					PresetOptions: PresetOptions{Version: "0.0.9"},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{Name: ModelName("test-validation")},
+				},
+			},
+			errContent: "Unsupported version 0.0.9",
+			expectErrs: true,
+		},
 		{
 			name: "Template Unset",
 			newInference: &InferenceSpec{
@@ -873,13 +1535,175 @@ func TestInferenceSpecValidateUpdate(t *testing.T) {
 	}
 }
 
-func TestWorkspaceValidateCreate(t *testing.T) {
-	tests := []struct {
-		name      string
-		workspace *Workspace
-		wantErr   bool
-		errField  string
-	}{
+type testModelTransformers struct {
+	testModelStatic
+}
+
+func (*testModelTransformers) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "16Gi",
+		PerGPUMemoryRequirement:   "16Gi",
+		RuntimeParam: model.RuntimeParam{
+			Transformers: model.HuggingfaceTransformersParam{BaseCommand: "accelerate launch"},
+		},
+	}
+}
+
+func TestInferenceSpecValidateRuntime(t *testing.T) {
+	RegisterValidationTestModels()
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     "test-validation-transformers",
+		Instance: &testModelTransformers{},
+	})
+	tests := []struct {
+		name        string
+		inference   *InferenceSpec
+		runtime     model.RuntimeName
+		vllmEnabled bool
+		errContent  string // Content expected error to include, if any
+	}{
+		{
+			name:        "Preset served by the runtime",
+			inference:   &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation-static"}}, Runtime: "vllm"},
+			runtime:     model.RuntimeNameVLLM,
+			vllmEnabled: true,
+		},
+		{
+			name:        "Unsupported runtime",
+			inference:   &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation-static"}}, Runtime: "unknown"},
+			runtime:     "unknown",
+			vllmEnabled: true,
+			errContent:  "Unsupported runtime unknown, supported runtimes are llamacpp, transformers, vllm",
+		},
+		{
+			name: "Pinned version served by the runtime",
+			inference: &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation-static"},
+				PresetOptions: PresetOptions{Version: "0.0.2"}}, Runtime: "vllm"},
+			runtime:     model.RuntimeNameVLLM,
+			vllmEnabled: true,
+		},
+		{
+			name: "Pinned version that predates the runtime",
+			inference: &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation-static"},
+				PresetOptions: PresetOptions{Version: "0.0.1"}}, Runtime: "vllm"},
+			runtime:     model.RuntimeNameVLLM,
+			vllmEnabled: true,
+			errContent:  "Version 0.0.1 of preset test-validation-static cannot be served by the vllm runtime",
+		},
+		{
+			name:        "Preset without the parameters of the runtime",
+			inference:   &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation-transformers"}}, Runtime: "vllm"},
+			runtime:     model.RuntimeNameVLLM,
+			vllmEnabled: true,
+			errContent:  "cannot be served by the vllm runtime",
+		},
+		{
+			name:        "VLLM Disabled",
+			inference:   &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation-static"}}, Runtime: "vllm"},
+			runtime:     model.RuntimeNameVLLM,
+			vllmEnabled: false,
+			errContent:  "disabled by the vLLM feature gate",
+		},
+		{
+			name:        "Runtime of a template",
+			inference:   &InferenceSpec{Template: &v1.PodTemplateSpec{}, Runtime: "vllm"},
+			runtime:     model.RuntimeNameVLLM,
+			vllmEnabled: true,
+			errContent:  "only supported for preset models",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			original := featuregates.FeatureGates[consts.FeatureFlagVLLM]
+			defer func() { featuregates.FeatureGates[consts.FeatureFlagVLLM] = original }()
+			featuregates.FeatureGates[consts.FeatureFlagVLLM] = tc.vllmEnabled
+
+			errs := tc.inference.validateRuntime(tc.runtime)
+			if tc.errContent == "" {
+				if errs != nil {
+					t.Errorf("validateRuntime() errors = %v, expected none", errs)
+				}
+			} else if errs == nil || !strings.Contains(errs.Error(), tc.errContent) {
+				t.Errorf("validateRuntime() errors = %v, expected to contain %s", errs, tc.errContent)
+			}
+		})
+	}
+}
+
+func TestGetWorkspaceRuntimeName(t *testing.T) {
+	RegisterValidationTestModels()
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     "test-validation-transformers",
+		Instance: &testModelTransformers{},
+	})
+	tests := []struct {
+		name            string
+		preset          ModelName
+		version         string
+		runtime         string
+		annotations     map[string]string
+		expectedRuntime model.RuntimeName
+	}{
+		{
+			name:            "Runtime of the inference spec takes precedence over the annotation",
+			preset:          "test-validation-static",
+			runtime:         "vllm",
+			annotations:     map[string]string{AnnotationWorkspaceRuntime: "transformers"},
+			expectedRuntime: model.RuntimeNameVLLM,
+		},
+		{
+			name:            "Runtime of the annotation",
+			preset:          "test-validation-static",
+			annotations:     map[string]string{AnnotationWorkspaceRuntime: "transformers"},
+			expectedRuntime: model.RuntimeNameHuggingfaceTransformers,
+		},
+		{
+			name:            "vLLM by default",
+			preset:          "test-validation-static",
+			expectedRuntime: model.RuntimeNameVLLM,
+		},
+		{
+			name:            "transformers for the presets that vLLM cannot serve",
+			preset:          "test-validation-transformers",
+			expectedRuntime: model.RuntimeNameHuggingfaceTransformers,
+		},
+		{
+			name:            "vLLM for a pinned version that supports it",
+			preset:          "test-validation-static",
+			version:         "0.0.2",
+			expectedRuntime: model.RuntimeNameVLLM,
+		},
+		{
+			name:            "transformers for a pinned version that predates vLLM",
+			preset:          "test-validation-static",
+			version:         "0.0.1",
+			expectedRuntime: model.RuntimeNameHuggingfaceTransformers,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := &Workspace{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Inference: &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: tc.preset},
+					PresetOptions: PresetOptions{Version: tc.version}}, Runtime: tc.runtime},
+			}
+			if got := GetWorkspaceRuntimeName(ws); got != tc.expectedRuntime {
+				t.Errorf("GetWorkspaceRuntimeName() = %v, expected %v", got, tc.expectedRuntime)
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace *Workspace
+		wantErr   bool
+		errField  string
+	}{
 		{
 			name:      "Neither Inference nor Tuning specified",
 			workspace: &Workspace{},
@@ -1071,6 +1895,7 @@ func TestTuningSpecValidateCreate(t *testing.T) {
 	// Create fake client with default ConfigMap
 	scheme := runtime.NewScheme()
 	_ = v1.AddToScheme(scheme)
+	_ = AddToScheme(scheme)
 	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(defaultConfigMapManifest(), qloraConfigMapManifest()).Build()
 	k8sclient.SetGlobalClient(client)
 	// Include client in ctx
@@ -1104,6 +1929,28 @@ func TestTuningSpecValidateCreate(t *testing.T) {
 			wantErr:   false,
 			errFields: nil,
 		},
+		{
+			name: "Artifact output without push secret",
+			tuningSpec: &TuningSpec{
+				Input:  &DataSource{Name: "valid-input", Image: "AZURE_ACR.azurecr.io/test:0.0.0"},
+				Output: &DataDestination{Image: "localhost:5000/adapter:0.0.1", ImageFormat: ImageFormatArtifact},
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Method: TuningMethodLora,
+			},
+			wantErr:   false,
+			errFields: nil,
+		},
+		{
+			name: "Artifact input",
+			tuningSpec: &TuningSpec{
+				Input:  &DataSource{Name: "valid-input", Image: "AZURE_ACR.azurecr.io/test:0.0.0", ImageFormat: ImageFormatArtifact},
+				Output: &DataDestination{Image: "AZURE_ACR.azurecr.io/test:0.0.0", ImagePushSecret: "secret"},
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Method: TuningMethodLora,
+			},
+			wantErr:   true,
+			errFields: []string{"Input.ImageFormat"},
+		},
 		{
 			name: "Missing Input",
 			tuningSpec: &TuningSpec{
@@ -1145,6 +1992,17 @@ func TestTuningSpecValidateCreate(t *testing.T) {
 			wantErr:   true,
 			errFields: []string{"presetName"},
 		},
+		{
+			name: "Preset Without Tuning Support",
+			tuningSpec: &TuningSpec{
+				Input:  &DataSource{Name: "valid-input", Image: "AZURE_ACR.azurecr.io/test:0.0.0"},
+				Output: &DataDestination{Image: "AZURE_ACR.azurecr.io/test:0.0.0", ImagePushSecret: "secret"},
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation-distributed")}},
+				Method: TuningMethodLora,
+			},
+			wantErr:   true,
+			errFields: []string{"presetName"},
+		},
 		{
 			name: "Invalid Method",
 			tuningSpec: &TuningSpec{
@@ -1319,15 +2177,132 @@ func TestDataSourceValidateCreate(t *testing.T) {
 			wantErr:    true,
 			errField:   "Exactly one of URLs, Volume, or Image must be specified",
 		},
-		// {
-		// 	name: "URLs and Volume specified",
-		// 	dataSource: &DataSource{
-		// 		URLs:   []string{"http://example.com/data"},
-		// 		Volume: &v1.VolumeSource{},
-		// 	},
-		// 	wantErr:  true,
-		// 	errField: "Exactly one of URLs, Volume, or Image must be specified",
-		// },
+		{
+			name: "URLs and Volume specified",
+			dataSource: &DataSource{
+				URLs: []string{"http://example.com/data"},
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+			},
+			wantErr:  true,
+			errField: "Exactly one of URLs, Volume, or Image must be specified",
+		},
+		{
+			name: "PVC volume specified only",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+				SubPath: "datasets/alpaca",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Azure file volume without share name",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					AzureFile: &v1.AzureFileVolumeSource{SecretName: "azure-secret"},
+				},
+			},
+			wantErr:  true,
+			errField: "shareName",
+		},
+		{
+			name: "NFS volume specified only",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					NFS: &v1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/data"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unsupported volume source",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					HostPath: &v1.HostPathVolumeSource{Path: "/data"},
+				},
+			},
+			wantErr:  true,
+			errField: "Unsupported volume source",
+		},
+		{
+			name: "Empty volume source",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{},
+			},
+			wantErr:  true,
+			errField: "Exactly one volume source must be specified",
+		},
+		{
+			name: "Artifact image specified",
+			dataSource: &DataSource{
+				Image:            "aimodels.azurecr.io/adapter:0.0.1",
+				ImagePullSecrets: []string{"imagePullSecret"},
+				ImageFormat:      ImageFormatArtifact,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Artifact image with several pull secrets",
+			dataSource: &DataSource{
+				Image:            "aimodels.azurecr.io/adapter:0.0.1",
+				ImagePullSecrets: []string{"imagePullSecret1", "imagePullSecret2"},
+				ImageFormat:      ImageFormatArtifact,
+			},
+			wantErr:  true,
+			errField: "Only one image pull secret can be used to pull an artifact",
+		},
+		{
+			name: "Unsupported image format",
+			dataSource: &DataSource{
+				Image:       "aimodels.azurecr.io/adapter:0.0.1",
+				ImageFormat: "helm",
+			},
+			wantErr:  true,
+			errField: "Unsupported image format helm",
+		},
+		{
+			name: "Image format without image",
+			dataSource: &DataSource{
+				URLs:        []string{"http://example.com/data"},
+				ImageFormat: ImageFormatContainer,
+			},
+			wantErr:  true,
+			errField: "ImageFormat can only be specified with Image",
+		},
+		{
+			name: "SubPath without volume",
+			dataSource: &DataSource{
+				URLs:    []string{"http://example.com/data"},
+				SubPath: "data",
+			},
+			wantErr:  true,
+			errField: "SubPath can only be specified with Volume",
+		},
+		{
+			name: "SubPath outside the volume",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+				SubPath: "data/../../etc",
+			},
+			wantErr:  true,
+			errField: "SubPath must not contain '..'",
+		},
+		{
+			name: "Absolute SubPath",
+			dataSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+				SubPath: "/data",
+			},
+			wantErr:  true,
+			errField: "SubPath must be a relative path",
+		},
 		{
 			name: "All fields specified",
 			dataSource: &DataSource{
@@ -1390,6 +2365,36 @@ func TestDataSourceValidateUpdate(t *testing.T) {
 			wantErr:   true,
 			errFields: []string{"Name"},
 		},
+		{
+			name: "Volume changed",
+			oldSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+			},
+			newSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "new-dataset-pvc"},
+				},
+				SubPath: "train",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid volume",
+			oldSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+			},
+			newSource: &DataSource{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{},
+				},
+			},
+			wantErr:   true,
+			errFields: []string{"claimName"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1425,13 +2430,52 @@ func TestDataDestinationValidateCreate(t *testing.T) {
 			wantErr:         true,
 			errField:        "At least one of Volume or Image must be specified",
 		},
-		// {
-		// 	name: "Volume specified only",
-		// 	dataDestination: &DataDestination{
-		// 		Volume: &v1.VolumeSource{},
-		// 	},
-		// 	wantErr: false,
-		// },
+		{
+			name: "Volume specified only",
+			dataDestination: &DataDestination{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "results-pvc"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Azure disk volume without disk URI",
+			dataDestination: &DataDestination{
+				Volume: &v1.VolumeSource{
+					AzureDisk: &v1.AzureDiskVolumeSource{DiskName: "results-disk"},
+				},
+			},
+			wantErr:  true,
+			errField: "diskURI",
+		},
+		{
+			name: "Artifact image without push secret",
+			dataDestination: &DataDestination{
+				Image:       "localhost:5000/adapter:0.0.1",
+				ImageFormat: ImageFormatArtifact,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Container image without push secret",
+			dataDestination: &DataDestination{
+				Image:       "aimodels.azurecr.io/adapter:0.0.1",
+				ImageFormat: ImageFormatContainer,
+			},
+			wantErr:  true,
+			errField: "Must specify imagePushSecret with destination image",
+		},
+		{
+			name: "Unsupported volume source",
+			dataDestination: &DataDestination{
+				Volume: &v1.VolumeSource{
+					EmptyDir: &v1.EmptyDirVolumeSource{},
+				},
+			},
+			wantErr:  true,
+			errField: "Unsupported volume source",
+		},
 		{
 			name: "Image specified only",
 			dataDestination: &DataDestination{
@@ -1456,15 +2500,17 @@ func TestDataDestinationValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
-		// {
-		// 	name: "Both fields specified",
-		// 	dataDestination: &DataDestination{
-		// 		Volume:          &v1.VolumeSource{},
-		// 		Image:           "aimodels.azurecr.io/data-image:latest",
-		// 		ImagePushSecret: "imagePushSecret",
-		// 	},
-		// 	wantErr: false,
-		// },
+		{
+			name: "Both fields specified",
+			dataDestination: &DataDestination{
+				Volume: &v1.VolumeSource{
+					NFS: &v1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/results"},
+				},
+				Image:           "aimodels.azurecr.io/data-image:latest",
+				ImagePushSecret: "imagePushSecret",
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1505,6 +2551,20 @@ func TestDataDestinationValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Invalid volume",
+			oldDest: &DataDestination{
+				Image:           "old-image:latest",
+				ImagePushSecret: "old-secret",
+			},
+			newDest: &DataDestination{
+				Volume: &v1.VolumeSource{
+					NFS: &v1.NFSVolumeSource{Path: "/exports/results"},
+				},
+			},
+			wantErr:   true,
+			errFields: []string{"server"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1526,3 +2586,313 @@ func TestDataDestinationValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckpointSpecValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkpoint *CheckpointSpec
+		wantErr    bool
+		errFields  []string
+	}{
+		{
+			name: "Valid checkpoint",
+			checkpoint: &CheckpointSpec{
+				Interval: 100,
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints"},
+				},
+			},
+		},
+		{
+			name: "Default interval",
+			checkpoint: &CheckpointSpec{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints"},
+				},
+			},
+		},
+		{
+			name: "Negative interval",
+			checkpoint: &CheckpointSpec{
+				Interval: -1,
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints"},
+				},
+			},
+			wantErr:   true,
+			errFields: []string{"Interval"},
+		},
+		{
+			name:       "Missing volume",
+			checkpoint: &CheckpointSpec{Interval: 100},
+			wantErr:    true,
+			errFields:  []string{"Volume"},
+		},
+		{
+			name: "Ephemeral volume",
+			checkpoint: &CheckpointSpec{
+				Volume: &v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+			},
+			wantErr:   true,
+			errFields: []string{"Unsupported volume source"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.checkpoint.validate()
+			hasErrs := errs != nil
+
+			if hasErrs != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", errs, tt.wantErr)
+			}
+
+			if hasErrs {
+				for _, field := range tt.errFields {
+					if !strings.Contains(errs.Error(), field) {
+						t.Errorf("validate() expected errors to contain field %s, but got %s", field, errs.Error())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateRollbackAnnotation(t *testing.T) {
+	testWorkspace := &Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: "kaito",
+		},
+		Resource: ResourceSpec{
+			InstanceType: "Standard_NC12s_v3",
+			Count:        pointerToInt(1),
+		},
+		Inference: &InferenceSpec{
+			Preset: &PresetSpec{
+				PresetMeta: PresetMeta{
+					Name: ModelName("test-validation-static"),
+				},
+			},
+		},
+	}
+	RegisterValidationTestModels()
+	os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	tests := []struct {
+		name     string
+		revision string
+		wantErr  bool
+	}{
+		{
+			name:     "Valid revision",
+			revision: "2",
+			wantErr:  false,
+		},
+		{
+			name:     "Revision is not a number",
+			revision: "latest",
+			wantErr:  true,
+		},
+		{
+			name:     "Revision is not positive",
+			revision: "0",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := testWorkspace.DeepCopy()
+			workspace.Annotations = map[string]string{AnnotationRollbackToRevision: tt.revision}
+			errs := workspace.Validate(context.Background())
+			if (errs != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", errs, tt.wantErr)
+			}
+			if errs != nil && !strings.Contains(errs.Error(), AnnotationRollbackToRevision) {
+				t.Errorf("Validate() expected error to mention %s, but got %s", AnnotationRollbackToRevision, errs.Error())
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateUpgradePresetAnnotation(t *testing.T) {
+	testWorkspace := &Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: "kaito",
+		},
+		Resource: ResourceSpec{
+			InstanceType: "Standard_NC12s_v3",
+			Count:        pointerToInt(1),
+		},
+		Inference: &InferenceSpec{
+			Preset: &PresetSpec{
+				PresetMeta: PresetMeta{
+					Name: ModelName("test-validation-static"),
+				},
+			},
+		},
+	}
+	RegisterValidationTestModels()
+	os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	tests := []struct {
+		name    string
+		version string
+		runtime string
+		wantErr bool
+	}{
+		{
+			name:    "Latest version",
+			version: PresetUpgradeLatest,
+			wantErr: false,
+		},
+		{
+			name:    "Released version",
+			version: "0.0.2",
+			wantErr: false,
+		},
+		{
+			name:    "Unreleased version",
+			version: "0.0.9",
+			wantErr: true,
+		},
+		{
+			name:    "Released version that predates the selected runtime",
+			version: "0.0.1",
+			runtime: string(model.RuntimeNameVLLM),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := testWorkspace.DeepCopy()
+			workspace.Annotations = map[string]string{AnnotationUpgradePreset: tt.version}
+			workspace.Inference.Runtime = tt.runtime
+			errs := workspace.Validate(context.Background())
+			if (errs != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", errs, tt.wantErr)
+			}
+			if errs != nil && !strings.Contains(errs.Error(), AnnotationUpgradePreset) {
+				t.Errorf("Validate() expected error to mention %s, but got %s", AnnotationUpgradePreset, errs.Error())
+			}
+		})
+	}
+}
+
+func TestGetWorkspaceInstanceType(t *testing.T) {
+	tests := []struct {
+		name         string
+		resource     ResourceSpec
+		statusType   string
+		expectedType string
+	}{
+		{
+			name:         "Primary instance type is used when none is recorded",
+			resource:     ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4", FallbackInstanceTypes: []string{"Standard_NC48ads_A100_v4"}},
+			expectedType: "Standard_NC24ads_A100_v4",
+		},
+		{
+			name:         "Fallback instance type recorded in the status is used",
+			resource:     ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4", FallbackInstanceTypes: []string{"Standard_NC48ads_A100_v4"}},
+			statusType:   "Standard_NC48ads_A100_v4",
+			expectedType: "Standard_NC48ads_A100_v4",
+		},
+		{
+			name:         "Recorded instance type that is no longer a candidate is ignored",
+			resource:     ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+			statusType:   "Standard_NC48ads_A100_v4",
+			expectedType: "Standard_NC24ads_A100_v4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := &Workspace{Resource: tc.resource, Status: WorkspaceStatus{InstanceType: tc.statusType}}
+			if got := GetWorkspaceInstanceType(ws); got != tc.expectedType {
+				t.Errorf("GetWorkspaceInstanceType() = %v, expected %v", got, tc.expectedType)
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateScale(t *testing.T) {
+	RegisterValidationTestModels()
+	newWorkspace := func(preset string, count int, replicas *int32) *Workspace {
+		w := &Workspace{
+			Resource:  ResourceSpec{Count: pointerToInt(count)},
+			Inference: &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName(preset)}}},
+		}
+		if replicas != nil {
+			w.Spec = &ScaleSpec{Replicas: replicas}
+		}
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		workspace  *Workspace
+		old        *Workspace
+		errContent string
+	}{
+		{
+			name:      "Replicas equal to the count",
+			workspace: newWorkspace("test-validation", 2, pointerToInt32(2)),
+		},
+		{
+			name:       "Replicas different from the count on create",
+			workspace:  newWorkspace("test-validation", 2, pointerToInt32(3)),
+			errContent: "replicas 3 must be equal to resource.count 2",
+		},
+		{
+			name:       "Replicas of distributed inference",
+			workspace:  newWorkspace("test-validation-distributed", 2, pointerToInt32(2)),
+			errContent: "replicas are only supported for inference workspaces that run one replica per node",
+		},
+		{
+			name:      "Replicas changed on update",
+			workspace: newWorkspace("test-validation", 2, pointerToInt32(3)),
+			old:       newWorkspace("test-validation", 2, pointerToInt32(2)),
+		},
+		{
+			name:      "Count and replicas changed together on update",
+			workspace: newWorkspace("test-validation", 3, pointerToInt32(3)),
+			old:       newWorkspace("test-validation", 2, pointerToInt32(2)),
+		},
+		{
+			name:      "Count changed without the replicas on update",
+			workspace: newWorkspace("test-validation", 3, pointerToInt32(2)),
+			old:       newWorkspace("test-validation", 2, pointerToInt32(2)),
+		},
+		{
+			name:       "Count and replicas changed to different values on update",
+			workspace:  newWorkspace("test-validation", 3, pointerToInt32(4)),
+			old:        newWorkspace("test-validation", 2, pointerToInt32(2)),
+			errContent: "replicas 4 must be equal to resource.count 3 when both are changed",
+		},
+		{
+			name:      "Count changed before the replicas are set",
+			workspace: newWorkspace("test-validation", 3, nil),
+			old:       newWorkspace("test-validation", 2, nil),
+		},
+		{
+			name:       "Replicas scaled to zero",
+			workspace:  newWorkspace("test-validation", 2, pointerToInt32(0)),
+			old:        newWorkspace("test-validation", 2, pointerToInt32(2)),
+			errContent: "replicas must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.workspace.validateScale(tt.old)
+			if tt.errContent == "" {
+				if errs != nil {
+					t.Errorf("validateScale() errors = %v, expected none", errs)
+				}
+			} else if errs == nil || !strings.Contains(errs.Error(), tt.errContent) {
+				t.Errorf("validateScale() errors = %v, expected to contain %s", errs, tt.errContent)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/activator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+func main() {
+	var upstream string
+	var namespace string
+	var upstreamSelector string
+	var bindAddr string
+	var statsAddr string
+	var holdTimeout time.Duration
+	flag.StringVar(&upstream, "upstream", "", "The URL of the workspace service the requests are forwarded to.")
+	flag.StringVar(&namespace, "namespace", "", "The namespace of the workspace.")
+	flag.StringVar(&upstreamSelector, "upstream-selector", "",
+		"The label selector of the pods backing the workspace service, whose readiness tells whether the requests can be forwarded.")
+	flag.StringVar(&bindAddr, "bind-address", ":8080", "The address the proxy binds to.")
+	flag.StringVar(&statsAddr, "stats-bind-address", ":8081", "The address the stats and health endpoints bind to.")
+	flag.DurationVar(&holdTimeout, "hold-timeout", 15*time.Minute,
+		"The maximum time a request is held while the workspace is brought back.")
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	upstreamURL, err := url.Parse(upstream)
+	if err != nil || upstreamURL.Host == "" {
+		klog.ErrorS(err, "invalid upstream URL", "upstream", upstream)
+		os.Exit(1)
+	}
+	selector, err := labels.Parse(upstreamSelector)
+	if err != nil || selector.Empty() {
+		klog.ErrorS(err, "invalid upstream selector", "selector", upstreamSelector)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		klog.ErrorS(err, "failed to get the kubeconfig")
+		os.Exit(1)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		klog.ErrorS(err, "failed to create the kubernetes client")
+		os.Exit(1)
+	}
+	// Only the pods backing the workspace service are watched.
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector.String()
+		}))
+	podLister := informerFactory.Core().V1().Pods().Lister().Pods(namespace)
+	informerFactory.Start(ctx.Done())
+	for _, synced := range informerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			klog.Error("failed to sync the pods of the workspace")
+			os.Exit(1)
+		}
+	}
+
+	a := activator.New(upstreamURL, holdTimeout, podLister, selector)
+	statsMux := http.NewServeMux()
+	statsMux.Handle(activator.StatsPath, a.StatsHandler())
+	statsMux.HandleFunc(activator.HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	servers := []*http.Server{
+		{Addr: bindAddr, Handler: a, ReadHeaderTimeout: 30 * time.Second},
+		{Addr: statsAddr, Handler: statsMux, ReadHeaderTimeout: 30 * time.Second},
+	}
+
+	errCh := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server *http.Server) {
+			klog.InfoS("starting server", "address", server.Addr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(server)
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		klog.ErrorS(err, "server failed")
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.ErrorS(err, "failed to shut down server", "address", server.Addr)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	awsv1beta1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -23,6 +24,7 @@ import (
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
 	"github.com/kaito-project/kaito/pkg/workspace/controllers"
 	"github.com/kaito-project/kaito/pkg/workspace/webhooks"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/webhook"
@@ -36,6 +38,8 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -70,6 +74,14 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == RecommendSKUsCommand {
+		if err := recommendSKUs(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			klog.ErrorS(err, "unable to recommend instance types")
+			exitWithErrorFunc()
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var enableWebhook bool
@@ -93,14 +105,34 @@ func main() {
 
 	ctx := withShutdownSignal(context.Background())
 
+	// The SKU catalog ConfigMap lives in the release namespace, without which only the built-in SKUs are supported.
+	releaseNamespace, err := utils.GetReleaseNamespace()
+	if err != nil {
+		klog.ErrorS(err, "unable to get the release namespace, the SKU catalog is disabled")
+	}
+	var cacheOptions cache.Options
+	if releaseNamespace != "" {
+		// Only the SKU catalog is watched among the ConfigMaps.
+		cacheOptions.ByObject = controllers.SKUCatalogCacheByObject(releaseNamespace)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
 		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "ef60f9b0.io",
+		Cache:                  cacheOptions,
+		// Secrets are read on demand for the tokens of Hugging Face models, so they are not cached, which would
+		// require watching all the secrets of the cluster. Likewise, ConfigMaps are read on demand for the tuning
+		// configs, the cache only holds the SKU catalog.
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}},
+			},
+		},
+		LeaderElection:   enableLeaderElection,
+		LeaderElectionID: "ef60f9b0.io",
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -132,6 +164,27 @@ func main() {
 		klog.ErrorS(err, "unable to create controller", "controller", "Workspace")
 		exitWithErrorFunc()
 	}
+
+	modelPresetReconciler := controllers.NewModelPresetReconciler(
+		kClient,
+		mgr.GetEventRecorderFor("KAITO-ModelPreset-controller"),
+	)
+	if err = modelPresetReconciler.SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "ModelPreset")
+		exitWithErrorFunc()
+	}
+
+	if releaseNamespace != "" {
+		skuCatalogReconciler := controllers.NewSKUCatalogReconciler(
+			kClient,
+			mgr.GetEventRecorderFor("KAITO-SKUCatalog-controller"),
+			releaseNamespace,
+		)
+		if err = skuCatalogReconciler.SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "SKUCatalog")
+			exitWithErrorFunc()
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -143,6 +196,18 @@ func main() {
 		exitWithErrorFunc()
 	}
 
+	// Register the existing model presets before the webhook and the controllers start, the ModelPreset controller
+	// keeps them up to date afterwards.
+	if err := controllers.RegisterModelPresets(ctx, mgr.GetAPIReader()); err != nil {
+		klog.ErrorS(err, "unable to register model presets")
+	}
+	// Likewise, the SKU catalog is loaded before the webhook validates instance types against it.
+	if releaseNamespace != "" {
+		if err := controllers.LoadSKUCatalog(ctx, mgr.GetAPIReader(), releaseNamespace); err != nil {
+			klog.ErrorS(err, "unable to load the SKU catalog")
+		}
+	}
+
 	if enableWebhook {
 		klog.InfoS("starting webhook reconcilers")
 		p, err := strconv.Atoi(os.Getenv(WebhookServicePort))
@@ -168,7 +233,7 @@ func main() {
 		exitWithErrorFunc()
 	}
 
-	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] && utils.IsNodeProvisioningEnabled() {
 		err = nodeclaim.CheckNodeClass(ctx, kClient)
 		if err != nil {
 			exitWithErrorFunc()
@@ -3,7 +3,9 @@
 package main
 
 import (
+	_ "github.com/kaito-project/kaito/presets/workspace/models"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/falcon"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/huggingface"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/llama2"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/llama2chat"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/mistral"
@@ -0,0 +1,135 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/huggingface"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/sku/recommender"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/workspace/controllers"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RecommendSKUsCommand is the subcommand that prints the instance types that can run inference with a preset,
+// instead of starting the controller manager.
+const RecommendSKUsCommand = "recommend-skus"
+
+// clusterTimeout bounds the time to read the ModelPresets of the cluster.
+const clusterTimeout = 10 * time.Second
+
+// recommendSKUs runs the recommend-skus subcommand with the arguments following the subcommand.
+func recommendSKUs(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(RecommendSKUsCommand, flag.ContinueOnError)
+	fs.SetOutput(out)
+	presetName := fs.String("preset", "", "The name of the preset.")
+	runtime := fs.String("runtime", string(model.RuntimeNameVLLM), "The inference runtime, e.g., vllm or transformers.")
+	cloud := fs.String("cloud", "", "The cloud provider of the instance types, azure, aws or onprem for the nodes of the cluster. Azure and AWS if not specified.")
+	maxGPUs := fs.Int("max-gpus", 0, "The maximum number of GPUs per node. No limit if not specified.")
+	gpuFamily := fs.String("gpu-family", "", "The GPU model of the instance types, e.g., A100.")
+	nodeCount := fs.Int("node-count", 1, "The number of nodes.")
+	modelID := fs.String("model-id", "", "The model repository of the huggingface preset, e.g., mistralai/Mistral-7B-Instruct-v0.3.")
+	revision := fs.String("revision", huggingface.DefaultRevision, "The revision of the model repository of the huggingface preset.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// The ModelPresets of the cluster are registered before the preset is looked up.
+	connectToCluster(ctx)
+	if !plugin.IsValidPreset(*presetName) {
+		return fmt.Errorf("preset %q is not registered", *presetName)
+	}
+	presetModel := plugin.KaitoModelRegister.MustGet(*presetName)
+	if *presetName == huggingface.PresetName {
+		if *modelID == "" {
+			return fmt.Errorf("--model-id is required by the %s preset", huggingface.PresetName)
+		}
+		// The token of gated or private model repositories is read from the environment like in the Hugging Face CLI.
+		info, err := huggingface.GetModelInfo(ctx, *modelID, *revision, os.Getenv("HF_TOKEN"))
+		if err != nil {
+			return err
+		}
+		presetModel = huggingface.NewModel(presetModel, *modelID, info)
+	}
+
+	if model.GetRuntime(model.RuntimeName(*runtime)) == nil {
+		return fmt.Errorf("unsupported runtime %q, supported runtimes are %v", *runtime, model.RuntimeNames())
+	}
+
+	handlers := map[string]sku.CloudSKUHandler{}
+	clouds := []string{consts.AzureCloudName, consts.AWSCloudName}
+	if *cloud == consts.OnPremCloudName {
+		clouds = []string{consts.OnPremCloudName}
+	}
+	for _, c := range clouds {
+		if *cloud == "" || *cloud == c {
+			handlers[c] = sku.GetCloudSKUHandler(c)
+		}
+	}
+	if len(handlers) == 0 {
+		return fmt.Errorf("unsupported cloud provider %q", *cloud)
+	}
+
+	recommendations := recommender.Recommend(presetModel, model.RuntimeName(*runtime), recommender.Constraints{
+		MaxGPUs:   *maxGPUs,
+		GPUFamily: *gpuFamily,
+		NodeCount: *nodeCount,
+	}, handlers)
+	if len(recommendations) == 0 {
+		return fmt.Errorf("no instance type meets the requirements of preset %s", *presetName)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLOUD\tINSTANCE TYPE\tGPUS\tGPU MODEL\tGPU MEMORY\tREQUIRED\tHEADROOM")
+	for _, r := range recommendations {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%dGi\t%.1fGi\t%.1fGi\n", r.Cloud, r.SKU, r.GPUCount, r.GPUModel, r.GPUMem,
+			float64(r.RequiredGPUMemory.Value())/consts.GiBToBytes, float64(r.Headroom())/consts.GiBToBytes)
+	}
+	return w.Flush()
+}
+
+// connectToCluster registers the ModelPresets of the cluster, so that they can be recommended like the built-in
+// presets. When the subcommand runs in the cluster, it also loads the SKU catalog ConfigMap and lets the onprem SKU
+// handler list the nodes, so that the same SKUs are recommended as in the webhook. Without a cluster, only the
+// built-in presets and SKUs are available.
+func connectToCluster(ctx context.Context) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return
+	}
+	reader, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		klog.ErrorS(err, "unable to create a client of the cluster")
+		return
+	}
+	// An unreachable cluster does not block the recommendation of the built-in presets.
+	listCtx, cancel := context.WithTimeout(ctx, clusterTimeout)
+	defer cancel()
+	if err := controllers.RegisterModelPresets(listCtx, reader); err != nil {
+		klog.ErrorS(err, "unable to register the model presets")
+	}
+
+	namespace, err := utils.GetReleaseNamespace()
+	if err != nil {
+		return
+	}
+	k8sclient.SetGlobalClient(reader)
+	if err := controllers.LoadSKUCatalog(ctx, reader, namespace); err != nil {
+		klog.ErrorS(err, "unable to load the SKU catalog")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+// Package activator implements the proxy placed in front of the service of an inference workspace that can be scaled
+// to zero. It forwards the requests to the workspace service, holds them while the workspace has no ready replica,
+// and reports the request activity to the workspace controller, which decides when to scale the workspace to zero
+// and when to bring it back.
+package activator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// StatsPath is the path of the endpoint reporting the request activity of the activator.
+	StatsPath = "/stats"
+	// HealthPath is the path of the health check endpoint of the activator.
+	HealthPath = "/healthz"
+)
+
+// Stats is the request activity reported by the activator.
+type Stats struct {
+	// ActiveRequests is the number of requests being held or forwarded.
+	ActiveRequests int `json:"activeRequests"`
+	// LastRequestTime is the time the last request was received or completed. It is the start time of the activator
+	// if no request has been received yet.
+	LastRequestTime time.Time `json:"lastRequestTime"`
+}
+
+// Activator forwards requests to the upstream service once one of the pods backing it is ready.
+type Activator struct {
+	upstream     *url.URL
+	proxy        *httputil.ReverseProxy
+	holdTimeout  time.Duration
+	pollInterval time.Duration
+	// isUpstreamReady reports whether one of the pods backing the upstream service is ready, which tells whether the
+	// workspace has been brought back.
+	isUpstreamReady func() bool
+
+	mu              sync.Mutex
+	activeRequests  int
+	lastRequestTime time.Time
+}
+
+// New returns an activator forwarding requests to upstream. Requests are held for up to holdTimeout until one of the
+// pods listed by pods and matched by selector, i.e., the pods backing the upstream service, is ready.
+func New(upstream *url.URL, holdTimeout time.Duration, pods corelisters.PodNamespaceLister, selector labels.Selector) *Activator {
+	a := &Activator{
+		upstream:        upstream,
+		proxy:           httputil.NewSingleHostReverseProxy(upstream),
+		holdTimeout:     holdTimeout,
+		pollInterval:    time.Second,
+		lastRequestTime: time.Now(),
+	}
+	a.isUpstreamReady = func() bool {
+		return hasReadyPod(pods, selector)
+	}
+	return a
+}
+
+// ServeHTTP holds the request until the upstream service is ready and forwards it.
+func (a *Activator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.track(1)
+	defer a.track(-1)
+
+	ctx, cancel := context.WithTimeout(r.Context(), a.holdTimeout)
+	defer cancel()
+	if !a.waitForUpstream(ctx) {
+		klog.InfoS("upstream is not ready, giving up the request", "upstream", a.upstream.String(), "path", r.URL.Path)
+		http.Error(w, "the workspace is not ready yet, please retry later", http.StatusServiceUnavailable)
+		return
+	}
+	a.proxy.ServeHTTP(w, r)
+}
+
+// StatsHandler serves the request activity of the activator as JSON.
+func (a *Activator) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(a.Stats()); err != nil {
+			klog.ErrorS(err, "failed to write stats")
+		}
+	})
+}
+
+// Stats returns the current request activity.
+func (a *Activator) Stats() Stats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return Stats{ActiveRequests: a.activeRequests, LastRequestTime: a.lastRequestTime}
+}
+
+func (a *Activator) track(delta int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.activeRequests += delta
+	a.lastRequestTime = time.Now()
+}
+
+func (a *Activator) waitForUpstream(ctx context.Context) bool {
+	for {
+		if a.isUpstreamReady() {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(a.pollInterval):
+		}
+	}
+}
+
+// hasReadyPod reports whether one of the pods matched by selector has the Ready condition. A connection to the
+// upstream service does not tell it, since the workspace service also publishes the addresses of pods that are not
+// ready yet, e.g., while the model is being loaded.
+func hasReadyPod(pods corelisters.PodNamespaceLister, selector labels.Selector) bool {
+	podList, err := pods.List(selector)
+	if err != nil {
+		klog.ErrorS(err, "failed to list the upstream pods", "selector", selector.String())
+		return false
+	}
+	for _, pod := range podList {
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
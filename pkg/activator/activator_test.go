@@ -0,0 +1,163 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package activator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTestActivator(t *testing.T, holdTimeout time.Duration) (*Activator, *atomic.Bool) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "upstream "+r.URL.Path)
+	}))
+	t.Cleanup(upstream.Close)
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	ready := &atomic.Bool{}
+	a := New(upstreamURL, holdTimeout, nil, labels.Everything())
+	a.pollInterval = 10 * time.Millisecond
+	a.isUpstreamReady = ready.Load
+	return a, ready
+}
+
+func TestActivatorForwardsRequests(t *testing.T) {
+	a, ready := newTestActivator(t, time.Second)
+	ready.Store(true)
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "upstream /v1/chat/completions" {
+		t.Errorf("unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+	if stats := a.Stats(); stats.ActiveRequests != 0 {
+		t.Errorf("expected no active request, got %d", stats.ActiveRequests)
+	}
+}
+
+func TestActivatorHoldsRequestsUntilUpstreamIsReady(t *testing.T) {
+	a, ready := newTestActivator(t, 5*time.Second)
+	startTime := a.Stats().LastRequestTime
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		done <- rec
+	}()
+
+	// The request is reported as active while it is held.
+	deadline := time.Now().Add(time.Second)
+	for a.Stats().ActiveRequests != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the held request is not reported as active")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !a.Stats().LastRequestTime.After(startTime) {
+		t.Error("expected the last request time to be updated")
+	}
+
+	ready.Store(true)
+	select {
+	case rec := <-done:
+		if rec.Code != http.StatusOK || rec.Body.String() != "upstream /health" {
+			t.Errorf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request is not forwarded once the upstream is ready")
+	}
+}
+
+func TestActivatorGivesUpAfterHoldTimeout(t *testing.T) {
+	a, _ := newTestActivator(t, 50*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if stats := a.Stats(); stats.ActiveRequests != 0 {
+		t.Errorf("expected no active request, got %d", stats.ActiveRequests)
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	a, _ := newTestActivator(t, time.Second)
+
+	rec := httptest.NewRecorder()
+	a.StatsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, StatsPath, nil))
+	var stats Stats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+	if stats.ActiveRequests != 0 || !stats.LastRequestTime.Equal(a.Stats().LastRequestTime) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestHasReadyPod(t *testing.T) {
+	newPod := func(name string, podLabels map[string]string, ready, deleting bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kaito", Labels: podLabels},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			},
+		}
+		if ready {
+			pod.Status.Conditions[0].Status = corev1.ConditionTrue
+		}
+		if deleting {
+			pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return pod
+	}
+	workspaceLabels := map[string]string{"kaito.sh/workspace": "ws"}
+	selector := labels.SelectorFromSet(workspaceLabels)
+
+	testcases := map[string]struct {
+		pods     []*corev1.Pod
+		expected bool
+	}{
+		"No pod": {},
+		"Pod not ready yet": {
+			pods: []*corev1.Pod{newPod("ws-0", workspaceLabels, false, false)},
+		},
+		"Ready pod": {
+			pods:     []*corev1.Pod{newPod("ws-0", workspaceLabels, false, false), newPod("ws-1", workspaceLabels, true, false)},
+			expected: true,
+		},
+		"Ready pod being deleted": {
+			pods: []*corev1.Pod{newPod("ws-0", workspaceLabels, true, true)},
+		},
+		"Ready pod of another workspace": {
+			pods: []*corev1.Pod{newPod("other-0", map[string]string{"kaito.sh/workspace": "other"}, true, false)},
+		},
+	}
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, pod := range tc.pods {
+				if err := indexer.Add(pod); err != nil {
+					t.Fatalf("failed to add pod: %v", err)
+				}
+			}
+			if ready := hasReadyPod(corelisters.NewPodLister(indexer).Pods("kaito"), selector); ready != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, ready)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ var (
 	FeatureGates = map[string]bool{
 		consts.FeatureFlagKarpenter: false,
 		consts.FeatureFlagVLLM:      true,
+		consts.FeatureFlagAMDGPU:    false,
 		//	Add more feature gates here
 	}
 )
@@ -0,0 +1,203 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/model"
+)
+
+const (
+	// DefaultEndpoint is the Hugging Face Hub, which can be replaced with a mirror by the HF_ENDPOINT environment
+	// variable like in the Hugging Face libraries.
+	DefaultEndpoint = "https://huggingface.co"
+	// DefaultRevision is the revision used when the workspace does not specify one.
+	DefaultRevision = "main"
+)
+
+var (
+	hubHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+	// modelInfoCache caches the metadata of model revisions so that the webhook and the controller do not query the
+	// hub on every request. The metadata of a commit never changes, while branches and tags can move to other commits,
+	// so their metadata expires after modelInfoTTL.
+	modelInfoCache sync.Map
+	modelInfoTTL   = 10 * time.Minute
+	commitRegex    = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+	// now is replaced in tests.
+	now = time.Now
+
+	errNotFound = errors.New("not found")
+)
+
+// cachedModelInfo is the metadata of a model revision in modelInfoCache. It never expires if expiry is zero.
+type cachedModelInfo struct {
+	info   *ModelInfo
+	expiry time.Time
+}
+
+// ModelInfo is the metadata of a model repository that determines the requirements of the model.
+type ModelInfo struct {
+	// WeightsSize is the size of the safetensors weights in bytes.
+	WeightsSize int64
+	// TorchDType is the data type of the weights, e.g., bfloat16.
+	TorchDType string
+	// Architectures are the model classes of the model, e.g., MistralForCausalLM.
+	Architectures []string
+	// Architecture describes the model for estimating its GPU memory requirement.
+	Architecture model.ModelArchitecture
+}
+
+// modelConfig is the subset of config.json used by Kaito.
+type modelConfig struct {
+	TorchDType            string   `json:"torch_dtype"`
+	Architectures         []string `json:"architectures"`
+	NumHiddenLayers       int      `json:"num_hidden_layers"`
+	HiddenSize            int      `json:"hidden_size"`
+	IntermediateSize      int      `json:"intermediate_size"`
+	NumAttentionHeads     int      `json:"num_attention_heads"`
+	NumKeyValueHeads      int      `json:"num_key_value_heads"`
+	HeadDim               int      `json:"head_dim"`
+	MaxPositionEmbeddings int      `json:"max_position_embeddings"`
+}
+
+// safetensorsIndex is the subset of model.safetensors.index.json used by Kaito.
+type safetensorsIndex struct {
+	Metadata struct {
+		TotalSize int64 `json:"total_size"`
+	} `json:"metadata"`
+}
+
+func endpoint() string {
+	if e := os.Getenv("HF_ENDPOINT"); e != "" {
+		return strings.TrimSuffix(e, "/")
+	}
+	return DefaultEndpoint
+}
+
+// GetModelInfo reads the config.json and the safetensors weights of the model revision from the hub. The token is
+// only needed for gated or private repositories.
+func GetModelInfo(ctx context.Context, modelID, revision, token string) (*ModelInfo, error) {
+	if revision == "" {
+		revision = DefaultRevision
+	}
+	key := fmt.Sprintf("%s/%s@%s", endpoint(), modelID, revision)
+	if v, ok := modelInfoCache.Load(key); ok {
+		if cached := v.(cachedModelInfo); cached.expiry.IsZero() || now().Before(cached.expiry) {
+			return cached.info, nil
+		}
+	}
+
+	config := &modelConfig{}
+	if err := getJSON(ctx, modelID, revision, "config.json", token, config); err != nil {
+		return nil, err
+	}
+	info := &ModelInfo{TorchDType: config.TorchDType, Architectures: config.Architectures}
+
+	// Sharded weights are described by an index, a single file is measured directly.
+	index := &safetensorsIndex{}
+	err := getJSON(ctx, modelID, revision, "model.safetensors.index.json", token, index)
+	switch {
+	case err == nil:
+		info.WeightsSize = index.Metadata.TotalSize
+	case errors.Is(err, errNotFound):
+		if info.WeightsSize, err = getFileSize(ctx, modelID, revision, "model.safetensors", token); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	if info.WeightsSize <= 0 {
+		return nil, fmt.Errorf("model %s has no safetensors weights", modelID)
+	}
+	info.Architecture = model.ModelArchitecture{
+		DType:                 config.TorchDType,
+		WeightsSize:           info.WeightsSize,
+		NumHiddenLayers:       config.NumHiddenLayers,
+		HiddenSize:            config.HiddenSize,
+		IntermediateSize:      config.IntermediateSize,
+		NumAttentionHeads:     config.NumAttentionHeads,
+		NumKeyValueHeads:      config.NumKeyValueHeads,
+		HeadDim:               config.HeadDim,
+		MaxPositionEmbeddings: config.MaxPositionEmbeddings,
+	}
+
+	cached := cachedModelInfo{info: info}
+	if !commitRegex.MatchString(revision) {
+		cached.expiry = now().Add(modelInfoTTL)
+	}
+	modelInfoCache.Store(key, cached)
+	return info, nil
+}
+
+func newRequest(ctx context.Context, method, modelID, revision, file, token string) (*http.Request, error) {
+	url := fmt.Sprintf("%s/%s/resolve/%s/%s", endpoint(), modelID, revision, file)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func checkResponse(resp *http.Response, modelID, file string) error {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%s of model %s: %w", file, modelID, errNotFound)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("access to model %s is denied, a token is required for gated or private models", modelID)
+	default:
+		return fmt.Errorf("failed to get %s of model %s: %s", file, modelID, resp.Status)
+	}
+}
+
+func getJSON(ctx context.Context, modelID, revision, file, token string, out interface{}) error {
+	req, err := newRequest(ctx, http.MethodGet, modelID, revision, file, token)
+	if err != nil {
+		return err
+	}
+	resp, err := hubHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get %s of model %s: %w", file, modelID, err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp, modelID, file); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s of model %s: %w", file, modelID, err)
+	}
+	return nil
+}
+
+func getFileSize(ctx context.Context, modelID, revision, file, token string) (int64, error) {
+	req, err := newRequest(ctx, http.MethodHead, modelID, revision, file, token)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := hubHTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s of model %s: %w", file, modelID, err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp, modelID, file); err != nil {
+		return 0, err
+	}
+	return resp.ContentLength, nil
+}
@@ -0,0 +1,214 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package huggingface
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHub(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("HF_ENDPOINT", server.URL)
+}
+
+func TestGetModelInfo(t *testing.T) {
+	testcases := []struct {
+		name          string
+		modelID       string
+		handler       http.HandlerFunc
+		expectedInfo  *ModelInfo
+		expectedError string
+	}{
+		{
+			name:    "sharded weights",
+			modelID: "org/sharded",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/org/sharded/resolve/main/config.json":
+					w.Write([]byte(`{"torch_dtype": "bfloat16", "architectures": ["MistralForCausalLM"], "num_hidden_layers": 32,
+						"hidden_size": 4096, "intermediate_size": 14336, "num_attention_heads": 32, "num_key_value_heads": 8,
+						"max_position_embeddings": 32768}`))
+				case "/org/sharded/resolve/main/model.safetensors.index.json":
+					w.Write([]byte(`{"metadata": {"total_size": 14483464192}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			expectedInfo: &ModelInfo{WeightsSize: 14483464192, TorchDType: "bfloat16", Architectures: []string{"MistralForCausalLM"},
+				Architecture: model.ModelArchitecture{DType: "bfloat16", WeightsSize: 14483464192, NumHiddenLayers: 32, HiddenSize: 4096,
+					IntermediateSize: 14336, NumAttentionHeads: 32, NumKeyValueHeads: 8, MaxPositionEmbeddings: 32768}},
+		},
+		{
+			name:    "single weights file",
+			modelID: "org/single",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/org/single/resolve/main/config.json":
+					w.Write([]byte(`{"torch_dtype": "float16"}`))
+				case "/org/single/resolve/main/model.safetensors":
+					if r.Method != http.MethodHead {
+						w.WriteHeader(http.StatusMethodNotAllowed)
+						return
+					}
+					w.Header().Set("Content-Length", "2048")
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			expectedInfo: &ModelInfo{WeightsSize: 2048, TorchDType: "float16",
+				Architecture: model.ModelArchitecture{DType: "float16", WeightsSize: 2048}},
+		},
+		{
+			name:    "gated model without token",
+			modelID: "org/gated",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			expectedError: "access to model org/gated is denied",
+		},
+		{
+			name:    "no safetensors weights",
+			modelID: "org/pickled",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/config.json") {
+					w.Write([]byte(`{}`))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedError: "model.safetensors of model org/pickled: not found",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			newTestHub(t, tc.handler)
+
+			info, err := GetModelInfo(context.Background(), tc.modelID, "", "")
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedInfo, info)
+		})
+	}
+}
+
+func TestGetModelInfoToken(t *testing.T) {
+	newTestHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/org/private/resolve/v1.0/config.json":
+			w.Write([]byte(`{}`))
+		case "/org/private/resolve/v1.0/model.safetensors.index.json":
+			w.Write([]byte(`{"metadata": {"total_size": 1024}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	info, err := GetModelInfo(context.Background(), "org/private", "v1.0", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), info.WeightsSize)
+}
+
+func TestGetModelInfoCache(t *testing.T) {
+	requests := 0
+	newTestHub(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/config.json"):
+			w.Write([]byte(`{}`))
+		case strings.HasSuffix(r.URL.Path, "/model.safetensors.index.json"):
+			w.Write([]byte(`{"metadata": {"total_size": 1024}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	current := time.Now()
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	for _, revision := range []string{"", commit} {
+		_, err := GetModelInfo(context.Background(), "org/cached", revision, "")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 4, requests)
+
+	// Both revisions are cached until the metadata of the branch expires.
+	current = current.Add(modelInfoTTL - time.Second)
+	for _, revision := range []string{"", commit} {
+		_, err := GetModelInfo(context.Background(), "org/cached", revision, "")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 4, requests)
+
+	// The branch may have moved, while the metadata of the commit never changes.
+	current = current.Add(time.Second)
+	for _, revision := range []string{"", commit} {
+		_, err := GetModelInfo(context.Background(), "org/cached", revision, "")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 6, requests)
+}
+
+type testBaseModel struct{}
+
+func (*testBaseModel) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		Tag:                       "0.0.1",
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "1Gi",
+		RuntimeParam: model.RuntimeParam{
+			Transformers: model.HuggingfaceTransformersParam{ModelRunParams: map[string]string{}},
+			VLLM:         model.VLLMParam{ModelRunParams: map[string]string{}},
+		},
+	}
+}
+func (*testBaseModel) GetTuningParameters() *model.PresetParam { return nil }
+func (*testBaseModel) SupportDistributedInference() bool       { return false }
+func (*testBaseModel) SupportTuning() bool                     { return false }
+
+func TestNewModel(t *testing.T) {
+	base := &testBaseModel{}
+	m := NewModel(base, "org/model", &ModelInfo{WeightsSize: 15 * 1024 * 1024 * 1024, TorchDType: "bfloat16"})
+
+	param := m.GetInferenceParameters()
+	assert.Equal(t, "0.0.1", param.Tag)
+	assert.Equal(t, "18Gi", param.TotalGPUMemoryRequirement)
+	assert.Equal(t, "0Gi", param.PerGPUMemoryRequirement)
+	assert.Equal(t, "65Gi", param.DiskStorageRequirement)
+	assert.Equal(t, "org/model", param.VLLM.ModelName)
+	assert.Equal(t, "bfloat16", param.VLLM.ModelRunParams["dtype"])
+	assert.Equal(t, "bfloat16", param.Transformers.ModelRunParams["torch_dtype"])
+	// The parameters of the base preset are left untouched.
+	assert.Empty(t, base.GetInferenceParameters().VLLM.ModelName)
+	assert.Nil(t, param.Architecture)
+	assert.False(t, m.SupportTuning())
+}
+
+func TestNewModelWithArchitecture(t *testing.T) {
+	architecture := model.ModelArchitecture{DType: "bfloat16", WeightsSize: 14483464192, NumHiddenLayers: 32, HiddenSize: 4096,
+		IntermediateSize: 14336, NumAttentionHeads: 32, NumKeyValueHeads: 8, MaxPositionEmbeddings: 32768}
+	m := NewModel(&testBaseModel{}, "org/model", &ModelInfo{WeightsSize: 14483464192, TorchDType: "bfloat16", Architecture: architecture})
+
+	param := m.GetInferenceParameters()
+	// The weights, and the KV cache, activations and runtime overhead of a 2048 token context.
+	assert.Equal(t, "15Gi", param.TotalGPUMemoryRequirement)
+	assert.Equal(t, &architecture, param.Architecture)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package huggingface
+
+import (
+	"fmt"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// PresetName is the name of the generic preset that serves a model repository of the Hugging Face Hub.
+	PresetName = "huggingface"
+
+	// gpuMemoryOverheadRatio accounts for the KV cache and the activations on top of the weights of models whose
+	// architecture is not understood by the GPU memory estimator.
+	gpuMemoryOverheadRatio = 1.2
+	// baseImageDiskGi is the disk space needed besides the weights, mostly for the runtime image.
+	baseImageDiskGi = 50
+)
+
+// hubModel is the generic preset applied to a model repository of the hub.
+type hubModel struct {
+	base    model.Model
+	modelID string
+	info    *ModelInfo
+}
+
+// NewModel returns the model that serves modelID with the runtime parameters of the generic base preset, and the
+// requirements derived from the metadata of the model repository.
+func NewModel(base model.Model, modelID string, info *ModelInfo) model.Model {
+	return &hubModel{base: base, modelID: modelID, info: info}
+}
+
+func (m *hubModel) GetInferenceParameters() *model.PresetParam {
+	param := m.base.GetInferenceParameters().DeepCopy()
+	weightsGi := ceilGi(float64(m.info.WeightsSize))
+	param.GPUCountRequirement = "1"
+	param.TotalGPUMemoryRequirement = fmt.Sprintf("%dGi", ceilGi(float64(m.info.WeightsSize)*gpuMemoryOverheadRatio))
+	architecture := m.info.Architecture
+	if estimate, err := model.EstimateGPUMemory(&architecture, model.MemoryEstimateOptions{}); err == nil {
+		param.Architecture = &architecture
+		param.TotalGPUMemoryRequirement = fmt.Sprintf("%dGi", estimate.TotalGiB())
+	}
+	// The weights are split across the GPUs of a node by tensor parallelism.
+	param.PerGPUMemoryRequirement = "0Gi"
+	param.DiskStorageRequirement = fmt.Sprintf("%dGi", weightsGi+baseImageDiskGi)
+
+	dtype := m.info.TorchDType
+	if dtype == "" {
+		dtype = "auto"
+	}
+	param.VLLM.ModelName = m.modelID
+	param.VLLM.ModelRunParams["dtype"] = dtype
+	param.Transformers.ModelRunParams["torch_dtype"] = dtype
+	return param
+}
+
+func (m *hubModel) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+
+func (m *hubModel) SupportDistributedInference() bool {
+	return false
+}
+
+func (m *hubModel) SupportTuning() bool {
+	return false
+}
+
+func ceilGi(bytes float64) int64 {
+	gi := int64(bytes / consts.GiBToBytes)
+	if float64(gi)*consts.GiBToBytes < bytes {
+		gi++
+	}
+	return gi
+}
@@ -0,0 +1,201 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// DefaultEstimateMaxModelLen is the context length used for the estimate when the runtime does not set the
+	// max-model-len. vLLM lowers the max-model-len of such models to what fits in memory, so the model needs to fit
+	// at least a short context.
+	DefaultEstimateMaxModelLen = 2048
+
+	// runtimeOverheadPerGPU is the memory used by the CUDA context, the CUDA graphs and the runtime itself.
+	runtimeOverheadPerGPU = 1 * consts.GiBToBytes
+)
+
+// ModelArchitecture describes the parts of a transformer model that determine its GPU memory usage. The fields
+// correspond to those of the config.json of Hugging Face models.
+type ModelArchitecture struct {
+	ParameterCount int64  // Number of parameters of the model.
+	DType          string // Data type of the weights and activations, e.g., bfloat16.
+	// WeightsSize is the size of the weights in bytes. If set, it is used instead of the size computed from
+	// ParameterCount and DType, which is useful for pre-quantized weights.
+	WeightsSize int64
+
+	NumHiddenLayers       int // Number of transformer layers.
+	HiddenSize            int // Size of the hidden states.
+	IntermediateSize      int // Size of the MLP hidden states, defaults to 4 * HiddenSize.
+	NumAttentionHeads     int // Number of attention heads.
+	NumKeyValueHeads      int // Number of key value heads for grouped-query attention, defaults to NumAttentionHeads.
+	HeadDim               int // Size of an attention head, defaults to HiddenSize / NumAttentionHeads.
+	MaxPositionEmbeddings int // Maximum context length of the model.
+}
+
+// MemoryEstimateOptions are the runtime settings that the GPU memory usage of a model depends on.
+type MemoryEstimateOptions struct {
+	// Quantization is the quantization method of the weights, e.g., awq or fp8. Empty means no quantization.
+	Quantization string
+	// MaxModelLen is the maximum context length. Defaults to DefaultEstimateMaxModelLen, capped by the
+	// MaxPositionEmbeddings of the model.
+	MaxModelLen int
+	// NumGPUs is the number of GPUs the model is sharded across by tensor or pipeline parallelism. Defaults to 1.
+	NumGPUs int
+}
+
+// MemoryEstimate is the GPU memory needed to serve a model, summed over all GPUs, in bytes.
+type MemoryEstimate struct {
+	Weights     int64
+	KVCache     int64
+	Activations int64
+	Overhead    int64
+
+	MaxModelLen int // The context length that the estimate is for.
+	NumGPUs     int // The number of GPUs that the estimate is for.
+}
+
+// Total returns the GPU memory needed across all GPUs.
+func (e *MemoryEstimate) Total() int64 {
+	return e.Weights + e.KVCache + e.Activations + e.Overhead
+}
+
+// PerGPU returns the GPU memory needed on each GPU.
+func (e *MemoryEstimate) PerGPU() int64 {
+	return int64(math.Ceil(float64(e.Total()) / float64(e.NumGPUs)))
+}
+
+// TotalGiB returns the GPU memory needed across all GPUs rounded up to GiB.
+func (e *MemoryEstimate) TotalGiB() int64 {
+	return (e.Total() + consts.GiBToBytes - 1) / consts.GiBToBytes
+}
+
+// String returns the breakdown of the estimate.
+func (e *MemoryEstimate) String() string {
+	return fmt.Sprintf("weights %.1fGiB, KV cache %.1fGiB, activations %.1fGiB, runtime overhead %.1fGiB",
+		toGiB(e.Weights), toGiB(e.KVCache), toGiB(e.Activations), toGiB(e.Overhead))
+}
+
+// EstimateGPUMemory estimates the GPU memory needed to serve a single sequence of the maximum context length:
+//   - the weights are the parameters times the bytes per parameter of the quantization or the data type,
+//   - the KV cache stores a key and a value vector per layer, key value head and token,
+//   - the activations are the hidden states and the MLP states of the tokens of a prefill,
+//   - and each GPU has a fixed runtime overhead.
+//
+// Tensor parallelism shards the weights, the KV cache and the MLP states across the GPUs, but every GPU holds the
+// full hidden states.
+func EstimateGPUMemory(arch *ModelArchitecture, opts MemoryEstimateOptions) (*MemoryEstimate, error) {
+	if arch.NumHiddenLayers <= 0 || arch.HiddenSize <= 0 || arch.NumAttentionHeads <= 0 {
+		return nil, fmt.Errorf("the number of layers, the hidden size and the number of attention heads must be positive")
+	}
+	dtypeBytes, err := dtypeBytesPerParameter(arch.DType)
+	if err != nil {
+		return nil, err
+	}
+
+	numGPUs := opts.NumGPUs
+	if numGPUs <= 0 {
+		numGPUs = 1
+	}
+	maxModelLen := opts.MaxModelLen
+	if maxModelLen <= 0 {
+		maxModelLen = DefaultEstimateMaxModelLen
+		if arch.MaxPositionEmbeddings > 0 && arch.MaxPositionEmbeddings < maxModelLen {
+			maxModelLen = arch.MaxPositionEmbeddings
+		}
+	}
+
+	weights := arch.WeightsSize
+	if weights <= 0 {
+		if arch.ParameterCount <= 0 {
+			return nil, fmt.Errorf("either the parameter count or the weights size must be positive")
+		}
+		weightBytes := dtypeBytes
+		if opts.Quantization != "" {
+			if weightBytes, err = quantizationBytesPerParameter(opts.Quantization); err != nil {
+				return nil, err
+			}
+		}
+		weights = int64(math.Ceil(float64(arch.ParameterCount) * weightBytes))
+	}
+
+	numKeyValueHeads := arch.NumKeyValueHeads
+	if numKeyValueHeads <= 0 {
+		numKeyValueHeads = arch.NumAttentionHeads
+	}
+	headDim := arch.HeadDim
+	if headDim <= 0 {
+		headDim = arch.HiddenSize / arch.NumAttentionHeads
+	}
+	intermediateSize := arch.IntermediateSize
+	if intermediateSize <= 0 {
+		intermediateSize = 4 * arch.HiddenSize
+	}
+
+	tokens := float64(maxModelLen)
+	kvCache := 2 * float64(arch.NumHiddenLayers) * float64(numKeyValueHeads) * float64(headDim) * tokens * dtypeBytes
+	// Per GPU, the hidden states, the residual and the attention input and output, plus the MLP gate and up states.
+	activationsPerGPU := tokens * (4*float64(arch.HiddenSize) + 2*float64(intermediateSize)/float64(numGPUs)) * dtypeBytes
+
+	return &MemoryEstimate{
+		Weights:     weights,
+		KVCache:     int64(math.Ceil(kvCache)),
+		Activations: int64(math.Ceil(activationsPerGPU)) * int64(numGPUs),
+		Overhead:    runtimeOverheadPerGPU * int64(numGPUs),
+		MaxModelLen: maxModelLen,
+		NumGPUs:     numGPUs,
+	}, nil
+}
+
+// EstimateGPUMemory estimates the GPU memory needed to serve the model on numGPUs GPUs with the max-model-len and the
+// quantization of the vLLM runtime parameters.
+func (p *PresetParam) EstimateGPUMemory(numGPUs int) (*MemoryEstimate, error) {
+	if p.Architecture == nil {
+		return nil, fmt.Errorf("the architecture of the model is unknown")
+	}
+	opts := MemoryEstimateOptions{
+		Quantization: p.VLLM.ModelRunParams["quantization"],
+		NumGPUs:      numGPUs,
+	}
+	if maxModelLen, ok := p.VLLM.ModelRunParams["max-model-len"]; ok {
+		v, err := strconv.Atoi(maxModelLen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max-model-len %s: %w", maxModelLen, err)
+		}
+		opts.MaxModelLen = v
+	}
+	return EstimateGPUMemory(p.Architecture, opts)
+}
+
+func dtypeBytesPerParameter(dtype string) (float64, error) {
+	switch strings.ToLower(dtype) {
+	case "float32", "float":
+		return 4, nil
+	// Models without a data type are served in half precision.
+	case "", "auto", "float16", "half", "bfloat16":
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("unsupported data type %s", dtype)
+	}
+}
+
+func quantizationBytesPerParameter(quantization string) (float64, error) {
+	switch strings.ToLower(quantization) {
+	case "fp8", "int8", "bitsandbytes-8bit":
+		return 1, nil
+	case "awq", "gptq", "int4", "bitsandbytes", "marlin":
+		return 0.5, nil
+	default:
+		return 0, fmt.Errorf("unsupported quantization %s", quantization)
+	}
+}
+
+func toGiB(bytes int64) float64 {
+	return float64(bytes) / consts.GiBToBytes
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package model
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+// llama3_8B is the architecture of Meta-Llama-3-8B.
+var llama3_8B = ModelArchitecture{
+	ParameterCount:        8030261248,
+	DType:                 "bfloat16",
+	NumHiddenLayers:       32,
+	HiddenSize:            4096,
+	IntermediateSize:      14336,
+	NumAttentionHeads:     32,
+	NumKeyValueHeads:      8,
+	MaxPositionEmbeddings: 8192,
+}
+
+func TestEstimateGPUMemory(t *testing.T) {
+	testcases := []struct {
+		name             string
+		arch             ModelArchitecture
+		opts             MemoryEstimateOptions
+		expectedEstimate *MemoryEstimate
+		expectedError    string
+	}{
+		{
+			name: "default context length",
+			arch: llama3_8B,
+			expectedEstimate: &MemoryEstimate{
+				Weights:     16060522496,
+				KVCache:     256 * 1024 * 1024,
+				Activations: 2048 * (4*4096 + 2*14336) * 2,
+				Overhead:    consts.GiBToBytes,
+				MaxModelLen: 2048,
+				NumGPUs:     1,
+			},
+		},
+		{
+			name: "full context length",
+			arch: llama3_8B,
+			opts: MemoryEstimateOptions{MaxModelLen: 8192},
+			expectedEstimate: &MemoryEstimate{
+				Weights:     16060522496,
+				KVCache:     consts.GiBToBytes,
+				Activations: 8192 * (4*4096 + 2*14336) * 2,
+				Overhead:    consts.GiBToBytes,
+				MaxModelLen: 8192,
+				NumGPUs:     1,
+			},
+		},
+		{
+			name: "tensor parallelism and quantization",
+			arch: llama3_8B,
+			opts: MemoryEstimateOptions{MaxModelLen: 8192, NumGPUs: 2, Quantization: "fp8"},
+			expectedEstimate: &MemoryEstimate{
+				Weights:     8030261248,
+				KVCache:     consts.GiBToBytes,
+				Activations: 2 * 8192 * (4*4096 + 14336) * 2,
+				Overhead:    2 * consts.GiBToBytes,
+				MaxModelLen: 8192,
+				NumGPUs:     2,
+			},
+		},
+		{
+			name: "context length capped by the model",
+			arch: ModelArchitecture{ParameterCount: 1000, DType: "float32", NumHiddenLayers: 1, HiddenSize: 8, NumAttentionHeads: 2, MaxPositionEmbeddings: 1024},
+			expectedEstimate: &MemoryEstimate{
+				Weights:     4000,
+				KVCache:     2 * 1 * 2 * 4 * 1024 * 4,
+				Activations: 1024 * (4*8 + 2*32) * 4,
+				Overhead:    consts.GiBToBytes,
+				MaxModelLen: 1024,
+				NumGPUs:     1,
+			},
+		},
+		{
+			name:          "unsupported data type",
+			arch:          ModelArchitecture{ParameterCount: 1000, DType: "int2", NumHiddenLayers: 1, HiddenSize: 8, NumAttentionHeads: 2},
+			expectedError: "unsupported data type int2",
+		},
+		{
+			name:          "unsupported quantization",
+			arch:          llama3_8B,
+			opts:          MemoryEstimateOptions{Quantization: "unknown"},
+			expectedError: "unsupported quantization unknown",
+		},
+		{
+			name:          "missing layers",
+			arch:          ModelArchitecture{ParameterCount: 1000},
+			expectedError: "the number of layers, the hidden size and the number of attention heads must be positive",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			estimate, err := EstimateGPUMemory(&tc.arch, tc.opts)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEstimate, estimate)
+		})
+	}
+}
+
+func TestPresetParamEstimateGPUMemory(t *testing.T) {
+	param := &PresetParam{
+		Architecture: &llama3_8B,
+		RuntimeParam: RuntimeParam{
+			VLLM: VLLMParam{ModelRunParams: map[string]string{"max-model-len": "8192"}},
+		},
+	}
+	estimate, err := param.EstimateGPUMemory(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 8192, estimate.MaxModelLen)
+	assert.Equal(t, int64(18), estimate.TotalGiB())
+	assert.Equal(t, "weights 15.0GiB, KV cache 1.0GiB, activations 0.7GiB, runtime overhead 1.0GiB", estimate.String())
+
+	_, err = (&PresetParam{}).EstimateGPUMemory(1)
+	assert.Error(t, err)
+}
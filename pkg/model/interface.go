@@ -4,8 +4,6 @@ package model
 
 import (
 	"time"
-
-	"github.com/kaito-project/kaito/pkg/utils"
 )
 
 type Model interface {
@@ -21,6 +19,7 @@ type RuntimeName string
 const (
 	RuntimeNameHuggingfaceTransformers RuntimeName = "transformers"
 	RuntimeNameVLLM                    RuntimeName = "vllm"
+	RuntimeNameLlamaCpp                RuntimeName = "llamacpp"
 )
 
 // PresetParam defines the preset inference parameters for a model.
@@ -35,6 +34,11 @@ type PresetParam struct {
 	PerGPUMemoryRequirement       string         // GPU memory required per GPU. Used for inference.
 	TuningPerGPUMemoryRequirement map[string]int // Min GPU memory per tuning method (batch size 1). Used for tuning.
 	WorldSize                     int            // Defines the number of processes required for distributed inference.
+	CPURequirement                string         // Number of CPUs required for CPU inference.
+	MemoryRequirement             string         // Memory required for CPU inference.
+	// Architecture describes the model for estimating its GPU memory requirement. If set, the webhook checks the
+	// estimate instead of the TotalGPUMemoryRequirement and the PerGPUMemoryRequirement. Used for inference.
+	Architecture *ModelArchitecture
 
 	RuntimeParam
 
@@ -48,6 +52,7 @@ type PresetParam struct {
 type RuntimeParam struct {
 	Transformers HuggingfaceTransformersParam
 	VLLM         VLLMParam
+	LlamaCpp     LlamaCppParam
 	// Disable the tensor parallelism
 	DisableTensorParallelism bool
 }
@@ -71,6 +76,20 @@ type VLLMParam struct {
 	ModelRunParams map[string]string
 }
 
+// LlamaCppParam defines the parameters of the llama.cpp server, which serves quantized models in the GGUF format on CPUs.
+type LlamaCppParam struct {
+	// The image of the llama.cpp server.
+	Image       string
+	BaseCommand string
+	// The model repository on the Hugging Face Hub and the GGUF file in it, which the server downloads at startup.
+	ModelRepo string
+	ModelFile string
+	// The model name used in the openai serving API.
+	ModelName string
+	// Parameters for running the model inference.
+	ModelRunParams map[string]string
+}
+
 func (p *PresetParam) DeepCopy() *PresetParam {
 	if p == nil {
 		return nil
@@ -78,6 +97,10 @@ func (p *PresetParam) DeepCopy() *PresetParam {
 	out := new(PresetParam)
 	*out = *p
 	out.RuntimeParam = p.RuntimeParam.DeepCopy()
+	if p.Architecture != nil {
+		architecture := *p.Architecture
+		out.Architecture = &architecture
+	}
 	out.TuningPerGPUMemoryRequirement = make(map[string]int, len(p.TuningPerGPUMemoryRequirement))
 	for k, v := range p.TuningPerGPUMemoryRequirement {
 		out.TuningPerGPUMemoryRequirement[k] = v
@@ -92,6 +115,7 @@ func (rp *RuntimeParam) DeepCopy() RuntimeParam {
 	out := *rp
 	out.Transformers = rp.Transformers.DeepCopy()
 	out.VLLM = rp.VLLM.DeepCopy()
+	out.LlamaCpp = rp.LlamaCpp.DeepCopy()
 	return out
 }
 
@@ -131,24 +155,23 @@ func (v *VLLMParam) DeepCopy() VLLMParam {
 	return out
 }
 
-// builds the container command:
-// eg. torchrun <TORCH_PARAMS> <OPTIONAL_RDZV_PARAMS> baseCommand <MODEL_PARAMS>
+func (l *LlamaCppParam) DeepCopy() LlamaCppParam {
+	if l == nil {
+		return LlamaCppParam{}
+	}
+	out := *l
+	out.ModelRunParams = make(map[string]string, len(l.ModelRunParams))
+	for k, v := range l.ModelRunParams {
+		out.ModelRunParams[k] = v
+	}
+	return out
+}
+
+// GetInferenceCommand builds the container command that serves the model with the runtime, or returns nil if the
+// runtime is not registered.
 func (p *PresetParam) GetInferenceCommand(runtime RuntimeName, skuNumGPUs string) []string {
-	switch runtime {
-	case RuntimeNameHuggingfaceTransformers:
-		torchCommand := utils.BuildCmdStr(p.Transformers.BaseCommand, p.Transformers.TorchRunParams, p.Transformers.TorchRunRdzvParams)
-		modelCommand := utils.BuildCmdStr(p.Transformers.InferenceMainFile, p.Transformers.ModelRunParams)
-		return utils.ShellCmd(torchCommand + " " + modelCommand)
-	case RuntimeNameVLLM:
-		if p.VLLM.ModelName != "" {
-			p.VLLM.ModelRunParams["served-model-name"] = p.VLLM.ModelName
-		}
-		if !p.DisableTensorParallelism {
-			p.VLLM.ModelRunParams["tensor-parallel-size"] = skuNumGPUs
-		}
-		modelCommand := utils.BuildCmdStr(p.VLLM.BaseCommand, p.VLLM.ModelRunParams)
-		return utils.ShellCmd(modelCommand)
-	default:
-		return nil
+	if r := GetRuntime(runtime); r != nil {
+		return r.GetInferenceCommand(p, skuNumGPUs)
 	}
+	return nil
 }
@@ -0,0 +1,272 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package model
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/kaito-project/kaito/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// RuntimeFeature is an optional capability of a runtime.
+type RuntimeFeature string
+
+const (
+	// RuntimeFeatureDistributedInference serves a model across multiple nodes.
+	RuntimeFeatureDistributedInference RuntimeFeature = "DistributedInference"
+	// RuntimeFeatureAdapters serves a model with LoRA adapters.
+	RuntimeFeatureAdapters RuntimeFeature = "Adapters"
+	// RuntimeFeatureAutoscaling exposes the metrics of the inference server that are used for autoscaling,
+	// e.g., the number of pending requests and the KV-cache usage.
+	RuntimeFeatureAutoscaling RuntimeFeature = "Autoscaling"
+	// RuntimeFeatureCPU serves a model on CPUs, so that the workload requests CPU and memory instead of GPUs and runs
+	// on any instance type.
+	RuntimeFeatureCPU RuntimeFeature = "CPU"
+)
+
+const (
+	// DefaultRuntimePort is the port the built-in runtimes serve the inference API on.
+	DefaultRuntimePort = 5000
+	// DefaultRuntimeHealthPath is the health check path of the built-in runtimes.
+	DefaultRuntimeHealthPath = "/health"
+)
+
+// Runtime is an inference runtime that serves the preset models. The runtimes are registered by name with
+// RegisterRuntime, so that a new runtime is added by implementing this interface and registering it.
+type Runtime interface {
+	Name() RuntimeName
+	// SupportsModel returns whether the preset has the parameters to be served by the runtime.
+	SupportsModel(p *PresetParam) bool
+	// GetInferenceCommand builds the container command that serves the model with skuNumGPUs GPUs per node.
+	// The parameters are updated in place, so callers pass a copy of the preset parameters.
+	GetInferenceCommand(p *PresetParam, skuNumGPUs string) []string
+	// GetImage returns the image of the runtime, or empty if the model is served from the image of the preset.
+	GetImage(p *PresetParam) string
+	// Port is the container port of the inference API.
+	Port() int32
+	LivenessProbe() *corev1.Probe
+	ReadinessProbe() *corev1.Probe
+	// MetricsPath is the path of the Prometheus metrics of the inference server, or empty if it exposes none.
+	MetricsPath() string
+	SupportsFeature(feature RuntimeFeature) bool
+}
+
+var (
+	runtimesMu sync.RWMutex
+	runtimes   = map[RuntimeName]Runtime{}
+)
+
+// RegisterRuntime adds the runtime, replacing the runtime of the same name if any.
+func RegisterRuntime(r Runtime) {
+	runtimesMu.Lock()
+	defer runtimesMu.Unlock()
+	if r.Name() == "" {
+		panic("runtime name is not specified")
+	}
+	runtimes[r.Name()] = r
+}
+
+// GetRuntime returns the runtime with the given name, or nil if it is not registered.
+func GetRuntime(name RuntimeName) Runtime {
+	runtimesMu.RLock()
+	defer runtimesMu.RUnlock()
+	return runtimes[name]
+}
+
+// RuntimeNames returns the names of the registered runtimes in alphabetical order.
+func RuntimeNames() []RuntimeName {
+	runtimesMu.RLock()
+	defer runtimesMu.RUnlock()
+	names := make([]RuntimeName, 0, len(runtimes))
+	for name := range runtimes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// RuntimeNamesWithFeature returns the names of the registered runtimes that support the feature.
+func RuntimeNamesWithFeature(feature RuntimeFeature) []RuntimeName {
+	var names []RuntimeName
+	for _, name := range RuntimeNames() {
+		if GetRuntime(name).SupportsFeature(feature) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func init() {
+	RegisterRuntime(&transformersRuntime{})
+	RegisterRuntime(&vllmRuntime{})
+	RegisterRuntime(&llamaCppRuntime{})
+}
+
+// httpRuntime implements the probes and the port of runtimes whose inference server listens on the default port.
+type httpRuntime struct{}
+
+func (httpRuntime) Port() int32 {
+	return DefaultRuntimePort
+}
+
+func (httpRuntime) LivenessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port: intstr.FromInt(DefaultRuntimePort),
+				Path: DefaultRuntimeHealthPath,
+			},
+		},
+		InitialDelaySeconds: 600, // 10 minutes
+		PeriodSeconds:       10,
+	}
+}
+
+func (httpRuntime) ReadinessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port: intstr.FromInt(DefaultRuntimePort),
+				Path: DefaultRuntimeHealthPath,
+			},
+		},
+		InitialDelaySeconds: 30,
+		PeriodSeconds:       10,
+	}
+}
+
+// transformersRuntime serves the models with the inference server of Kaito built on Hugging Face transformers,
+// launched by torchrun or accelerate.
+type transformersRuntime struct {
+	httpRuntime
+}
+
+func (*transformersRuntime) Name() RuntimeName {
+	return RuntimeNameHuggingfaceTransformers
+}
+
+func (*transformersRuntime) SupportsModel(p *PresetParam) bool {
+	return p.Transformers.BaseCommand != ""
+}
+
+// builds the container command:
+// eg. torchrun <TORCH_PARAMS> <OPTIONAL_RDZV_PARAMS> baseCommand <MODEL_PARAMS>
+func (*transformersRuntime) GetInferenceCommand(p *PresetParam, _ string) []string {
+	torchCommand := utils.BuildCmdStr(p.Transformers.BaseCommand, p.Transformers.TorchRunParams, p.Transformers.TorchRunRdzvParams)
+	modelCommand := utils.BuildCmdStr(p.Transformers.InferenceMainFile, p.Transformers.ModelRunParams)
+	return utils.ShellCmd(torchCommand + " " + modelCommand)
+}
+
+func (*transformersRuntime) GetImage(_ *PresetParam) string {
+	return ""
+}
+
+func (*transformersRuntime) MetricsPath() string {
+	return ""
+}
+
+func (*transformersRuntime) SupportsFeature(feature RuntimeFeature) bool {
+	return feature == RuntimeFeatureDistributedInference || feature == RuntimeFeatureAdapters
+}
+
+// vllmRuntime serves the models with the OpenAI compatible server of vLLM.
+type vllmRuntime struct {
+	httpRuntime
+}
+
+func (*vllmRuntime) Name() RuntimeName {
+	return RuntimeNameVLLM
+}
+
+func (*vllmRuntime) SupportsModel(p *PresetParam) bool {
+	return p.VLLM.BaseCommand != ""
+}
+
+func (*vllmRuntime) GetInferenceCommand(p *PresetParam, skuNumGPUs string) []string {
+	if p.VLLM.ModelRunParams == nil {
+		p.VLLM.ModelRunParams = map[string]string{}
+	}
+	if p.VLLM.ModelName != "" {
+		p.VLLM.ModelRunParams["served-model-name"] = p.VLLM.ModelName
+	}
+	if !p.DisableTensorParallelism {
+		p.VLLM.ModelRunParams["tensor-parallel-size"] = skuNumGPUs
+	}
+	modelCommand := utils.BuildCmdStr(p.VLLM.BaseCommand, p.VLLM.ModelRunParams)
+	return utils.ShellCmd(modelCommand)
+}
+
+func (*vllmRuntime) GetImage(_ *PresetParam) string {
+	return ""
+}
+
+func (*vllmRuntime) MetricsPath() string {
+	return "/metrics"
+}
+
+func (*vllmRuntime) SupportsFeature(feature RuntimeFeature) bool {
+	return feature == RuntimeFeatureAdapters || feature == RuntimeFeatureAutoscaling
+}
+
+// llamaCppRuntime serves quantized models on CPUs with the OpenAI compatible server of llama.cpp.
+type llamaCppRuntime struct {
+	httpRuntime
+}
+
+func (*llamaCppRuntime) Name() RuntimeName {
+	return RuntimeNameLlamaCpp
+}
+
+func (*llamaCppRuntime) SupportsModel(p *PresetParam) bool {
+	return p.LlamaCpp.BaseCommand != "" && p.LlamaCpp.ModelRepo != "" && p.LlamaCpp.ModelFile != ""
+}
+
+// builds the container command:
+// eg. llama-server --hf-repo <REPO> --hf-file <FILE> --host 0.0.0.0 --port 5000 <MODEL_PARAMS>
+// The arguments are separated by spaces because the server does not accept --key=value.
+func (*llamaCppRuntime) GetInferenceCommand(p *PresetParam, _ string) []string {
+	if p.LlamaCpp.ModelRunParams == nil {
+		p.LlamaCpp.ModelRunParams = map[string]string{}
+	}
+	p.LlamaCpp.ModelRunParams["hf-repo"] = p.LlamaCpp.ModelRepo
+	p.LlamaCpp.ModelRunParams["hf-file"] = p.LlamaCpp.ModelFile
+	p.LlamaCpp.ModelRunParams["host"] = "0.0.0.0"
+	p.LlamaCpp.ModelRunParams["port"] = strconv.Itoa(DefaultRuntimePort)
+	p.LlamaCpp.ModelRunParams["metrics"] = ""
+	if p.LlamaCpp.ModelName != "" {
+		p.LlamaCpp.ModelRunParams["alias"] = p.LlamaCpp.ModelName
+	}
+	if p.CPURequirement != "" {
+		p.LlamaCpp.ModelRunParams["threads"] = p.CPURequirement
+	}
+
+	keys := make([]string, 0, len(p.LlamaCpp.ModelRunParams))
+	for k := range p.LlamaCpp.ModelRunParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	command := p.LlamaCpp.BaseCommand
+	for _, k := range keys {
+		command += " --" + k
+		if v := p.LlamaCpp.ModelRunParams[k]; v != "" {
+			command += " " + v
+		}
+	}
+	return utils.ShellCmd(command)
+}
+
+func (*llamaCppRuntime) GetImage(p *PresetParam) string {
+	return p.LlamaCpp.Image
+}
+
+func (*llamaCppRuntime) MetricsPath() string {
+	return "/metrics"
+}
+
+func (*llamaCppRuntime) SupportsFeature(feature RuntimeFeature) bool {
+	return feature == RuntimeFeatureCPU
+}
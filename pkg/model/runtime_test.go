@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInferenceCommand(t *testing.T) {
+	params := &PresetParam{
+		CPURequirement: "4",
+		RuntimeParam: RuntimeParam{
+			Transformers: HuggingfaceTransformersParam{
+				BaseCommand:       "accelerate launch",
+				InferenceMainFile: "/workspace/tfs/inference_api.py",
+				ModelRunParams:    map[string]string{"torch_dtype": "bfloat16"},
+			},
+			VLLM: VLLMParam{
+				BaseCommand:    "python3 /workspace/vllm/inference_api.py",
+				ModelName:      "falcon-7b",
+				ModelRunParams: map[string]string{"dtype": "float16"},
+			},
+			LlamaCpp: LlamaCppParam{
+				BaseCommand:    "/app/llama-server",
+				ModelRepo:      "microsoft/phi-3-mini-gguf",
+				ModelFile:      "phi-3-mini-q4.gguf",
+				ModelName:      "phi-3-mini",
+				ModelRunParams: map[string]string{"ctx-size": "4096"},
+			},
+		},
+	}
+
+	testcases := map[string]struct {
+		runtime  RuntimeName
+		expected []string
+	}{
+		"transformers": {
+			runtime:  RuntimeNameHuggingfaceTransformers,
+			expected: []string{"/bin/sh", "-c", "accelerate launch /workspace/tfs/inference_api.py --torch_dtype=bfloat16"},
+		},
+		"vllm": {
+			runtime:  RuntimeNameVLLM,
+			expected: []string{"/bin/sh", "-c", "python3 /workspace/vllm/inference_api.py --dtype=float16 --served-model-name=falcon-7b --tensor-parallel-size=2"},
+		},
+		"llamacpp": {
+			runtime:  RuntimeNameLlamaCpp,
+			expected: []string{"/bin/sh", "-c", "/app/llama-server --alias phi-3-mini --ctx-size 4096 --hf-file phi-3-mini-q4.gguf --hf-repo microsoft/phi-3-mini-gguf --host 0.0.0.0 --metrics --port 5000 --threads 4"},
+		},
+		"unregistered runtime": {
+			runtime: "unknown",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, params.DeepCopy().GetInferenceCommand(tc.runtime, "2"))
+		})
+	}
+}
+
+func TestRuntimeRegistry(t *testing.T) {
+	assert.Equal(t, []RuntimeName{RuntimeNameLlamaCpp, RuntimeNameHuggingfaceTransformers, RuntimeNameVLLM}, RuntimeNames())
+	assert.Equal(t, []RuntimeName{RuntimeNameVLLM}, RuntimeNamesWithFeature(RuntimeFeatureAutoscaling))
+	assert.Equal(t, []RuntimeName{RuntimeNameHuggingfaceTransformers}, RuntimeNamesWithFeature(RuntimeFeatureDistributedInference))
+
+	vllm := GetRuntime(RuntimeNameVLLM)
+	assert.Equal(t, int32(DefaultRuntimePort), vllm.Port())
+	assert.Equal(t, "/metrics", vllm.MetricsPath())
+	assert.True(t, vllm.SupportsModel(&PresetParam{RuntimeParam: RuntimeParam{VLLM: VLLMParam{BaseCommand: "vllm serve"}}}))
+	assert.False(t, vllm.SupportsModel(&PresetParam{}))
+	assert.Nil(t, GetRuntime("unknown"))
+
+	llamaCpp := GetRuntime(RuntimeNameLlamaCpp)
+	assert.True(t, llamaCpp.SupportsFeature(RuntimeFeatureCPU))
+	assert.Equal(t, "ghcr.io/ggerganov/llama.cpp:server", llamaCpp.GetImage(&PresetParam{RuntimeParam: RuntimeParam{LlamaCpp: LlamaCppParam{Image: "ghcr.io/ggerganov/llama.cpp:server"}}}))
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	presetTagsMu      sync.RWMutex
+	presetTags        = map[string][]string{}
+	presetTagRuntimes = map[string]map[string][]RuntimeName{}
+)
+
+// RegisterPresetTags sets the released tags of the image of the preset, which workspaces can pin with the version of
+// the preset. The tags are usually registered from the tag history of supported_models.yaml.
+func RegisterPresetTags(preset string, tags []string) {
+	presetTagsMu.Lock()
+	defer presetTagsMu.Unlock()
+	presetTags[preset] = append([]string(nil), tags...)
+}
+
+// RegisterPresetTagRuntimes sets the runtimes that the image of the preset with the tag can serve the model with.
+// Images released before a runtime was added cannot run it, e.g., the images that predate vLLM.
+func RegisterPresetTagRuntimes(preset string, tag string, runtimes []RuntimeName) {
+	presetTagsMu.Lock()
+	defer presetTagsMu.Unlock()
+	if presetTagRuntimes[preset] == nil {
+		presetTagRuntimes[preset] = map[string][]RuntimeName{}
+	}
+	presetTagRuntimes[preset][tag] = append([]RuntimeName(nil), runtimes...)
+}
+
+// PresetTagSupportsRuntime returns whether the image of the preset with the tag can serve the model with the runtime.
+// A tag whose runtimes are not registered supports all runtimes.
+func PresetTagSupportsRuntime(preset string, tag string, runtime RuntimeName) bool {
+	presetTagsMu.RLock()
+	defer presetTagsMu.RUnlock()
+	runtimes, ok := presetTagRuntimes[preset][tag]
+	if !ok {
+		return true
+	}
+	for _, r := range runtimes {
+		if r == runtime {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPresetTags returns the known tags of the image of the preset, from the newest to the oldest. The default tag of
+// the preset is always known, even if it is not in the registered tags.
+func GetPresetTags(preset string, defaultTag string) []string {
+	presetTagsMu.RLock()
+	tags := append([]string(nil), presetTags[preset]...)
+	presetTagsMu.RUnlock()
+
+	found := defaultTag == ""
+	for _, tag := range tags {
+		if tag == defaultTag {
+			found = true
+			break
+		}
+	}
+	if !found {
+		tags = append(tags, defaultTag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return CompareTags(tags[i], tags[j]) > 0 })
+	return tags
+}
+
+// IsKnownPresetTag returns whether the tag is a known tag of the image of the preset.
+func IsKnownPresetTag(preset string, defaultTag string, tag string) bool {
+	for _, t := range GetPresetTags(preset, defaultTag) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNewerPresetTag returns the newest known tag of the image of the preset if it is newer than the given tag, or
+// empty otherwise.
+func GetNewerPresetTag(preset string, defaultTag string, tag string) string {
+	tags := GetPresetTags(preset, defaultTag)
+	if len(tags) == 0 || CompareTags(tags[0], tag) <= 0 {
+		return ""
+	}
+	return tags[0]
+}
+
+// CompareTags compares the tags of preset images, e.g., 0.0.10 and 0.0.9, by their dot-separated numbers, and
+// returns -1, 0 or 1 if a is older than, the same as or newer than b. Parts that are not numbers are compared as
+// strings.
+func CompareTags(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		switch {
+		case aErr == nil && bErr == nil && aNum != bNum:
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && aPart != bPart:
+			return strings.Compare(aPart, bPart)
+		}
+	}
+	return 0
+}
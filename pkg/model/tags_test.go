@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareTags(t *testing.T) {
+	assert.Equal(t, 0, CompareTags("0.0.9", "0.0.9"))
+	assert.Equal(t, 1, CompareTags("0.0.10", "0.0.9"))
+	assert.Equal(t, -1, CompareTags("0.0.9", "0.1.0"))
+	assert.Equal(t, 1, CompareTags("0.0.9.1", "0.0.9"))
+	assert.Equal(t, -1, CompareTags("0.0.9-rc", "0.0.9-rc2"))
+}
+
+func TestPresetTags(t *testing.T) {
+	RegisterPresetTags("test-preset-tags", []string{"0.0.2", "0.0.3", "0.0.1"})
+
+	assert.Equal(t, []string{"0.0.3", "0.0.2", "0.0.1"}, GetPresetTags("test-preset-tags", "0.0.2"))
+	assert.Equal(t, []string{"0.0.4", "0.0.3", "0.0.2", "0.0.1"}, GetPresetTags("test-preset-tags", "0.0.4"))
+	assert.Equal(t, []string{"0.0.1"}, GetPresetTags("test-unknown-preset", "0.0.1"))
+
+	assert.True(t, IsKnownPresetTag("test-preset-tags", "0.0.2", "0.0.1"))
+	assert.False(t, IsKnownPresetTag("test-preset-tags", "0.0.2", "0.0.9"))
+
+	assert.Equal(t, "0.0.3", GetNewerPresetTag("test-preset-tags", "0.0.2", "0.0.2"))
+	assert.Equal(t, "", GetNewerPresetTag("test-preset-tags", "0.0.2", "0.0.3"))
+	assert.Equal(t, "", GetNewerPresetTag("test-unknown-preset", "0.0.1", "0.0.1"))
+}
+
+func TestPresetTagSupportsRuntime(t *testing.T) {
+	RegisterPresetTagRuntimes("test-preset-tag-runtimes", "0.0.1", []RuntimeName{RuntimeNameHuggingfaceTransformers})
+
+	assert.True(t, PresetTagSupportsRuntime("test-preset-tag-runtimes", "0.0.1", RuntimeNameHuggingfaceTransformers))
+	assert.False(t, PresetTagSupportsRuntime("test-preset-tag-runtimes", "0.0.1", RuntimeNameVLLM))
+	// The runtimes of unregistered tags are not restricted.
+	assert.True(t, PresetTagSupportsRuntime("test-preset-tag-runtimes", "0.0.2", RuntimeNameVLLM))
+}
@@ -11,6 +11,7 @@ import (
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -50,12 +51,15 @@ var (
 		InitialDelaySeconds: 30,
 		PeriodSeconds:       10,
 	}
+)
 
-	tolerations = []corev1.Toleration{
+// gpuTolerations returns the tolerations of the taints of the GPU nodes of the vendor.
+func gpuTolerations(gpuVendor string) []corev1.Toleration {
+	return []corev1.Toleration{
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
 			Operator: corev1.TolerationOpExists,
-			Key:      resources.CapacityNvidiaGPU,
+			Key:      string(sku.GetGPUVendorConfig(gpuVendor).ResourceName),
 		},
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
@@ -64,7 +68,7 @@ var (
 			Operator: corev1.TolerationOpEqual,
 		},
 	}
-)
+}
 
 func CreatePresetRAG(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine, revisionNum string, kubeClient client.Client) (client.Object, error) {
 	var volumes []corev1.Volume
@@ -79,6 +83,7 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine,
 	}
 
 	var resourceReq corev1.ResourceRequirements
+	gpuVendor := utils.GetSKUGPUVendor(ctx, kubeClient, ragEngineObj.Status.WorkerNodes, ragEngineObj.Spec.Compute.InstanceType)
 
 	if ragEngineObj.Spec.Embedding.Local != nil {
 		skuNumGPUs, err := utils.GetSKUNumGPUs(ctx, kubeClient, ragEngineObj.Status.WorkerNodes,
@@ -87,12 +92,13 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine,
 			return nil, fmt.Errorf("failed to get SKU num GPUs: %v", err)
 		}
 
+		gpuResource := sku.GetGPUVendorConfig(gpuVendor).ResourceName
 		resourceReq = corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+				gpuResource: resource.MustParse(skuNumGPUs),
 			},
 			Limits: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+				gpuResource: resource.MustParse(skuNumGPUs),
 			},
 		}
 
@@ -104,7 +110,7 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine,
 	imagePullSecretRefs := []corev1.LocalObjectReference{}
 
 	depObj := manifests.GenerateRAGDeploymentManifest(ctx, ragEngineObj, revisionNum, image, imagePullSecretRefs, *ragEngineObj.Spec.Compute.Count, commands,
-		containerPorts, livenessProbe, readinessProbe, resourceReq, tolerations, volumes, volumeMounts)
+		containerPorts, livenessProbe, readinessProbe, resourceReq, gpuTolerations(gpuVendor), volumes, volumeMounts)
 
 	err := resources.CreateResource(ctx, depObj, kubeClient)
 	if client.IgnoreAlreadyExists(err) != nil {
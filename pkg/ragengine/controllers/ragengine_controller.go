@@ -19,6 +19,7 @@ import (
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/machine"
@@ -372,6 +373,9 @@ func (c *RAGEngineReconciler) applyRAGEngineResource(ctx context.Context, ragEng
 
 	if newNodesCount > 0 {
 		klog.InfoS("need to create more nodes", "NodeCount", newNodesCount)
+		if err := c.checkGPUQuota(ctx, ragEngineObj); err != nil {
+			return err
+		}
 		if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
 			if err := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj,
 				kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionUnknown,
@@ -582,6 +586,11 @@ RetryWithDifferentName:
 
 // ensureNodePlugins ensures node plugins are installed.
 func (c *RAGEngineReconciler) ensureNodePlugins(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine, nodeObj *corev1.Node) error {
+	// Only the NVIDIA device plugin is installed by Kaito, those of other vendors come with their GPU operators.
+	if utils.GetSKUGPUVendor(ctx, c.Client, nil, ragEngineObj.Spec.Compute.InstanceType) != sku.GPUVendorNvidia {
+		return nil
+	}
+
 	timeClock := clock.RealClock{}
 	tick := timeClock.NewTicker(consts.NodePluginInstallTimeout)
 	defer tick.Stop()
@@ -19,6 +19,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
@@ -102,6 +103,25 @@ func TestApplyRAGEngineResource(t *testing.T) {
 			ragengine:                   *test.MockRAGEngineDistributedModel,
 			expectedError:               errors.New("failed to list nodes"),
 		},
+		"Fail to apply ragengine because the nodes exceed the GPU quota of the namespace": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateMapWithType(&v1alpha1.GPUQuotaList{})[client.ObjectKey{Name: "team-quota"}] = &v1alpha1.GPUQuota{
+					ObjectMeta: v1.ObjectMeta{Name: "team-quota"},
+					Spec:       v1alpha1.GPUQuotaSpec{Namespaces: []string{"kaito"}, MaxNodes: lo.ToPtr(0)},
+				}
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.GPUQuotaList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.WorkspaceList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.RAGEngineList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.RAGEngine{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.RAGEngine{}), mock.Anything).Return(nil)
+			},
+			karpenterFeatureGateEnabled: true,
+			ragengine:                   *test.MockRAGEngineDistributedModel,
+			expectedError:               errors.New("GPU quota exceeded: quota team-quota allows 0 nodes in namespace kaito, 0 are used and 1 more are requested"),
+		},
 		"Successfully apply ragengine resource with machine": {
 			callMocks: func(c *test.MockClient) {
 				nodeList := test.MockNodeList
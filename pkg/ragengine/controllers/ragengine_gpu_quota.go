@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// checkGPUQuota checks the nodes of the ragengine against the GPU quotas of its namespace before new nodes are created.
+// If the nodes exceed a quota, the reason is recorded in the GPUQuotaSatisfied condition and returned as an error.
+func (c *RAGEngineReconciler) checkGPUQuota(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine) error {
+	err := kaitov1alpha1.ValidateGPUQuota(ctx, c.Client, ragEngineObj,
+		[]string{ragEngineObj.Spec.Compute.InstanceType}, lo.FromPtr(ragEngineObj.Spec.Compute.Count))
+	if err != nil {
+		if errors.Is(err, kaitov1alpha1.ErrGPUQuotaExceeded) {
+			if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1alpha1.ConditionTypeGPUQuotaStatus, metav1.ConditionFalse,
+				"GPUQuotaExceeded", err.Error()); updateErr != nil {
+				klog.ErrorS(updateErr, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
+				return updateErr
+			}
+		}
+		return err
+	}
+
+	if cond := meta.FindStatusCondition(ragEngineObj.Status.Conditions, string(kaitov1alpha1.ConditionTypeGPUQuotaStatus)); cond != nil && cond.Status != metav1.ConditionTrue {
+		if err := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1alpha1.ConditionTypeGPUQuotaStatus, metav1.ConditionTrue,
+			"GPUQuotaSatisfied", "nodes of the ragengine fit in the GPU quotas of the namespace"); err != nil {
+			klog.ErrorS(err, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
+			return err
+		}
+	}
+	return nil
+}
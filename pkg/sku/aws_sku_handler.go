@@ -3,6 +3,10 @@
 
 package sku
 
+import (
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
 var _ CloudSKUHandler = &AwsSKUHandler{}
 
 type AwsSKUHandler struct {
@@ -76,9 +80,9 @@ func NewAwsSKUHandler() *AwsSKUHandler {
 }
 
 func (a *AwsSKUHandler) GetSupportedSKUs() []string {
-	return GetMapKeys(a.supportedSKUs)
+	return GetMapKeys(a.GetGPUConfigs())
 }
 
 func (a *AwsSKUHandler) GetGPUConfigs() map[string]GPUConfig {
-	return a.supportedSKUs
+	return mergeCatalog(consts.AWSCloudName, a.supportedSKUs)
 }
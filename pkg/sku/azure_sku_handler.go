@@ -3,6 +3,10 @@
 
 package sku
 
+import (
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
 var _ CloudSKUHandler = &AzureSKUHandler{}
 
 type AzureSKUHandler struct {
@@ -46,9 +50,9 @@ func NewAzureSKUHandler() *AzureSKUHandler {
 }
 
 func (a *AzureSKUHandler) GetSupportedSKUs() []string {
-	return GetMapKeys(a.supportedSKUs)
+	return GetMapKeys(a.GetGPUConfigs())
 }
 
 func (a *AzureSKUHandler) GetGPUConfigs() map[string]GPUConfig {
-	return a.supportedSKUs
+	return mergeCatalog(consts.AzureCloudName, a.supportedSKUs)
 }
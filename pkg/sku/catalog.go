@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"gopkg.in/yaml.v2"
+)
+
+// CatalogKey is the key of the ConfigMap data that holds the SKU catalog.
+const CatalogKey = "skus.yaml"
+
+// Catalog is the SKUs defined in addition to the built-in SKUs, keyed by cloud provider and then by SKU. A SKU in the
+// catalog replaces the built-in SKU of the same name.
+type Catalog map[string]map[string]GPUConfig
+
+var (
+	catalogMu sync.RWMutex
+	catalog   Catalog
+)
+
+// ParseCatalog parses a SKU catalog that lists the SKUs of each cloud provider, e.g.,
+//
+//	azure:
+//	  - sku: Standard_ND96isr_H100_v5
+//	    gpuCount: 8
+//	    gpuMem: 640
+//	    gpuModel: NVIDIA H100
+//	    interconnect: InfiniBand
+//	    vendor: nvidia
+func ParseCatalog(data string) (Catalog, error) {
+	var skus map[string][]GPUConfig
+	if err := yaml.UnmarshalStrict([]byte(data), &skus); err != nil {
+		return nil, fmt.Errorf("failed to parse the SKU catalog: %w", err)
+	}
+	c := Catalog{}
+	for cloud, gpuConfigs := range skus {
+		switch cloud {
+		case consts.AzureCloudName, consts.AWSCloudName, consts.OnPremCloudName:
+		default:
+			return nil, fmt.Errorf("unsupported cloud provider %q in the SKU catalog", cloud)
+		}
+		c[cloud] = make(map[string]GPUConfig, len(gpuConfigs))
+		for _, gpuConfig := range gpuConfigs {
+			if gpuConfig.SKU == "" {
+				return nil, fmt.Errorf("a SKU of cloud provider %s has no name", cloud)
+			}
+			if gpuConfig.GPUCount <= 0 || gpuConfig.GPUMem <= 0 {
+				return nil, fmt.Errorf("SKU %s must have a positive gpuCount and gpuMem", gpuConfig.SKU)
+			}
+			if gpuConfig.Vendor != "" && !IsSupportedGPUVendor(gpuConfig.Vendor) {
+				return nil, fmt.Errorf("SKU %s has an unsupported GPU vendor %s", gpuConfig.SKU, gpuConfig.Vendor)
+			}
+			if _, ok := c[cloud][gpuConfig.SKU]; ok {
+				return nil, fmt.Errorf("SKU %s is defined more than once for cloud provider %s", gpuConfig.SKU, cloud)
+			}
+			c[cloud][gpuConfig.SKU] = gpuConfig
+		}
+	}
+	return c, nil
+}
+
+// SetCatalog replaces the SKU catalog that is merged with the built-in SKUs. A nil catalog leaves only the built-in SKUs.
+func SetCatalog(c Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog = c
+}
+
+// mergeCatalog returns the built-in SKUs of the cloud provider with the SKUs of the catalog added or replaced.
+func mergeCatalog(cloud string, builtIn map[string]GPUConfig) map[string]GPUConfig {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	if len(catalog[cloud]) == 0 {
+		return builtIn
+	}
+	merged := make(map[string]GPUConfig, len(builtIn)+len(catalog[cloud]))
+	for k, v := range builtIn {
+		merged[k] = v
+	}
+	for k, v := range catalog[cloud] {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCatalog(t *testing.T) {
+	testcases := map[string]struct {
+		data        string
+		expected    Catalog
+		expectedErr string
+	}{
+		"Valid catalog": {
+			data: `
+azure:
+  - sku: Standard_ND96isr_H100_v5
+    gpuCount: 8
+    gpuMem: 640
+    gpuModel: NVIDIA H100
+    interconnect: InfiniBand
+    vendor: nvidia
+aws:
+  - sku: p5.48xlarge
+    gpuCount: 8
+    gpuMem: 640
+`,
+			expected: Catalog{
+				"azure": {"Standard_ND96isr_H100_v5": {SKU: "Standard_ND96isr_H100_v5", GPUCount: 8, GPUMem: 640,
+					GPUModel: "NVIDIA H100", Interconnect: "InfiniBand", Vendor: "nvidia"}},
+				"aws": {"p5.48xlarge": {SKU: "p5.48xlarge", GPUCount: 8, GPUMem: 640}},
+			},
+		},
+		"Empty catalog": {
+			data:     "",
+			expected: Catalog{},
+		},
+		"Unknown field": {
+			data:        "azure:\n  - sku: a\n    gpuCount: 1\n    gpuMemory: 16\n",
+			expectedErr: "failed to parse the SKU catalog",
+		},
+		"Unsupported cloud provider": {
+			data:        "gcp:\n  - sku: a\n    gpuCount: 1\n    gpuMem: 16\n",
+			expectedErr: `unsupported cloud provider "gcp"`,
+		},
+		"Missing SKU name": {
+			data:        "azure:\n  - gpuCount: 1\n    gpuMem: 16\n",
+			expectedErr: "has no name",
+		},
+		"Missing GPU memory": {
+			data:        "azure:\n  - sku: a\n    gpuCount: 1\n",
+			expectedErr: "SKU a must have a positive gpuCount and gpuMem",
+		},
+		"Duplicate SKU": {
+			data:        "azure:\n  - sku: a\n    gpuCount: 1\n    gpuMem: 16\n  - sku: a\n    gpuCount: 2\n    gpuMem: 32\n",
+			expectedErr: "SKU a is defined more than once",
+		},
+		"Unsupported GPU vendor": {
+			data:        "onprem:\n  - sku: a\n    gpuCount: 1\n    gpuMem: 16\n    vendor: unknown\n",
+			expectedErr: "SKU a has an unsupported GPU vendor unknown",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			c, err := ParseCatalog(tc.data)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+func TestCatalogMergedWithBuiltInSKUs(t *testing.T) {
+	defer SetCatalog(nil)
+
+	builtIn := len(NewAzureSKUHandler().GetGPUConfigs())
+	SetCatalog(Catalog{
+		"azure": {
+			"Standard_ND96isr_H100_v5": {SKU: "Standard_ND96isr_H100_v5", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA H100"},
+			"Standard_NC6s_v3":         {SKU: "Standard_NC6s_v3", GPUCount: 1, GPUMem: 32, GPUModel: "NVIDIA V100"},
+		},
+	})
+
+	azure := NewAzureSKUHandler()
+	configs := azure.GetGPUConfigs()
+	assert.Equal(t, builtIn+1, len(configs))
+	assert.Equal(t, 640, configs["Standard_ND96isr_H100_v5"].GPUMem)
+	// The catalog replaces the built-in SKU of the same name.
+	assert.Equal(t, 32, configs["Standard_NC6s_v3"].GPUMem)
+	assert.Contains(t, azure.GetSupportedSKUs(), "Standard_ND96isr_H100_v5")
+	// The SKUs of other cloud providers are not affected.
+	assert.NotContains(t, NewAwsSKUHandler().GetSupportedSKUs(), "Standard_ND96isr_H100_v5")
+
+	SetCatalog(nil)
+	assert.Equal(t, builtIn, len(azure.GetGPUConfigs()))
+	assert.Equal(t, 16, azure.GetGPUConfigs()["Standard_NC6s_v3"].GPUMem)
+}
@@ -4,6 +4,7 @@
 package sku
 
 import (
+	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
@@ -12,11 +13,16 @@ type CloudSKUHandler interface {
 	GetGPUConfigs() map[string]GPUConfig
 }
 
+// GPUConfig describes the GPUs of a SKU.
 type GPUConfig struct {
-	SKU      string
-	GPUCount int
-	GPUMem   int
-	GPUModel string
+	SKU      string `yaml:"sku"`
+	GPUCount int    `yaml:"gpuCount"`
+	GPUMem   int    `yaml:"gpuMem"` // Total memory of the GPUs in GiB.
+	GPUModel string `yaml:"gpuModel"`
+	// Interconnect is the interconnect between the GPUs or the nodes, e.g., NVLink or InfiniBand.
+	Interconnect string `yaml:"interconnect"`
+	// Vendor is the vendor of the GPUs, e.g., nvidia or amd.
+	Vendor string `yaml:"vendor"`
 }
 
 func GetCloudSKUHandler(cloud string) CloudSKUHandler {
@@ -25,6 +31,8 @@ func GetCloudSKUHandler(cloud string) CloudSKUHandler {
 		return NewAzureSKUHandler()
 	case consts.AWSCloudName:
 		return NewAwsSKUHandler()
+	case consts.OnPremCloudName:
+		return NewNodeSKUHandler(k8sclient.GetGlobalClient())
 	default:
 		return nil
 	}
@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Labels of the GPU nodes set by the GPU feature discovery of the NVIDIA GPU Operator, and by the node labeller of
+// the AMD GPU Operator.
+const (
+	LabelNvidiaGPUProduct = "nvidia.com/gpu.product"
+	LabelNvidiaGPUMemory  = "nvidia.com/gpu.memory" // Memory of a GPU in MiB.
+	LabelNvidiaGPUCount   = "nvidia.com/gpu.count"
+	LabelAMDGPUProduct    = "amd.com/gpu.product-name"
+	LabelAMDGPUVRAM       = "amd.com/gpu.vram" // Memory of a GPU in GiB, e.g., 192G.
+)
+
+var _ CloudSKUHandler = &NodeSKUHandler{}
+
+// NodeSKUHandler supports the SKUs of the existing GPU nodes of on-prem and bring-your-own clusters, which are built
+// from the node labels and capacity instead of a list of instance types of a cloud provider.
+type NodeSKUHandler struct {
+	reader client.Reader
+}
+
+func NewNodeSKUHandler(reader client.Reader) *NodeSKUHandler {
+	return &NodeSKUHandler{
+		reader: reader,
+	}
+}
+
+func (n *NodeSKUHandler) GetSupportedSKUs() []string {
+	return GetMapKeys(n.GetGPUConfigs())
+}
+
+// GetGPUConfigs returns the GPU configs of the SKUs of the current nodes. When nodes of the same SKU differ, the
+// smallest GPU config is returned, so that a workload fitting the SKU fits every node of it.
+func (n *NodeSKUHandler) GetGPUConfigs() map[string]GPUConfig {
+	gpuConfigs := map[string]GPUConfig{}
+	if n.reader == nil {
+		return mergeCatalog(consts.OnPremCloudName, gpuConfigs)
+	}
+	nodeList := &corev1.NodeList{}
+	if err := n.reader.List(context.Background(), nodeList); err != nil {
+		klog.ErrorS(err, "failed to list nodes for their GPU configs")
+		return mergeCatalog(consts.OnPremCloudName, gpuConfigs)
+	}
+	for i := range nodeList.Items {
+		gpuConfig, ok := NodeGPUConfig(&nodeList.Items[i])
+		if !ok {
+			continue
+		}
+		if existing, found := gpuConfigs[gpuConfig.SKU]; found &&
+			(existing.GPUCount < gpuConfig.GPUCount || existing.GPUMem < gpuConfig.GPUMem) {
+			continue
+		}
+		gpuConfigs[gpuConfig.SKU] = gpuConfig
+	}
+	return mergeCatalog(consts.OnPremCloudName, gpuConfigs)
+}
+
+// NodeSKU returns the SKU of the node, which is its instance type if the node has one, or the product name of its
+// GPUs otherwise, e.g., NVIDIA-A100-SXM4-80GB.
+func NodeSKU(node *corev1.Node) string {
+	if instanceType := node.Labels[corev1.LabelInstanceTypeStable]; instanceType != "" {
+		return instanceType
+	}
+	if product := node.Labels[LabelNvidiaGPUProduct]; product != "" {
+		return product
+	}
+	return node.Labels[LabelAMDGPUProduct]
+}
+
+// NodeGPUConfig returns the GPU config of the node from its labels and capacity. It returns false if the node has
+// no GPU or its GPUs are not labeled.
+func NodeGPUConfig(node *corev1.Node) (GPUConfig, bool) {
+	var vendor, product string
+	var memPerGPU int // GPU memory in MiB
+	var err error
+	switch {
+	case node.Labels[LabelNvidiaGPUProduct] != "":
+		vendor, product = GPUVendorNvidia, node.Labels[LabelNvidiaGPUProduct]
+		memPerGPU, err = strconv.Atoi(node.Labels[LabelNvidiaGPUMemory])
+	case node.Labels[LabelAMDGPUProduct] != "":
+		vendor, product = GPUVendorAMD, node.Labels[LabelAMDGPUProduct]
+		memPerGPU, err = strconv.Atoi(strings.TrimSuffix(node.Labels[LabelAMDGPUVRAM], "G"))
+		memPerGPU *= 1024
+	default:
+		return GPUConfig{}, false
+	}
+	if err != nil || memPerGPU <= 0 {
+		return GPUConfig{}, false
+	}
+
+	gpuCount, err := strconv.Atoi(node.Labels[LabelNvidiaGPUCount])
+	if vendor != GPUVendorNvidia || err != nil || gpuCount <= 0 {
+		capacity, ok := node.Status.Capacity[GetGPUVendorConfig(vendor).ResourceName]
+		if !ok || capacity.Value() <= 0 {
+			return GPUConfig{}, false
+		}
+		gpuCount = int(capacity.Value())
+	}
+
+	return GPUConfig{
+		SKU:      NodeSKU(node),
+		GPUCount: gpuCount,
+		GPUMem:   gpuCount * memPerGPU / 1024,
+		GPUModel: product,
+		Vendor:   vendor,
+	}, true
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testGPUNode(name string, labels map[string]string, gpus string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	if gpus != "" {
+		node.Status.Capacity = corev1.ResourceList{consts.NvidiaGPU: resource.MustParse(gpus)}
+	}
+	return node
+}
+
+func TestNodeGPUConfig(t *testing.T) {
+	testcases := map[string]struct {
+		node     *corev1.Node
+		expected GPUConfig
+		ok       bool
+	}{
+		"Node labeled by the GPU operator": {
+			node: testGPUNode("node1", map[string]string{
+				LabelNvidiaGPUProduct: "NVIDIA-A100-SXM4-80GB",
+				LabelNvidiaGPUMemory:  "81920",
+				LabelNvidiaGPUCount:   "8",
+			}, ""),
+			expected: GPUConfig{SKU: "NVIDIA-A100-SXM4-80GB", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA-A100-SXM4-80GB", Vendor: "nvidia"},
+			ok:       true,
+		},
+		"Node with an instance type and the GPU count in its capacity": {
+			node: testGPUNode("node2", map[string]string{
+				corev1.LabelInstanceTypeStable: "dgx-a100",
+				LabelNvidiaGPUProduct:          "NVIDIA-A100-SXM4-40GB",
+				LabelNvidiaGPUMemory:           "40960",
+			}, "2"),
+			expected: GPUConfig{SKU: "dgx-a100", GPUCount: 2, GPUMem: 80, GPUModel: "NVIDIA-A100-SXM4-40GB", Vendor: "nvidia"},
+			ok:       true,
+		},
+		"Node labeled by the AMD GPU operator": {
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node5", Labels: map[string]string{
+					LabelAMDGPUProduct: "AMD-Instinct-MI300X",
+					LabelAMDGPUVRAM:    "192G",
+				}},
+				Status: corev1.NodeStatus{Capacity: corev1.ResourceList{"amd.com/gpu": resource.MustParse("8")}},
+			},
+			expected: GPUConfig{SKU: "AMD-Instinct-MI300X", GPUCount: 8, GPUMem: 1536, GPUModel: "AMD-Instinct-MI300X", Vendor: "amd"},
+			ok:       true,
+		},
+		"Node without GPU labels": {
+			node: testGPUNode("node3", map[string]string{corev1.LabelInstanceTypeStable: "cpu-only"}, ""),
+		},
+		"Node without GPUs": {
+			node: testGPUNode("node4", map[string]string{
+				LabelNvidiaGPUProduct: "NVIDIA-A100-SXM4-80GB",
+				LabelNvidiaGPUMemory:  "81920",
+			}, ""),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			gpuConfig, ok := NodeGPUConfig(tc.node)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, gpuConfig)
+		})
+	}
+}
+
+func TestNodeSKUHandler(t *testing.T) {
+	defer SetCatalog(nil)
+
+	a100 := map[string]string{
+		LabelNvidiaGPUProduct: "NVIDIA-A100-SXM4-80GB",
+		LabelNvidiaGPUMemory:  "81920",
+	}
+	reader := fake.NewClientBuilder().WithObjects(
+		testGPUNode("node1", a100, "8"),
+		// A node of the same SKU with fewer GPUs, e.g., because of a failed GPU, limits the SKU.
+		testGPUNode("node2", a100, "4"),
+		testGPUNode("node3", map[string]string{corev1.LabelInstanceTypeStable: "cpu-only"}, ""),
+	).Build()
+	SetCatalog(Catalog{
+		consts.OnPremCloudName: {"custom": {SKU: "custom", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA L4"}},
+	})
+
+	handler := NewNodeSKUHandler(reader)
+	assert.ElementsMatch(t, []string{"NVIDIA-A100-SXM4-80GB", "custom"}, handler.GetSupportedSKUs())
+	assert.Equal(t, 4, handler.GetGPUConfigs()["NVIDIA-A100-SXM4-80GB"].GPUCount)
+	assert.Equal(t, 320, handler.GetGPUConfigs()["NVIDIA-A100-SXM4-80GB"].GPUMem)
+
+	// Without a client, only the SKUs of the catalog are supported.
+	assert.ElementsMatch(t, []string{"custom"}, NewNodeSKUHandler(nil).GetSupportedSKUs())
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package recommender
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Evaluation describes how a number of nodes of a SKU meet the GPU requirements of a model for inference.
+type Evaluation struct {
+	sku.GPUConfig
+	NodeCount int
+
+	// RequiredGPUs is the GPU count requirement of the model, AvailableGPUs is the number of GPUs of the nodes.
+	RequiredGPUs  int64
+	AvailableGPUs int64
+
+	// RequiredPerGPUMemory is the per GPU memory requirement of models without an architecture, and
+	// AvailablePerGPUMemory is the memory of a GPU of the SKU.
+	RequiredPerGPUMemory  resource.Quantity
+	AvailablePerGPUMemory resource.Quantity
+
+	// RequiredGPUMemory is the total GPU memory requirement, or the estimate for models with an architecture, and
+	// AvailableGPUMemory is the memory of the GPUs that the model is sharded across.
+	RequiredGPUMemory  resource.Quantity
+	AvailableGPUMemory resource.Quantity
+
+	// Estimate is the GPU memory estimate of models with an architecture.
+	Estimate *model.MemoryEstimate
+	// EstimateError is the error of estimating the GPU memory, in which case the SKU does not qualify.
+	EstimateError error
+}
+
+// Evaluate checks nodeCount nodes of the SKU against the GPU requirements of the model served by the runtime. The
+// webhook rejects workspaces on the same conditions.
+func Evaluate(m model.Model, runtime model.RuntimeName, gpuConfig sku.GPUConfig, nodeCount int) *Evaluation {
+	params := m.GetInferenceParameters()
+	requiredGPUs := resource.MustParse(params.GPUCountRequirement)
+	e := &Evaluation{
+		GPUConfig:             gpuConfig,
+		NodeCount:             nodeCount,
+		RequiredGPUs:          requiredGPUs.Value(),
+		AvailableGPUs:         int64(nodeCount * gpuConfig.GPUCount),
+		AvailablePerGPUMemory: *resource.NewQuantity(int64(gpuConfig.GPUMem/gpuConfig.GPUCount)*consts.GiBToBytes, resource.BinarySI),
+	}
+
+	if params.Architecture == nil {
+		e.RequiredPerGPUMemory = resource.MustParse(params.PerGPUMemoryRequirement)
+		e.RequiredGPUMemory = resource.MustParse(params.TotalGPUMemoryRequirement)
+		e.AvailableGPUMemory = *resource.NewQuantity(int64(nodeCount*gpuConfig.GPUMem)*consts.GiBToBytes, resource.BinarySI)
+		return e
+	}
+
+	// vLLM shards the model across the GPUs of a node by tensor parallelism unless it is disabled, and across the
+	// nodes by pipeline parallelism, while transformers shards the model across the GPUs of a node.
+	numGPUs := gpuConfig.GPUCount
+	if runtime == model.RuntimeNameVLLM {
+		if params.DisableTensorParallelism {
+			numGPUs = 1
+		} else if m.SupportDistributedInference() {
+			numGPUs *= nodeCount
+		}
+		e.Estimate, e.EstimateError = params.EstimateGPUMemory(numGPUs)
+	} else {
+		e.Estimate, e.EstimateError = model.EstimateGPUMemory(params.Architecture, model.MemoryEstimateOptions{NumGPUs: numGPUs})
+	}
+	if e.Estimate != nil {
+		e.RequiredGPUMemory = *resource.NewQuantity(e.Estimate.Total(), resource.BinarySI)
+	}
+	e.AvailableGPUMemory = *resource.NewQuantity(int64(gpuConfig.GPUMem/gpuConfig.GPUCount*numGPUs)*consts.GiBToBytes, resource.BinarySI)
+	return e
+}
+
+// Fits returns whether the nodes meet all the GPU requirements of the model.
+func (e *Evaluation) Fits() bool {
+	if e.EstimateError != nil || e.AvailableGPUs < e.RequiredGPUs {
+		return false
+	}
+	if e.Estimate == nil && e.AvailablePerGPUMemory.Cmp(e.RequiredPerGPUMemory) < 0 {
+		return false
+	}
+	return e.AvailableGPUMemory.Cmp(e.RequiredGPUMemory) >= 0
+}
+
+// Headroom returns the GPU memory in bytes that is left after the model is loaded.
+func (e *Evaluation) Headroom() int64 {
+	return e.AvailableGPUMemory.Value() - e.RequiredGPUMemory.Value()
+}
+
+// Constraints restrict the SKUs that are recommended.
+type Constraints struct {
+	// MaxGPUs is the maximum number of GPUs per node. Zero means no limit.
+	MaxGPUs int
+	// GPUFamily is the GPU model that the SKU must have, e.g., A100. It matches any part of the GPU model of the SKU,
+	// ignoring the case.
+	GPUFamily string
+	// NodeCount is the number of nodes the model runs on. Defaults to 1.
+	NodeCount int
+}
+
+// Recommendation is a SKU of a cloud that meets the GPU requirements of a model.
+type Recommendation struct {
+	Cloud string
+	*Evaluation
+}
+
+// Recommend returns the SKUs of the SKU handlers, keyed by cloud, that meet the GPU requirements of the model within
+// the constraints. They are ranked by the number of GPUs and then by the GPU memory headroom, so that the smallest
+// qualifying SKUs come first.
+func Recommend(m model.Model, runtime model.RuntimeName, constraints Constraints, handlers map[string]sku.CloudSKUHandler) []Recommendation {
+	nodeCount := constraints.NodeCount
+	if nodeCount <= 0 {
+		nodeCount = 1
+	}
+	var recommendations []Recommendation
+	for cloud, handler := range handlers {
+		for _, gpuConfig := range handler.GetGPUConfigs() {
+			if !sku.IsEnabledGPUVendor(gpuConfig.GPUVendor()) {
+				continue
+			}
+			if constraints.MaxGPUs > 0 && gpuConfig.GPUCount > constraints.MaxGPUs {
+				continue
+			}
+			if constraints.GPUFamily != "" && !strings.Contains(strings.ToLower(gpuConfig.GPUModel), strings.ToLower(constraints.GPUFamily)) {
+				continue
+			}
+			if e := Evaluate(m, runtime, gpuConfig, nodeCount); e.Fits() {
+				recommendations = append(recommendations, Recommendation{Cloud: cloud, Evaluation: e})
+			}
+		}
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.GPUCount != b.GPUCount {
+			return a.GPUCount < b.GPUCount
+		}
+		if a.Headroom() != b.Headroom() {
+			return a.Headroom() < b.Headroom()
+		}
+		if a.Cloud != b.Cloud {
+			return a.Cloud < b.Cloud
+		}
+		return a.SKU < b.SKU
+	})
+	return recommendations
+}
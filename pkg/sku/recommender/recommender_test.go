@@ -0,0 +1,217 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package recommender
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/stretchr/testify/assert"
+)
+
+type testModel struct {
+	param       *model.PresetParam
+	distributed bool
+}
+
+func (m *testModel) GetInferenceParameters() *model.PresetParam {
+	return m.param
+}
+func (*testModel) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+func (m *testModel) SupportDistributedInference() bool {
+	return m.distributed
+}
+func (*testModel) SupportTuning() bool {
+	return false
+}
+
+func fixedModel(gpuCount, totalGPUMemory, perGPUMemory string) *testModel {
+	return &testModel{param: &model.PresetParam{
+		GPUCountRequirement:       gpuCount,
+		TotalGPUMemoryRequirement: totalGPUMemory,
+		PerGPUMemoryRequirement:   perGPUMemory,
+	}}
+}
+
+func architectureModel(maxModelLen string, disableTensorParallelism bool) *testModel {
+	return &testModel{param: &model.PresetParam{
+		GPUCountRequirement: "1",
+		Architecture: &model.ModelArchitecture{
+			ParameterCount:    7241732096,
+			DType:             "bfloat16",
+			NumHiddenLayers:   32,
+			HiddenSize:        4096,
+			IntermediateSize:  14336,
+			NumAttentionHeads: 32,
+			NumKeyValueHeads:  8,
+		},
+		RuntimeParam: model.RuntimeParam{
+			VLLM:                     model.VLLMParam{ModelRunParams: map[string]string{"max-model-len": maxModelLen}},
+			DisableTensorParallelism: disableTensorParallelism,
+		},
+	}}
+}
+
+func TestEvaluate(t *testing.T) {
+	v100 := sku.GPUConfig{SKU: "v100", GPUCount: 1, GPUMem: 16, GPUModel: "NVIDIA V100"}
+	v100x2 := sku.GPUConfig{SKU: "v100x2", GPUCount: 2, GPUMem: 32, GPUModel: "NVIDIA V100"}
+
+	testcases := []struct {
+		name      string
+		model     *testModel
+		runtime   model.RuntimeName
+		gpuConfig sku.GPUConfig
+		nodeCount int
+		fits      bool
+	}{
+		{
+			name:      "fixed requirements fit",
+			model:     fixedModel("1", "16Gi", "16Gi"),
+			gpuConfig: v100,
+			nodeCount: 1,
+			fits:      true,
+		},
+		{
+			name:      "insufficient GPUs",
+			model:     fixedModel("2", "16Gi", "0Gi"),
+			gpuConfig: v100,
+			nodeCount: 1,
+			fits:      false,
+		},
+		{
+			name:      "insufficient per GPU memory",
+			model:     fixedModel("1", "16Gi", "20Gi"),
+			gpuConfig: v100x2,
+			nodeCount: 1,
+			fits:      false,
+		},
+		{
+			name:      "total GPU memory of multiple nodes",
+			model:     fixedModel("1", "30Gi", "0Gi"),
+			gpuConfig: v100,
+			nodeCount: 2,
+			fits:      true,
+		},
+		{
+			name:      "estimate fits",
+			model:     architectureModel("4096", false),
+			runtime:   model.RuntimeNameVLLM,
+			gpuConfig: v100,
+			nodeCount: 1,
+			fits:      true,
+		},
+		{
+			name:      "estimate of long context does not fit",
+			model:     architectureModel("32768", false),
+			runtime:   model.RuntimeNameVLLM,
+			gpuConfig: v100,
+			nodeCount: 1,
+			fits:      false,
+		},
+		{
+			name:      "estimate of long context fits with tensor parallelism",
+			model:     architectureModel("32768", false),
+			runtime:   model.RuntimeNameVLLM,
+			gpuConfig: v100x2,
+			nodeCount: 1,
+			fits:      true,
+		},
+		{
+			name:      "estimate of long context does not fit without tensor parallelism",
+			model:     architectureModel("32768", true),
+			runtime:   model.RuntimeNameVLLM,
+			gpuConfig: v100x2,
+			nodeCount: 1,
+			fits:      false,
+		},
+		{
+			name:      "transformers does not use the max-model-len of vLLM",
+			model:     architectureModel("32768", false),
+			runtime:   model.RuntimeNameHuggingfaceTransformers,
+			gpuConfig: v100,
+			nodeCount: 1,
+			fits:      true,
+		},
+		{
+			name:      "estimate error",
+			model:     architectureModel("long", false),
+			runtime:   model.RuntimeNameVLLM,
+			gpuConfig: v100x2,
+			nodeCount: 1,
+			fits:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := Evaluate(tc.model, tc.runtime, tc.gpuConfig, tc.nodeCount)
+			assert.Equal(t, tc.fits, e.Fits())
+		})
+	}
+}
+
+type testSKUHandler map[string]sku.GPUConfig
+
+func (h testSKUHandler) GetSupportedSKUs() []string {
+	return sku.GetMapKeys(h)
+}
+func (h testSKUHandler) GetGPUConfigs() map[string]sku.GPUConfig {
+	return h
+}
+
+func TestRecommend(t *testing.T) {
+	handlers := map[string]sku.CloudSKUHandler{
+		"cloud-a": testSKUHandler{
+			"a-t4":        {SKU: "a-t4", GPUCount: 1, GPUMem: 16, GPUModel: "NVIDIA T4"},
+			"a-a100":      {SKU: "a-a100", GPUCount: 1, GPUMem: 80, GPUModel: "NVIDIA A100"},
+			"a-a100x8":    {SKU: "a-a100x8", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA A100"},
+			"a-too-small": {SKU: "a-too-small", GPUCount: 1, GPUMem: 8, GPUModel: "NVIDIA M60"},
+		},
+		"cloud-b": testSKUHandler{
+			"b-a10":    {SKU: "b-a10", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA A10G"},
+			"b-l4x4":   {SKU: "b-l4x4", GPUCount: 4, GPUMem: 96, GPUModel: "NVIDIA L4"},
+			"b-a100x8": {SKU: "b-a100x8", GPUCount: 8, GPUMem: 320, GPUModel: "NVIDIA A100"},
+		},
+	}
+	m := fixedModel("1", "16Gi", "0Gi")
+
+	testcases := []struct {
+		name        string
+		constraints Constraints
+		expected    []string
+	}{
+		{
+			name:     "ranked by GPU count and headroom",
+			expected: []string{"a-t4", "b-a10", "a-a100", "b-l4x4", "b-a100x8", "a-a100x8"},
+		},
+		{
+			name:        "max GPUs",
+			constraints: Constraints{MaxGPUs: 1},
+			expected:    []string{"a-t4", "b-a10", "a-a100"},
+		},
+		{
+			name:        "GPU family",
+			constraints: Constraints{GPUFamily: "a100"},
+			expected:    []string{"a-a100", "b-a100x8", "a-a100x8"},
+		},
+		{
+			name:        "node count",
+			constraints: Constraints{MaxGPUs: 1, NodeCount: 2},
+			expected:    []string{"a-too-small", "a-t4", "b-a10", "a-a100"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var skus []string
+			for _, r := range Recommend(m, model.RuntimeNameVLLM, tc.constraints, handlers) {
+				skus = append(skus, r.SKU)
+			}
+			assert.Equal(t, tc.expected, skus)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	GPUVendorNvidia = "nvidia"
+	GPUVendorAMD    = "amd"
+)
+
+// GPUVendorConfig describes how the GPUs of a vendor are exposed to the workloads.
+type GPUVendorConfig struct {
+	// ResourceName is the extended resource of the GPUs advertised by the device plugin of the vendor. The GPU
+	// nodes are usually tainted with it too.
+	ResourceName corev1.ResourceName
+	// ImageTagSuffix selects the variant of the preset images built for the software stack of the vendor.
+	ImageTagSuffix string
+	// AllocConfEnv is the environment variable that configures the GPU memory allocator of PyTorch.
+	AllocConfEnv string
+	// FeatureGate is the feature gate that must be enabled to run workloads on the GPUs of the vendor, if any.
+	FeatureGate string
+}
+
+var gpuVendorConfigs = map[string]GPUVendorConfig{
+	GPUVendorNvidia: {
+		ResourceName: "nvidia.com/gpu",
+		AllocConfEnv: "PYTORCH_CUDA_ALLOC_CONF",
+	},
+	GPUVendorAMD: {
+		ResourceName:   "amd.com/gpu",
+		ImageTagSuffix: "-rocm",
+		AllocConfEnv:   "PYTORCH_HIP_ALLOC_CONF",
+		// The -rocm preset images are not published yet, so they have to be built and pushed to the preset registry
+		// before the AMDGPU feature gate is enabled.
+		FeatureGate: consts.FeatureFlagAMDGPU,
+	},
+}
+
+// IsSupportedGPUVendor returns whether the GPUs of the vendor are supported.
+func IsSupportedGPUVendor(vendor string) bool {
+	_, ok := gpuVendorConfigs[strings.ToLower(vendor)]
+	return ok
+}
+
+// IsEnabledGPUVendor returns whether workloads can run on the GPUs of the vendor, i.e., the vendor is supported and
+// its feature gate, if any, is enabled.
+func IsEnabledGPUVendor(vendor string) bool {
+	c, ok := gpuVendorConfigs[strings.ToLower(vendor)]
+	return ok && (c.FeatureGate == "" || featuregates.FeatureGates[c.FeatureGate])
+}
+
+// GetGPUVendorConfig returns the config of the GPU vendor, defaulting to NVIDIA for unknown vendors.
+func GetGPUVendorConfig(vendor string) GPUVendorConfig {
+	if c, ok := gpuVendorConfigs[strings.ToLower(vendor)]; ok {
+		return c
+	}
+	return gpuVendorConfigs[GPUVendorNvidia]
+}
+
+// SupportedGPUVendors returns the supported GPU vendors.
+func SupportedGPUVendors() []string {
+	return []string{GPUVendorNvidia, GPUVendorAMD}
+}
+
+// GPUVendor returns the vendor of the GPUs of the SKU. SKUs without a vendor, like most of the built-in SKUs, are
+// assumed to have NVIDIA GPUs unless their GPU model says otherwise.
+func (c GPUConfig) GPUVendor() string {
+	if c.Vendor != "" {
+		return strings.ToLower(c.Vendor)
+	}
+	if strings.HasPrefix(c.GPUModel, "AMD ") {
+		return GPUVendorAMD
+	}
+	return GPUVendorNvidia
+}
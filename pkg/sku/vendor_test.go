@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGPUVendor(t *testing.T) {
+	testcases := map[string]struct {
+		gpuConfig GPUConfig
+		expected  string
+	}{
+		"Vendor of the SKU":           {gpuConfig: GPUConfig{GPUModel: "Radeon Instinct MI25", Vendor: "AMD"}, expected: GPUVendorAMD},
+		"Vendor from the GPU model":   {gpuConfig: GPUConfig{GPUModel: "AMD Radeon PRO V620"}, expected: GPUVendorAMD},
+		"NVIDIA GPUs without vendor":  {gpuConfig: GPUConfig{GPUModel: "NVIDIA A100"}, expected: GPUVendorNvidia},
+		"SKU without vendor or model": {gpuConfig: GPUConfig{}, expected: GPUVendorNvidia},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.gpuConfig.GPUVendor())
+		})
+	}
+}
+
+func TestGetGPUVendorConfig(t *testing.T) {
+	assert.Equal(t, "amd.com/gpu", string(GetGPUVendorConfig("amd").ResourceName))
+	assert.Equal(t, "-rocm", GetGPUVendorConfig("AMD").ImageTagSuffix)
+	assert.Equal(t, "nvidia.com/gpu", string(GetGPUVendorConfig("nvidia").ResourceName))
+	// Unknown vendors fall back to NVIDIA.
+	assert.Equal(t, GetGPUVendorConfig(GPUVendorNvidia), GetGPUVendorConfig("unknown"))
+	assert.False(t, IsSupportedGPUVendor("unknown"))
+}
+
+func TestIsEnabledGPUVendor(t *testing.T) {
+	original := featuregates.FeatureGates[consts.FeatureFlagAMDGPU]
+	defer func() { featuregates.FeatureGates[consts.FeatureFlagAMDGPU] = original }()
+
+	featuregates.FeatureGates[consts.FeatureFlagAMDGPU] = false
+	assert.True(t, IsEnabledGPUVendor(GPUVendorNvidia))
+	assert.False(t, IsEnabledGPUVendor(GPUVendorAMD))
+	assert.False(t, IsEnabledGPUVendor("unknown"))
+
+	featuregates.FeatureGates[consts.FeatureFlagAMDGPU] = true
+	assert.True(t, IsEnabledGPUVendor("AMD"))
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// DefaultOrasImage is the image running the oras CLI if the ORAS_IMAGE environment variable is not set.
+	DefaultOrasImage = "ghcr.io/oras-project/oras:v1.2.0"
+	// AdapterArtifactType is the artifact type of the adapters pushed by the tuning jobs.
+	AdapterArtifactType = "application/vnd.kaito.adapter.v1"
+	// registryConfigPath is where ConfigImagePushSecretVolume mounts the credentials of the registry.
+	registryConfigPath = "/tmp/.docker/config/config.json"
+)
+
+// GetOrasImage returns the image running the oras CLI.
+func GetOrasImage() string {
+	if image := os.Getenv(consts.OrasImageEnvVar); image != "" {
+		return image
+	}
+	return DefaultOrasImage
+}
+
+// GetOrasPushCommand returns the oras command that pushes the files in the working directory as the layers of an
+// adapter artifact.
+func GetOrasPushCommand(artifact string, files []string, withCredentials bool) string {
+	return fmt.Sprintf("oras push %s --artifact-type %s%s %s", artifact, AdapterArtifactType,
+		getOrasRegistryFlags(artifact, withCredentials), strings.Join(files, " "))
+}
+
+// GetOrasPullCommand returns the oras command that pulls the files of an artifact to the output directory.
+func GetOrasPullCommand(artifact string, outputDir string, withCredentials bool) string {
+	return fmt.Sprintf("oras pull %s --output %s%s", artifact, outputDir, getOrasRegistryFlags(artifact, withCredentials))
+}
+
+// getOrasRegistryFlags returns the flags of the oras CLI to access the registry of the artifact. The credentials of
+// the registry are read from the mounted image secret, if any.
+func getOrasRegistryFlags(artifact string, withCredentials bool) string {
+	var flags string
+	if withCredentials {
+		flags += " --registry-config " + registryConfigPath
+	}
+	if isPlainHTTPRegistry(artifact) {
+		flags += " --plain-http"
+	}
+	return flags
+}
+
+// isPlainHTTPRegistry returns whether the registry of the artifact is listed in the PLAIN_HTTP_REGISTRIES
+// environment variable.
+func isPlainHTTPRegistry(artifact string) bool {
+	registry := strings.SplitN(artifact, "/", 2)[0]
+	for _, plainHTTPRegistry := range strings.Split(os.Getenv(consts.PlainHTTPRegistriesEnvVar), ",") {
+		if strings.TrimSpace(plainHTTPRegistry) == registry {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package utils
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrasImage(t *testing.T) {
+	t.Setenv(consts.OrasImageEnvVar, "")
+	assert.Equal(t, DefaultOrasImage, GetOrasImage())
+
+	t.Setenv(consts.OrasImageEnvVar, "myregistry.azurecr.io/oras:v1.2.0")
+	assert.Equal(t, "myregistry.azurecr.io/oras:v1.2.0", GetOrasImage())
+}
+
+func TestGetOrasCommands(t *testing.T) {
+	t.Setenv(consts.PlainHTTPRegistriesEnvVar, "localhost:5000, registry.kube-system.svc:5000")
+
+	testcases := map[string]struct {
+		command  string
+		expected string
+	}{
+		"Push with credentials": {
+			command:  GetOrasPushCommand("myregistry.azurecr.io/adapter:0.0.1", []string{"adapter_config.json", "adapter_model.safetensors"}, true),
+			expected: "oras push myregistry.azurecr.io/adapter:0.0.1 --artifact-type application/vnd.kaito.adapter.v1 --registry-config /tmp/.docker/config/config.json adapter_config.json adapter_model.safetensors",
+		},
+		"Push to a plain HTTP registry": {
+			command:  GetOrasPushCommand("localhost:5000/adapter:0.0.1", []string{"adapter_config.json"}, false),
+			expected: "oras push localhost:5000/adapter:0.0.1 --artifact-type application/vnd.kaito.adapter.v1 --plain-http adapter_config.json",
+		},
+		"Pull from a plain HTTP registry with credentials": {
+			command:  GetOrasPullCommand("registry.kube-system.svc:5000/adapter:0.0.1", "/mnt/adapter/adapter", true),
+			expected: "oras pull registry.kube-system.svc:5000/adapter:0.0.1 --output /mnt/adapter/adapter --registry-config /tmp/.docker/config/config.json --plain-http",
+		},
+		"Pull without credentials": {
+			command:  GetOrasPullCommand("myregistry.azurecr.io/adapter:0.0.1", "/mnt/adapter/adapter", false),
+			expected: "oras pull myregistry.azurecr.io/adapter:0.0.1 --output /mnt/adapter/adapter",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.command)
+		})
+	}
+}
@@ -3,6 +3,8 @@
 package utils
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -11,6 +13,7 @@ const (
 	DefaultConfigMapMountPath = "/mnt/config"
 	DefaultDataVolumePath     = "/mnt/data"
 	DefaultAdapterVolumePath  = "/mnt/adapter"
+	DefaultWeightsVolumePath  = "/workspace/weights"
 )
 
 func ConfigResultsVolume(outputPath string) (corev1.Volume, corev1.VolumeMount) {
@@ -149,3 +152,27 @@ func ConfigAdapterVolume() (corev1.Volume, corev1.VolumeMount) {
 	}
 	return volume, volumeMount
 }
+
+// ConfigAdapterPullSecretVolume returns the volume of the secret used to pull the adapter artifact at the index of
+// the adapters of the workspace. It is mounted at the same path as the image push secret.
+func ConfigAdapterPullSecretVolume(index int, imagePullSecret string) (corev1.Volume, corev1.VolumeMount) {
+	volume, volumeMount := ConfigImagePushSecretVolume(imagePullSecret)
+	volume.Name = fmt.Sprintf("adapter-%d-docker-config", index)
+	volumeMount.Name = volume.Name
+	return volume, volumeMount
+}
+
+// ConfigModelWeightsVolume returns the volume that the weights of models without a model image are downloaded to.
+func ConfigModelWeightsVolume() (corev1.Volume, corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: "model-weights",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      volume.Name,
+		MountPath: DefaultWeightsVolumePath,
+	}
+	return volume, volumeMount
+}
@@ -68,10 +68,18 @@ func MergeConfigMaps(baseMap, overrideMap map[string]string) map[string]string {
 	return merged
 }
 
+// BuildCmdStr appends the parameters to the base command. The parameters of each map are sorted by name
+// so that the same parameters always render the same command.
 func BuildCmdStr(baseCommand string, runParams ...map[string]string) string {
 	updatedBaseCommand := baseCommand
 	for _, runParam := range runParams {
-		for key, value := range runParam {
+		keys := make([]string, 0, len(runParam))
+		for key := range runParam {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := runParam[key]
 			if value == "" {
 				updatedBaseCommand = fmt.Sprintf("%s --%s", updatedBaseCommand, key)
 			} else {
@@ -91,6 +99,16 @@ func ShellCmd(command string) []string {
 	}
 }
 
+// GetServiceHost returns the DNS name of the service, <name>.<namespace>.svc, followed by the cluster domain if it is
+// configured. The name without the domain resolves in any cluster through the search domains of the pods.
+func GetServiceHost(name, namespace string) string {
+	host := fmt.Sprintf("%s.%s.svc", name, namespace)
+	if domain := strings.Trim(os.Getenv(consts.ClusterDomainEnvVar), "."); domain != "" {
+		host = host + "." + domain
+	}
+	return host
+}
+
 func GetReleaseNamespace() (string, error) {
 	// Path to the namespace file inside a Kubernetes pod
 	namespaceFilePath := "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
@@ -123,6 +141,12 @@ func GetSKUHandler() (sku.CloudSKUHandler, error) {
 	return skuHandler, nil
 }
 
+// IsNodeProvisioningEnabled returns whether nodes are provisioned for the workloads. On-prem and bring-your-own
+// clusters run the workloads on their existing nodes only.
+func IsNodeProvisioningEnabled() bool {
+	return os.Getenv("CLOUD_PROVIDER") != consts.OnPremCloudName
+}
+
 func GetSKUNumGPUs(ctx context.Context, kubeClient client.Client, workerNodes []string, instanceType, defaultGPUCount string) (string, error) {
 	skuHandler, err := GetSKUHandler()
 	if err != nil {
@@ -146,6 +170,28 @@ func GetSKUNumGPUs(ctx context.Context, kubeClient client.Client, workerNodes []
 	return skuNumGPUs, nil
 }
 
+// GetSKUGPUVendor returns the vendor of the GPUs of the instance type, or of the GPUs of the worker nodes if the
+// instance type is unknown. It defaults to NVIDIA.
+func GetSKUGPUVendor(ctx context.Context, kubeClient client.Client, workerNodes []string, instanceType string) string {
+	if skuHandler, err := GetSKUHandler(); err == nil {
+		if skuConfig, exists := skuHandler.GetGPUConfigs()[instanceType]; exists {
+			return skuConfig.GPUVendor()
+		}
+	}
+	for _, nodeName := range workerNodes {
+		node := &v1.Node{}
+		if err := kubeClient.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+			continue
+		}
+		for _, vendor := range sku.SupportedGPUVendors() {
+			if gpuCount, exists := node.Status.Capacity[sku.GetGPUVendorConfig(vendor).ResourceName]; exists && !gpuCount.IsZero() {
+				return vendor
+			}
+		}
+	}
+	return sku.GPUVendorNvidia
+}
+
 // FetchGPUCountFromNodes retrieves the GPU count from the given node names.
 func FetchGPUCountFromNodes(ctx context.Context, kubeClient client.Client, nodeNames []string) (string, error) {
 	if len(nodeNames) == 0 {
@@ -171,9 +217,11 @@ func FetchGPUCountFromNodes(ctx context.Context, kubeClient client.Client, nodeN
 
 func GetPerNodeGPUCountFromNodes(nodeList *v1.NodeList) string {
 	for _, node := range nodeList.Items {
-		gpuCount, exists := node.Status.Capacity[consts.NvidiaGPU]
-		if exists && gpuCount.String() != "" {
-			return gpuCount.String()
+		for _, vendor := range sku.SupportedGPUVendors() {
+			gpuCount, exists := node.Status.Capacity[sku.GetGPUVendorConfig(vendor).ResourceName]
+			if exists && gpuCount.String() != "" {
+				return gpuCount.String()
+			}
 		}
 	}
 	return ""
@@ -241,3 +289,32 @@ func SelectNodes(qualified []*v1.Node, preferred []string, previous []string, co
 
 	return qualified[0:count]
 }
+
+// SelectNodesToRelease splits the nodes into the ones to keep and the ones to release so that count nodes remain.
+// Nodes are released in a deterministic order: nodes that are not in the preferred list go first, then the most
+// recently created nodes, and finally the node with the larger name.
+func SelectNodesToRelease(nodes []*v1.Node, preferred []string, count int) (keep []*v1.Node, release []*v1.Node) {
+	sorted := make([]*v1.Node, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iPreferred := Contains(preferred, sorted[i].Name)
+		jPreferred := Contains(preferred, sorted[j].Name)
+		if iPreferred != jPreferred {
+			return !iPreferred
+		}
+		iCreated, jCreated := sorted[i].CreationTimestamp, sorted[j].CreationTimestamp
+		if !iCreated.Equal(&jCreated) {
+			return jCreated.Before(&iCreated)
+		}
+		return sorted[i].Name > sorted[j].Name
+	})
+
+	if count < 0 {
+		count = 0
+	}
+	if len(sorted) <= count {
+		return sorted, nil
+	}
+	releaseCount := len(sorted) - count
+	return sorted[releaseCount:], sorted[:releaseCount]
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -117,3 +118,68 @@ func TestFetchGPUCountFromNodes(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectNodesToRelease(t *testing.T) {
+	now := time.Now()
+	newNode := func(name string, age time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+	}
+	names := func(nodes []*corev1.Node) []string {
+		var result []string
+		for _, n := range nodes {
+			result = append(result, n.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name            string
+		nodes           []*corev1.Node
+		preferred       []string
+		count           int
+		expectedKeep    []string
+		expectedRelease []string
+	}{
+		{
+			name:         "Nothing to release",
+			nodes:        []*corev1.Node{newNode("node-1", time.Hour), newNode("node-2", time.Minute)},
+			count:        2,
+			expectedKeep: []string{"node-2", "node-1"},
+		},
+		{
+			name:            "Release the most recently created nodes",
+			nodes:           []*corev1.Node{newNode("node-1", time.Hour), newNode("node-2", time.Minute), newNode("node-3", 2*time.Hour)},
+			count:           1,
+			expectedKeep:    []string{"node-3"},
+			expectedRelease: []string{"node-2", "node-1"},
+		},
+		{
+			name:            "Release non-preferred nodes first",
+			nodes:           []*corev1.Node{newNode("node-1", time.Hour), newNode("node-2", time.Minute), newNode("node-3", 2*time.Hour)},
+			preferred:       []string{"node-2"},
+			count:           1,
+			expectedKeep:    []string{"node-2"},
+			expectedRelease: []string{"node-1", "node-3"},
+		},
+		{
+			name:            "Node name breaks the tie",
+			nodes:           []*corev1.Node{newNode("node-a", time.Hour), newNode("node-b", time.Hour)},
+			count:           1,
+			expectedKeep:    []string{"node-a"},
+			expectedRelease: []string{"node-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, release := SelectNodesToRelease(tt.nodes, tt.preferred, tt.count)
+			assert.Equal(t, tt.expectedKeep, names(keep))
+			assert.Equal(t, tt.expectedRelease, names(release))
+		})
+	}
+}
@@ -9,19 +9,27 @@ const (
 	// WorkspaceFinalizer is used to make sure that workspace controller handles garbage collection.
 	WorkspaceFinalizer = "workspace.finalizer.kaito.sh"
 	// RAGEngineFinalizer is used to make sure that ragengine controller handles garbage collection.
-	RAGEngineFinalizer            = "ragengine.finalizer.kaito.sh"
+	RAGEngineFinalizer = "ragengine.finalizer.kaito.sh"
+	// ModelPresetFinalizer is used to keep a model preset registered while workspaces still use it.
+	ModelPresetFinalizer          = "modelpreset.finalizer.kaito.sh"
 	DefaultReleaseNamespaceEnvVar = "RELEASE_NAMESPACE"
 	AzureCloudName                = "azure"
 	AWSCloudName                  = "aws"
-	GPUString                     = "gpu"
-	SKUString                     = "sku"
-	MaxRevisionHistoryLimit       = 10
-	GiBToBytes                    = 1024 * 1024 * 1024 // Conversion factor from GiB to bytes
-	NvidiaGPU                     = "nvidia.com/gpu"
+	// OnPremCloudName is the cloud provider of on-prem and bring-your-own clusters, whose workspaces run on the
+	// existing GPU nodes without provisioning nodes.
+	OnPremCloudName         = "onprem"
+	GPUString               = "gpu"
+	SKUString               = "sku"
+	MaxRevisionHistoryLimit = 10
+	GiBToBytes              = 1024 * 1024 * 1024 // Conversion factor from GiB to bytes
+	NvidiaGPU               = "nvidia.com/gpu"
+	// SKUCatalogConfigMapName is the ConfigMap in the release namespace that defines SKUs in addition to the built-in SKUs.
+	SKUCatalogConfigMapName = "kaito-sku-catalog"
 
 	// Feature flags
 	FeatureFlagKarpenter = "Karpenter"
 	FeatureFlagVLLM      = "vLLM"
+	FeatureFlagAMDGPU    = "AMDGPU"
 
 	// Nodeclaim related consts
 	KaitoNodePoolName             = "kaito"
@@ -38,4 +46,16 @@ const (
 	GpuSkuPrefix = "Standard_N"
 
 	NodePluginInstallTimeout = 60 * time.Second
+
+	// ActivatorImageEnvVar is the environment variable of the image running the activator of idle workspaces.
+	ActivatorImageEnvVar = "ACTIVATOR_IMAGE"
+	// OrasImageEnvVar is the environment variable of the image running the oras CLI, which pushes and pulls the
+	// adapters stored as OCI artifacts.
+	OrasImageEnvVar = "ORAS_IMAGE"
+	// PlainHTTPRegistriesEnvVar is the environment variable of the comma-separated registries, e.g., a local registry
+	// used for testing, that OCI artifacts are pushed to and pulled from over plain HTTP.
+	PlainHTTPRegistriesEnvVar = "PLAIN_HTTP_REGISTRIES"
+	// ClusterDomainEnvVar is the environment variable of the DNS domain of the cluster, e.g., cluster.local. The
+	// service endpoints are reported without the domain if it is not set.
+	ClusterDomainEnvVar = "CLUSTER_DOMAIN"
 )
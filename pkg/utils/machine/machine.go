@@ -172,6 +172,61 @@ func WaitForPendingMachines(ctx context.Context, obj interface{}, kubeClient cli
 	return nil
 }
 
+// GetPendingMachines returns the machines with the requested instance type that are created by the given workspace
+// or RAGEngine and are still being provisioned. Unlike WaitForPendingMachines, it does not wait for them to be ready.
+// An error is returned if any of the pending machines cannot be launched because the instance type is unavailable.
+func GetPendingMachines(ctx context.Context, obj interface{}, kubeClient client.Client) ([]*v1alpha5.Machine, error) {
+	instanceType, _, _, _, _, _, err := resources.ExtractObjFields(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := ListMachines(ctx, obj, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*v1alpha5.Machine
+	for i := range machines.Items {
+		machineObj := &machines.Items[i]
+		// check if the machine being created has the requested instance type
+		_, machineInstanceType := lo.Find(machineObj.Spec.Requirements, func(requirement v1.NodeSelectorRequirement) bool {
+			return requirement.Key == v1.LabelInstanceTypeStable &&
+				requirement.Operator == v1.NodeSelectorOpIn &&
+				lo.Contains(requirement.Values, instanceType)
+		})
+		if !machineInstanceType || IsMachineReady(machineObj) {
+			continue
+		}
+		if IsMachineInstanceTypeUnavailable(machineObj) {
+			klog.Error(consts.ErrorInstanceTypesUnavailable, "machine", machineObj.Name)
+			return nil, fmt.Errorf(consts.ErrorInstanceTypesUnavailable)
+		}
+		if machineObj.DeletionTimestamp.IsZero() {
+			pending = append(pending, machineObj)
+		}
+	}
+	return pending, nil
+}
+
+// IsMachineReady returns true if the machine has the Ready condition set to true.
+func IsMachineReady(machineObj *v1alpha5.Machine) bool {
+	_, conditionFound := lo.Find(machineObj.GetConditions(), func(condition apis.Condition) bool {
+		return condition.Type == apis.ConditionReady &&
+			condition.Status == v1.ConditionTrue
+	})
+	return conditionFound
+}
+
+// IsMachineInstanceTypeUnavailable returns true if the machine failed to launch because the SKU is not available.
+func IsMachineInstanceTypeUnavailable(machineObj *v1alpha5.Machine) bool {
+	_, conditionFound := lo.Find(machineObj.GetConditions(), func(condition apis.Condition) bool {
+		return condition.Type == v1alpha5.MachineLaunched &&
+			condition.Status == v1.ConditionFalse && condition.Message == consts.ErrorInstanceTypesUnavailable
+	})
+	return conditionFound
+}
+
 // ListMachines lists all machine objects in the cluster that are created by the given workspace or RAGEngine.
 func ListMachines(ctx context.Context, obj interface{}, kubeClient client.Client) (*v1alpha5.MachineList, error) {
 	machineList := &v1alpha5.MachineList{}
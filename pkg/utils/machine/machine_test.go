@@ -165,6 +165,79 @@ func TestWaitForPendingMachines(t *testing.T) {
 	}
 }
 
+func TestGetPendingMachines(t *testing.T) {
+	testcases := map[string]struct {
+		callMocks     func(c *test.MockClient)
+		conditions    apis.Conditions
+		expectedCount int
+		expectedError error
+	}{
+		"Fail to list machines": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(errors.New("failed to retrieve machines"))
+			},
+			expectedError: errors.New("failed to retrieve machines"),
+		},
+		"A machine is still being initialized": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			},
+			conditions: apis.Conditions{
+				{
+					Type:   v1alpha5.MachineInitialized,
+					Status: corev1.ConditionFalse,
+				},
+			},
+			expectedCount: 1,
+		},
+		"A machine cannot be launched because SKU is not available": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			},
+			conditions: apis.Conditions{
+				{
+					Type:    v1alpha5.MachineLaunched,
+					Status:  corev1.ConditionFalse,
+					Message: consts.ErrorInstanceTypesUnavailable,
+				},
+			},
+			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
+		},
+		"All machines are ready": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			},
+			conditions: apis.Conditions{
+				{
+					Type:   apis.ConditionReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+			expectedCount: 0,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			relevantMap := mockClient.CreateMapWithType(test.MockMachineList)
+			obj := test.MockMachine.DeepCopy()
+			obj.Status.Conditions = tc.conditions
+			relevantMap[client.ObjectKeyFromObject(obj)] = obj
+
+			pending, err := GetPendingMachines(context.Background(), test.MockWorkspaceWithPreset, mockClient)
+			if tc.expectedError == nil {
+				assert.Check(t, err == nil, "Not expected to return error")
+				assert.Equal(t, tc.expectedCount, len(pending))
+			} else {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			}
+		})
+	}
+}
+
 func TestGenerateMachineManifest(t *testing.T) {
 	t.Run("Should generate a machine object from the given workspace", func(t *testing.T) {
 		mockWorkspace := test.MockWorkspaceWithPreset
@@ -289,6 +289,61 @@ func WaitForPendingNodeClaims(ctx context.Context, obj interface{}, kubeClient c
 	return nil
 }
 
+// GetPendingNodeClaims returns the nodeClaims with the requested instance type that are created by the given workspace
+// or RAGEngine and are still being provisioned. Unlike WaitForPendingNodeClaims, it does not wait for them to be ready.
+// An error is returned if any of the pending nodeClaims cannot be launched because the instance type is unavailable.
+func GetPendingNodeClaims(ctx context.Context, obj interface{}, kubeClient client.Client) ([]*v1beta1.NodeClaim, error) {
+	instanceType, _, _, _, _, _, err := resources.ExtractObjFields(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeClaims, err := ListNodeClaim(ctx, obj, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*v1beta1.NodeClaim
+	for i := range nodeClaims.Items {
+		nodeClaim := &nodeClaims.Items[i]
+		// check if the nodeClaim being created has the requested instance type
+		_, nodeClaimInstanceType := lo.Find(nodeClaim.Spec.Requirements, func(requirement v1beta1.NodeSelectorRequirementWithMinValues) bool {
+			return requirement.Key == v1.LabelInstanceTypeStable &&
+				requirement.Operator == v1.NodeSelectorOpIn &&
+				lo.Contains(requirement.Values, instanceType)
+		})
+		if !nodeClaimInstanceType || IsNodeClaimReady(nodeClaim) {
+			continue
+		}
+		if IsNodeClaimInstanceTypeUnavailable(nodeClaim) {
+			klog.Error(consts.ErrorInstanceTypesUnavailable, "nodeClaim", nodeClaim.Name)
+			return nil, fmt.Errorf(consts.ErrorInstanceTypesUnavailable)
+		}
+		if nodeClaim.DeletionTimestamp.IsZero() {
+			pending = append(pending, nodeClaim)
+		}
+	}
+	return pending, nil
+}
+
+// IsNodeClaimReady returns true if the nodeClaim has the Ready condition set to true.
+func IsNodeClaimReady(nodeClaimObj *v1beta1.NodeClaim) bool {
+	_, conditionFound := lo.Find(nodeClaimObj.GetConditions(), func(condition apis.Condition) bool {
+		return condition.Type == apis.ConditionReady &&
+			condition.Status == v1.ConditionTrue
+	})
+	return conditionFound
+}
+
+// IsNodeClaimInstanceTypeUnavailable returns true if the nodeClaim failed to launch because the SKU is not available.
+func IsNodeClaimInstanceTypeUnavailable(nodeClaimObj *v1beta1.NodeClaim) bool {
+	_, conditionFound := lo.Find(nodeClaimObj.GetConditions(), func(condition apis.Condition) bool {
+		return condition.Type == v1beta1.Launched &&
+			condition.Status == v1.ConditionFalse && condition.Message == consts.ErrorInstanceTypesUnavailable
+	})
+	return conditionFound
+}
+
 // ListNodeClaim lists all nodeClaim objects in the cluster that are created by the given workspace or RAGEngine.
 func ListNodeClaim(ctx context.Context, obj interface{}, kubeClient client.Client) (*v1beta1.NodeClaimList, error) {
 	nodeClaimList := &v1beta1.NodeClaimList{}
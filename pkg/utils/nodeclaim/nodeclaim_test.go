@@ -175,6 +175,79 @@ func TestWaitForPendingNodeClaims(t *testing.T) {
 	}
 }
 
+func TestGetPendingNodeClaims(t *testing.T) {
+	testcases := map[string]struct {
+		callMocks     func(c *test.MockClient)
+		conditions    apis.Conditions
+		expectedCount int
+		expectedError error
+	}{
+		"Fail to list nodeClaims": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(errors.New("failed to retrieve nodeClaims"))
+			},
+			expectedError: errors.New("failed to retrieve nodeClaims"),
+		},
+		"A nodeClaim is still being initialized": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+			},
+			conditions: apis.Conditions{
+				{
+					Type:   v1beta1.Initialized,
+					Status: corev1.ConditionFalse,
+				},
+			},
+			expectedCount: 1,
+		},
+		"A nodeClaim cannot be launched because SKU is not available": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+			},
+			conditions: apis.Conditions{
+				{
+					Type:    v1beta1.Launched,
+					Status:  corev1.ConditionFalse,
+					Message: consts.ErrorInstanceTypesUnavailable,
+				},
+			},
+			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
+		},
+		"All nodeClaims are ready": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+			},
+			conditions: apis.Conditions{
+				{
+					Type:   apis.ConditionReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+			expectedCount: 0,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			relevantMap := mockClient.CreateMapWithType(test.MockNodeClaimList)
+			obj := test.MockNodeClaim.DeepCopy()
+			obj.Status.Conditions = tc.conditions
+			relevantMap[client.ObjectKeyFromObject(obj)] = obj
+
+			pending, err := GetPendingNodeClaims(context.Background(), test.MockWorkspaceWithPreset, mockClient)
+			if tc.expectedError == nil {
+				assert.Check(t, err == nil, "Not expected to return error")
+				assert.Equal(t, tc.expectedCount, len(pending))
+			} else {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			}
+		})
+	}
+}
+
 func TestGenerateNodeClaimManifest(t *testing.T) {
 	t.Run("Should generate a nodeClaim object from the given workspace when cloud provider set to azure", func(t *testing.T) {
 		mockWorkspace := test.MockWorkspaceWithPreset
@@ -11,6 +11,9 @@ import (
 type Registration struct {
 	Name     string
 	Instance model.Model
+	// Custom marks the models registered from ModelPresets, which, unlike the presets built into Kaito, can be
+	// replaced and removed.
+	Custom bool
 }
 
 type ModelRegister struct {
@@ -35,6 +38,23 @@ func (reg *ModelRegister) Register(r *Registration) {
 	reg.models[r.Name] = r
 }
 
+// Unregister removes the model with the given name, if any.
+func (reg *ModelRegister) Unregister(name string) {
+	reg.Lock()
+	defer reg.Unlock()
+	delete(reg.models, name)
+}
+
+// Get returns the model with the given name, or nil if it is not registered.
+func (reg *ModelRegister) Get(name string) model.Model {
+	reg.Lock()
+	defer reg.Unlock()
+	if r, ok := reg.models[name]; ok {
+		return r.Instance
+	}
+	return nil
+}
+
 func (reg *ModelRegister) MustGet(name string) model.Model {
 	reg.Lock()
 	defer reg.Unlock()
@@ -61,6 +81,14 @@ func (reg *ModelRegister) Has(name string) bool {
 	return ok
 }
 
+// IsBuiltIn returns whether the model with the given name is a preset built into Kaito.
+func (reg *ModelRegister) IsBuiltIn(name string) bool {
+	reg.Lock()
+	defer reg.Unlock()
+	r, ok := reg.models[name]
+	return ok && !r.Custom
+}
+
 func IsValidPreset(preset string) bool {
 	return KaitoModelRegister.Has(preset)
 }
@@ -90,11 +90,18 @@ func CheckNvidiaPlugin(ctx context.Context, nodeObj *corev1.Node) bool {
 	return false
 }
 
+// CheckGPUCapacity returns whether the device plugin advertises the GPUs of the extended resource on the node. The
+// device plugins of other vendors than NVIDIA are installed with their GPU operators rather than by Kaito.
+func CheckGPUCapacity(nodeObj *corev1.Node, resourceName corev1.ResourceName) bool {
+	capacity, found := nodeObj.Status.Capacity[resourceName]
+	return found && !capacity.IsZero()
+}
+
 func ExtractObjFields(obj interface{}) (instanceType, namespace, name string, labelSelector *metav1.LabelSelector,
 	nameLabel, namespaceLabel string, err error) {
 	switch o := obj.(type) {
 	case *kaitov1alpha1.Workspace:
-		instanceType = o.Resource.InstanceType
+		instanceType = kaitov1alpha1.GetWorkspaceInstanceType(o)
 		namespace = o.Namespace
 		name = o.Name
 		labelSelector = o.Resource.LabelSelector
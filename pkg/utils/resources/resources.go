@@ -4,17 +4,26 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// LastAppliedConfigAnnotation is the annotation that stores the desired state of a workload last applied by the controller.
+const LastAppliedConfigAnnotation = "kaito.sh/last-applied-configuration"
+
 func CreateResource(ctx context.Context, resource client.Object, kubeClient client.Client) error {
 	switch r := resource.(type) {
 	case *appsv1.Deployment:
@@ -68,37 +77,152 @@ func CheckResourceStatus(obj client.Object, kubeClient client.Client, timeoutDur
 				return err
 			}
 
-			switch k8sResource := obj.(type) {
-			case *appsv1.Deployment:
-				for _, condition := range k8sResource.Status.Conditions {
-					if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
-						errorMessage := fmt.Sprintf("deployment %s is not progressing: %s", k8sResource.Name, condition.Message)
-						klog.ErrorS(fmt.Errorf(errorMessage), "deployment", k8sResource.Name, "reason", condition.Reason, "message", condition.Message)
-						return fmt.Errorf(errorMessage)
-					}
-				}
-
-				if k8sResource.Status.ReadyReplicas == *k8sResource.Spec.Replicas {
-					klog.InfoS("deployment status is ready", "deployment", k8sResource.Name)
-					return nil
-				}
-			case *appsv1.StatefulSet:
-				if k8sResource.Status.ReadyReplicas == *k8sResource.Spec.Replicas {
-					klog.InfoS("statefulset status is ready", "statefulset", k8sResource.Name)
-					return nil
-				}
-			case *batchv1.Job:
-				if k8sResource.Status.Failed > 0 {
-					klog.ErrorS(fmt.Errorf("job failed"), "name", k8sResource.Name, "failed count", k8sResource.Status.Failed)
-					return fmt.Errorf("job %s has failed %d pods", k8sResource.Name, k8sResource.Status.Failed)
-				}
-				if k8sResource.Status.Succeeded > 0 || (k8sResource.Status.Ready != nil && *k8sResource.Status.Ready > 0) {
-					klog.InfoS("job status is active/succeeded", "name", k8sResource.Name)
-					return nil
-				}
-			default:
-				return fmt.Errorf("unsupported resource type")
+			ready, err := IsResourceReady(obj)
+			if err != nil {
+				return err
 			}
+			if ready {
+				return nil
+			}
+		}
+	}
+}
+
+// IsResourceReady checks the current status of the workload once without waiting.
+// It returns true if the workload is ready, and an error if the workload has failed
+// and will not become ready by itself.
+func IsResourceReady(obj client.Object) (bool, error) {
+	switch k8sResource := obj.(type) {
+	case *appsv1.Deployment:
+		for _, condition := range k8sResource.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
+				errorMessage := fmt.Sprintf("deployment %s is not progressing: %s", k8sResource.Name, condition.Message)
+				klog.ErrorS(fmt.Errorf(errorMessage), "deployment", k8sResource.Name, "reason", condition.Reason, "message", condition.Message)
+				return false, fmt.Errorf(errorMessage)
+			}
+		}
+
+		if k8sResource.Status.ObservedGeneration >= k8sResource.Generation &&
+			k8sResource.Status.ReadyReplicas == *k8sResource.Spec.Replicas {
+			klog.InfoS("deployment status is ready", "deployment", k8sResource.Name)
+			return true, nil
+		}
+	case *appsv1.StatefulSet:
+		if k8sResource.Status.ObservedGeneration >= k8sResource.Generation &&
+			k8sResource.Status.ReadyReplicas == *k8sResource.Spec.Replicas {
+			klog.InfoS("statefulset status is ready", "statefulset", k8sResource.Name)
+			return true, nil
 		}
+	case *batchv1.Job:
+		if k8sResource.Status.Failed > 0 {
+			klog.ErrorS(fmt.Errorf("job failed"), "name", k8sResource.Name, "failed count", k8sResource.Status.Failed)
+			return false, fmt.Errorf("job %s has failed %d pods", k8sResource.Name, k8sResource.Status.Failed)
+		}
+		if k8sResource.Status.Succeeded > 0 || (k8sResource.Status.Ready != nil && *k8sResource.Status.Ready > 0) {
+			klog.InfoS("job status is active/succeeded", "name", k8sResource.Name)
+			return true, nil
+		}
+	default:
+		return false, fmt.Errorf("unsupported resource type")
+	}
+	return false, nil
+}
+
+// SetLastAppliedConfiguration records the desired state of the object in the LastAppliedConfigAnnotation
+// so that it can be used as the original state of the next three-way update.
+func SetLastAppliedConfiguration(obj client.Object) error {
+	data, err := desiredStateJSON(obj)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastAppliedConfigAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// CreateThreeWayPatch computes the strategic merge patch that brings the current object to the desired one.
+// Like `kubectl apply`, fields removed from the desired state since the last applied configuration are deleted,
+// fields set by the desired state are overwritten, and fields owned by others, e.g. defaulted by the API server,
+// are left untouched. The returned patch is nil if the current object has not drifted from the desired state.
+// The desired object is expected to have been passed to SetLastAppliedConfiguration.
+func CreateThreeWayPatch(current, desired client.Object) (client.Patch, error) {
+	var schema runtime.Object
+	switch desired.(type) {
+	case *appsv1.Deployment:
+		schema = &appsv1.Deployment{}
+	case *appsv1.StatefulSet:
+		schema = &appsv1.StatefulSet{}
+	case *corev1.Service:
+		schema = &corev1.Service{}
+	case *corev1.ServiceAccount:
+		schema = &corev1.ServiceAccount{}
+	case *rbacv1.Role:
+		schema = &rbacv1.Role{}
+	case *rbacv1.RoleBinding:
+		schema = &rbacv1.RoleBinding{}
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %T", desired)
+	}
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	original := []byte(current.GetAnnotations()[LastAppliedConfigAnnotation])
+	modified, err := desiredStateJSON(desired)
+	if err != nil {
+		return nil, err
+	}
+	if modified, err = addLastAppliedAnnotation(modified, desired.GetAnnotations()[LastAppliedConfigAnnotation]); err != nil {
+		return nil, err
+	}
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, currentJSON, patchMeta, true)
+	if err != nil {
+		return nil, err
+	}
+	if string(patch) == "{}" {
+		return nil, nil
+	}
+	return client.RawPatch(types.StrategicMergePatchType, patch), nil
+}
+
+// desiredStateJSON serializes the fields of the object that are managed by the controller. The server-populated
+// fields and the last applied configuration itself are excluded.
+func desiredStateJSON(obj client.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(content, "metadata", "annotations", LastAppliedConfigAnnotation)
+	unstructured.RemoveNestedField(content, "spec", "template", "metadata", "creationTimestamp")
+	if annotations, found, _ := unstructured.NestedMap(content, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(content, "metadata", "annotations")
+	}
+	return json.Marshal(content)
+}
+
+func addLastAppliedAnnotation(data []byte, lastApplied string) ([]byte, error) {
+	if lastApplied == "" {
+		return data, nil
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(content, lastApplied, "metadata", "annotations", LastAppliedConfigAnnotation); err != nil {
+		return nil, err
 	}
+	return json.Marshal(content)
 }
@@ -4,6 +4,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -13,7 +14,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -188,6 +191,79 @@ func TestCheckResourceStatus(t *testing.T) {
 	})
 }
 
+func TestIsResourceReady(t *testing.T) {
+	readyCount := int32(1)
+	testcases := map[string]struct {
+		obj           client.Object
+		expectedReady bool
+		expectedError bool
+	}{
+		"Deployment with all replicas ready": {
+			obj: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+			},
+			expectedReady: true,
+		},
+		"Deployment still rolling out": {
+			obj: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+			},
+			expectedReady: false,
+		},
+		"Deployment not progressing": {
+			obj: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+				Status: appsv1.DeploymentStatus{
+					Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		"Deployment update not observed yet": {
+			obj: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, ReadyReplicas: 1},
+			},
+			expectedReady: false,
+		},
+		"StatefulSet not ready": {
+			obj: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: int32Ptr(1)},
+			},
+			expectedReady: false,
+		},
+		"Job with ready pods": {
+			obj:           &batchv1.Job{Status: batchv1.JobStatus{Ready: &readyCount}},
+			expectedReady: true,
+		},
+		"Job with failed pods": {
+			obj:           &batchv1.Job{Status: batchv1.JobStatus{Failed: 1}},
+			expectedError: true,
+		},
+		"Unsupported resource type": {
+			obj:           &appsv1.DaemonSet{},
+			expectedError: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			ready, err := IsResourceReady(tc.obj)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tc.expectedReady, ready)
+		})
+	}
+}
+
 func TestCreateResource(t *testing.T) {
 	testcases := map[string]struct {
 		callMocks        func(c *test.MockClient)
@@ -265,3 +341,120 @@ func TestGetResource(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateThreeWayPatch(t *testing.T) {
+	newDesired := func(image string, env []corev1.EnvVar) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(1),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:    "test",
+							Image:   image,
+							Command: []string{"/bin/sh", "-c", "serve"},
+							Env:     env,
+						}},
+					},
+				},
+			},
+		}
+	}
+	// applyOnServer mimics the API server: the desired state is stored with defaults and server-populated fields.
+	applyOnServer := func(desired *appsv1.Deployment) *appsv1.Deployment {
+		current := desired.DeepCopy()
+		current.ResourceVersion = "1"
+		current.Generation = 1
+		current.CreationTimestamp = metav1.Now()
+		current.Annotations["deployment.kubernetes.io/revision"] = "1"
+		current.Spec.RevisionHistoryLimit = int32Ptr(10)
+		current.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+		current.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+		current.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+		current.Spec.Template.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+		current.Status.ReadyReplicas = 1
+		return current
+	}
+	applyPatch := func(t *testing.T, current *appsv1.Deployment, patch client.Patch) *appsv1.Deployment {
+		currentJSON, err := json.Marshal(current)
+		assert.NoError(t, err)
+		data, err := patch.Data(current)
+		assert.NoError(t, err)
+		patched, err := strategicpatch.StrategicMergePatch(currentJSON, data, &appsv1.Deployment{})
+		assert.NoError(t, err)
+		result := &appsv1.Deployment{}
+		assert.NoError(t, json.Unmarshal(patched, result))
+		return result
+	}
+
+	env := []corev1.EnvVar{{Name: "ADAPTER", Value: "1.0"}}
+
+	t.Run("No drift", func(t *testing.T) {
+		desired := newDesired("image:0.0.1", env)
+		assert.NoError(t, SetLastAppliedConfiguration(desired))
+		current := applyOnServer(desired)
+
+		desired = newDesired("image:0.0.1", env)
+		assert.NoError(t, SetLastAppliedConfiguration(desired))
+		patch, err := CreateThreeWayPatch(current, desired)
+		assert.NoError(t, err)
+		assert.Nil(t, patch)
+	})
+
+	t.Run("Update image and remove env", func(t *testing.T) {
+		desired := newDesired("image:0.0.1", env)
+		assert.NoError(t, SetLastAppliedConfiguration(desired))
+		current := applyOnServer(desired)
+		current.Annotations["owner"] = "someone-else"
+
+		desired = newDesired("image:0.0.2", nil)
+		assert.NoError(t, SetLastAppliedConfiguration(desired))
+		patch, err := CreateThreeWayPatch(current, desired)
+		assert.NoError(t, err)
+		assert.NotNil(t, patch)
+
+		result := applyPatch(t, current, patch)
+		container := result.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "image:0.0.2", container.Image)
+		assert.Empty(t, container.Env)
+		assert.Equal(t, corev1.PullIfNotPresent, container.ImagePullPolicy)
+		assert.Equal(t, "someone-else", result.Annotations["owner"])
+		assert.Equal(t, desired.Annotations[LastAppliedConfigAnnotation], result.Annotations[LastAppliedConfigAnnotation])
+	})
+
+	t.Run("Revert manual drift", func(t *testing.T) {
+		desired := newDesired("image:0.0.1", env)
+		assert.NoError(t, SetLastAppliedConfiguration(desired))
+		current := applyOnServer(desired)
+		current.Spec.Template.Spec.Containers[0].Command = []string{"sleep", "infinity"}
+
+		patch, err := CreateThreeWayPatch(current, desired)
+		assert.NoError(t, err)
+		assert.NotNil(t, patch)
+		result := applyPatch(t, current, patch)
+		assert.Equal(t, []string{"/bin/sh", "-c", "serve"}, result.Spec.Template.Spec.Containers[0].Command)
+	})
+
+	t.Run("Workload created without last applied configuration", func(t *testing.T) {
+		legacy := newDesired("image:0.0.1", env)
+		legacy.Annotations = map[string]string{}
+		current := applyOnServer(legacy)
+
+		desired := newDesired("image:0.0.1", env)
+		assert.NoError(t, SetLastAppliedConfiguration(desired))
+		patch, err := CreateThreeWayPatch(current, desired)
+		assert.NoError(t, err)
+		assert.NotNil(t, patch)
+		result := applyPatch(t, current, patch)
+		assert.Equal(t, current.Spec.Template.Spec, result.Spec.Template.Spec)
+		assert.NotEmpty(t, result.Annotations[LastAppliedConfigAnnotation])
+	})
+
+	t.Run("Unsupported resource type", func(t *testing.T) {
+		_, err := CreateThreeWayPatch(&corev1.Pod{}, &corev1.Pod{})
+		assert.ErrorContains(t, err, "unsupported resource type")
+	})
+}
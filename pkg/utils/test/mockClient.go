@@ -8,6 +8,7 @@ import (
 	"reflect"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -23,17 +24,19 @@ import (
 type MockClient struct {
 	mock.Mock
 
-	ObjectMap  map[reflect.Type]map[k8sClient.ObjectKey]k8sClient.Object
-	StatusMock *MockStatusClient
-	UpdateCb   func(key types.NamespacedName)
+	ObjectMap       map[reflect.Type]map[k8sClient.ObjectKey]k8sClient.Object
+	StatusMock      *MockStatusClient
+	SubResourceMock *MockSubResourceClient
+	UpdateCb        func(key types.NamespacedName)
 }
 
 var _ k8sClient.Client = &MockClient{}
 
 func NewClient() *MockClient {
 	return &MockClient{
-		StatusMock: &MockStatusClient{},
-		ObjectMap:  map[reflect.Type]map[k8sClient.ObjectKey]k8sClient.Object{},
+		StatusMock:      &MockStatusClient{},
+		SubResourceMock: &MockSubResourceClient{},
+		ObjectMap:       map[reflect.Type]map[k8sClient.ObjectKey]k8sClient.Object{},
 	}
 }
 
@@ -129,6 +132,38 @@ func (m *MockClient) getObjectListFromMap(list k8sClient.ObjectList) k8sClient.O
 			}
 		}
 		return controllerRevisionList
+	case *corev1.PodList:
+		podList := &corev1.PodList{}
+		for _, obj := range relevantMap {
+			if p, ok := obj.(*corev1.Pod); ok {
+				podList.Items = append(podList.Items, *p)
+			}
+		}
+		return podList
+	case *v1alpha1.WorkspaceList:
+		workspaceList := &v1alpha1.WorkspaceList{}
+		for _, obj := range relevantMap {
+			if w, ok := obj.(*v1alpha1.Workspace); ok {
+				workspaceList.Items = append(workspaceList.Items, *w)
+			}
+		}
+		return workspaceList
+	case *v1alpha1.RAGEngineList:
+		ragEngineList := &v1alpha1.RAGEngineList{}
+		for _, obj := range relevantMap {
+			if r, ok := obj.(*v1alpha1.RAGEngine); ok {
+				ragEngineList.Items = append(ragEngineList.Items, *r)
+			}
+		}
+		return ragEngineList
+	case *v1alpha1.GPUQuotaList:
+		gpuQuotaList := &v1alpha1.GPUQuotaList{}
+		for _, obj := range relevantMap {
+			if q, ok := obj.(*v1alpha1.GPUQuota); ok {
+				gpuQuotaList.Items = append(gpuQuotaList.Items, *q)
+			}
+		}
+		return gpuQuotaList
 	}
 	//add additional object lists as needed
 	return nil
@@ -163,7 +198,7 @@ func (m *MockClient) DeleteAllOf(ctx context.Context, obj k8sClient.Object, opts
 
 // SubResource implements client.Client
 func (m *MockClient) SubResource(subResource string) k8sClient.SubResourceClient {
-	panic("unimplemented")
+	return m.SubResourceMock
 }
 
 // GroupVersionKindFor implements client.Client
@@ -212,3 +247,30 @@ func (m *MockStatusClient) Update(ctx context.Context, obj k8sClient.Object, opt
 }
 
 var _ k8sClient.StatusWriter = &MockStatusClient{}
+
+// MockSubResourceClient is a mock for the clients of subresources other than status, e.g., the eviction of pods.
+type MockSubResourceClient struct {
+	mock.Mock
+}
+
+func (m *MockSubResourceClient) Get(ctx context.Context, obj k8sClient.Object, subResource k8sClient.Object, opts ...k8sClient.SubResourceGetOption) error {
+	args := m.Called(ctx, obj, subResource, opts)
+	return args.Error(0)
+}
+
+func (m *MockSubResourceClient) Create(ctx context.Context, obj k8sClient.Object, subResource k8sClient.Object, opts ...k8sClient.SubResourceCreateOption) error {
+	args := m.Called(ctx, obj, subResource, opts)
+	return args.Error(0)
+}
+
+func (m *MockSubResourceClient) Patch(ctx context.Context, obj k8sClient.Object, patch k8sClient.Patch, opts ...k8sClient.SubResourcePatchOption) error {
+	args := m.Called(ctx, obj, patch, opts)
+	return args.Error(0)
+}
+
+func (m *MockSubResourceClient) Update(ctx context.Context, obj k8sClient.Object, opts ...k8sClient.SubResourceUpdateOption) error {
+	args := m.Called(ctx, obj, opts)
+	return args.Error(0)
+}
+
+var _ k8sClient.SubResourceClient = &MockSubResourceClient{}
@@ -24,8 +24,16 @@ func (*baseTestModel) GetInferenceParameters() *model.PresetParam {
 				BaseCommand:       "accelerate launch",
 				InferenceMainFile: "/workspace/tfs/inference_api.py",
 			},
+			LlamaCpp: model.LlamaCppParam{
+				Image:       "ghcr.io/ggerganov/llama.cpp:server",
+				BaseCommand: "/app/llama-server",
+				ModelRepo:   "test/mymodel-gguf",
+				ModelFile:   "mymodel-q4.gguf",
+			},
 		},
-		ReadinessTimeout: time.Duration(30) * time.Minute,
+		CPURequirement:    "4",
+		MemoryRequirement: "8Gi",
+		ReadinessTimeout:  time.Duration(30) * time.Minute,
 	}
 }
 func (*baseTestModel) GetTuningParameters() *model.PresetParam {
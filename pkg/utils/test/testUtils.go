@@ -197,6 +197,32 @@ var (
 	}
 )
 
+var (
+	MockWorkspaceWithPresetLlamaCpp = &v1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testWorkspace",
+			Namespace: "kaito",
+		},
+		Resource: v1alpha1.ResourceSpec{
+			Count:        &gpuNodeCount,
+			InstanceType: "Standard_D8s_v5",
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"apps": "test",
+				},
+			},
+		},
+		Inference: &v1alpha1.InferenceSpec{
+			Preset: &v1alpha1.PresetSpec{
+				PresetMeta: v1alpha1.PresetMeta{
+					Name: "test-model",
+				},
+			},
+			Runtime: "llamacpp",
+		},
+	}
+)
+
 var MockWorkspaceWithPresetHash = "89ae127050ec264a5ce84db48ef7226574cdf1299e6bd27fe90b927e34cc8adb"
 
 var (
@@ -287,7 +313,7 @@ var (
 			Name:      "testWorkspace",
 			Namespace: "kaito",
 			Annotations: map[string]string{
-				"workspace.kaito.io/hash":     "1171dc5d15043c92e684c8f06689eb241763a735181fdd2b59c8bd8fd6eecdd4",
+				"workspace.kaito.io/hash":     "b2a72832f786e15535c55c08c0b90fd71ed71df305b8e72f5d944f1da6065381",
 				"workspace.kaito.io/revision": "1",
 			},
 		},
@@ -445,7 +471,7 @@ var (
 			Name:      "testWorkspace",
 			Namespace: "kaito",
 			Annotations: map[string]string{
-				"workspace.kaito.io/hash":     "1171dc5d15043c92e684c8f06689eb241763a735181fdd2b59c8bd8fd6eecdd4",
+				"workspace.kaito.io/hash":     "b2a72832f786e15535c55c08c0b90fd71ed71df305b8e72f5d944f1da6065381",
 				"workspace.kaito.io/revision": "1",
 			},
 		},
@@ -497,7 +523,7 @@ var (
 			Name:      "testWorkspace",
 			Namespace: "kaito",
 			Annotations: map[string]string{
-				"workspace.kaito.io/hash":     "1171dc5d15043c92e684c8f06689eb241763a735181fdd2b59c8bd8fd6eecdd4",
+				"workspace.kaito.io/hash":     "b2a72832f786e15535c55c08c0b90fd71ed71df305b8e72f5d944f1da6065381",
 				"workspace.kaito.io/revision": "1",
 			},
 		},
@@ -526,7 +552,7 @@ var (
 			Name:      "testWorkspace",
 			Namespace: "kaito",
 			Annotations: map[string]string{
-				"workspace.kaito.io/hash":     "1171dc5d15043c92e684c8f06689eb241763a735181fdd2b59c8bd8fd6eecdd4",
+				"workspace.kaito.io/hash":     "b2a72832f786e15535c55c08c0b90fd71ed71df305b8e72f5d944f1da6065381",
 				"workspace.kaito.io/revision": "1",
 			},
 		},
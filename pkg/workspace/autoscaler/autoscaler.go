@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/prometheus/common/expfmt"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+)
+
+const (
+	// MetricNumRequestsWaiting is the vLLM metric of the number of requests waiting to be processed.
+	MetricNumRequestsWaiting = "vllm:num_requests_waiting"
+	// MetricGPUCacheUsage is the vLLM metric of the fraction of GPU KV-cache blocks in use, between 0 and 1.
+	MetricGPUCacheUsage = "vllm:gpu_cache_usage_perc"
+
+	// tolerance is the maximum deviation of the metric from the target that does not trigger scaling.
+	tolerance = 0.1
+)
+
+// RuntimeMetrics are the metrics of one inference server that are used for autoscaling.
+type RuntimeMetrics struct {
+	// PendingRequests is the number of requests waiting to be processed.
+	PendingRequests float64
+	// KVCacheUsage is the fraction of GPU KV-cache blocks in use, between 0 and 1.
+	KVCacheUsage float64
+}
+
+// ParseRuntimeMetrics parses the metrics in Prometheus text format exposed by the vLLM server.
+// Samples of the same metric with different labels, e.g., model names, are summed up.
+func ParseRuntimeMetrics(r io.Reader) (*RuntimeMetrics, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	sum := func(name string) (float64, bool) {
+		family, ok := families[name]
+		if !ok {
+			return 0, false
+		}
+		var total float64
+		for _, m := range family.GetMetric() {
+			switch {
+			case m.GetGauge() != nil:
+				total += m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				total += m.GetCounter().GetValue()
+			case m.GetUntyped() != nil:
+				total += m.GetUntyped().GetValue()
+			}
+		}
+		return total, true
+	}
+
+	metrics := &RuntimeMetrics{}
+	var found bool
+	if metrics.PendingRequests, found = sum(MetricNumRequestsWaiting); !found {
+		return nil, fmt.Errorf("metric %s is not found", MetricNumRequestsWaiting)
+	}
+	if metrics.KVCacheUsage, found = sum(MetricGPUCacheUsage); !found {
+		return nil, fmt.Errorf("metric %s is not found", MetricGPUCacheUsage)
+	}
+	return metrics, nil
+}
+
+// ScrapeRuntimeMetrics fetches and parses the metrics from the given url.
+func ScrapeRuntimeMetrics(ctx context.Context, httpClient *http.Client, url string) (*RuntimeMetrics, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return ParseRuntimeMetrics(resp.Body)
+}
+
+// DesiredCount computes the count that brings the average metric per replica to the target value.
+// The metrics are collected from the ready replicas only. If no metrics are available, or the average
+// is within the tolerance of the target, the current count is kept. The result is always within the
+// [minCount, maxCount] range of the spec.
+func DesiredCount(spec *kaitov1alpha1.AutoscalingSpec, currentCount int, metrics []RuntimeMetrics) int {
+	minCount := spec.MinCount
+	if minCount < 1 {
+		minCount = 1
+	}
+	desired := currentCount
+	if len(metrics) > 0 && spec.TargetValue > 0 {
+		var total float64
+		for _, m := range metrics {
+			switch spec.Metric {
+			case kaitov1alpha1.AutoscalingMetricPendingRequests:
+				total += m.PendingRequests
+			case kaitov1alpha1.AutoscalingMetricKVCacheUsage:
+				total += m.KVCacheUsage * 100
+			}
+		}
+		ratio := total / float64(len(metrics)) / float64(spec.TargetValue)
+		if math.Abs(ratio-1) > tolerance {
+			desired = int(math.Ceil(ratio * float64(len(metrics))))
+		}
+	}
+
+	if desired < minCount {
+		desired = minCount
+	}
+	if desired > spec.MaxCount {
+		desired = spec.MaxCount
+	}
+	return desired
+}
@@ -0,0 +1,148 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+)
+
+const vllmMetrics = `# HELP vllm:num_requests_waiting Number of requests waiting to be processed.
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="falcon-7b"} 3.0
+vllm:num_requests_waiting{model_name="adapter-1"} 2.0
+# HELP vllm:gpu_cache_usage_perc GPU KV-cache usage. 1 means 100 percent usage.
+# TYPE vllm:gpu_cache_usage_perc gauge
+vllm:gpu_cache_usage_perc{model_name="falcon-7b"} 0.45
+`
+
+func TestParseRuntimeMetrics(t *testing.T) {
+	testcases := map[string]struct {
+		input         string
+		expected      *RuntimeMetrics
+		expectedError string
+	}{
+		"Parse vLLM metrics": {
+			input:    vllmMetrics,
+			expected: &RuntimeMetrics{PendingRequests: 5, KVCacheUsage: 0.45},
+		},
+		"Missing metric": {
+			input:         "# TYPE vllm:num_requests_waiting gauge\nvllm:num_requests_waiting 1\n",
+			expectedError: "metric vllm:gpu_cache_usage_perc is not found",
+		},
+		"Invalid format": {
+			input:         "vllm:num_requests_waiting{ 1\n",
+			expectedError: "failed to parse metrics",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			metrics, err := ParseRuntimeMetrics(strings.NewReader(tc.input))
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.PendingRequests, metrics.PendingRequests)
+			assert.InDelta(t, tc.expected.KVCacheUsage, metrics.KVCacheUsage, 1e-9)
+		})
+	}
+}
+
+func TestScrapeRuntimeMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, vllmMetrics)
+	}))
+	defer server.Close()
+
+	metrics, err := ScrapeRuntimeMetrics(context.Background(), server.Client(), server.URL+"/metrics")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), metrics.PendingRequests)
+
+	_, err = ScrapeRuntimeMetrics(context.Background(), server.Client(), server.URL+"/unknown")
+	assert.ErrorContains(t, err, "unexpected status code 404")
+}
+
+func TestDesiredCount(t *testing.T) {
+	pendingSpec := &kaitov1alpha1.AutoscalingSpec{
+		MinCount:    1,
+		MaxCount:    4,
+		Metric:      kaitov1alpha1.AutoscalingMetricPendingRequests,
+		TargetValue: 5,
+	}
+	cacheSpec := &kaitov1alpha1.AutoscalingSpec{
+		MinCount:    2,
+		MaxCount:    4,
+		Metric:      kaitov1alpha1.AutoscalingMetricKVCacheUsage,
+		TargetValue: 50,
+	}
+
+	testcases := map[string]struct {
+		spec         *kaitov1alpha1.AutoscalingSpec
+		currentCount int
+		metrics      []RuntimeMetrics
+		expected     int
+	}{
+		"No metrics keeps the current count": {
+			spec:         pendingSpec,
+			currentCount: 2,
+			expected:     2,
+		},
+		"Scale up on pending requests": {
+			spec:         pendingSpec,
+			currentCount: 1,
+			metrics:      []RuntimeMetrics{{PendingRequests: 12}},
+			expected:     3,
+		},
+		"Scale up is capped by maxCount": {
+			spec:         pendingSpec,
+			currentCount: 2,
+			metrics:      []RuntimeMetrics{{PendingRequests: 30}, {PendingRequests: 30}},
+			expected:     4,
+		},
+		"Scale down to minCount when idle": {
+			spec:         pendingSpec,
+			currentCount: 3,
+			metrics:      []RuntimeMetrics{{}, {}, {}},
+			expected:     1,
+		},
+		"Within tolerance keeps the current count": {
+			spec:         cacheSpec,
+			currentCount: 2,
+			metrics:      []RuntimeMetrics{{KVCacheUsage: 0.52}, {KVCacheUsage: 0.5}},
+			expected:     2,
+		},
+		"Scale up on KV-cache usage": {
+			spec:         cacheSpec,
+			currentCount: 2,
+			metrics:      []RuntimeMetrics{{KVCacheUsage: 0.9}, {KVCacheUsage: 0.7}},
+			expected:     4,
+		},
+		"Scale down is limited by minCount": {
+			spec:         cacheSpec,
+			currentCount: 3,
+			metrics:      []RuntimeMetrics{{KVCacheUsage: 0.1}, {KVCacheUsage: 0.1}, {KVCacheUsage: 0.1}},
+			expected:     2,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DesiredCount(tc.spec, tc.currentCount, tc.metrics))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// modelPresetInUseRequeueInterval is how often a deleted model preset checks whether workspaces still use it.
+const modelPresetInUseRequeueInterval = 30 * time.Second
+
+// ModelPresetReconciler registers the ModelPresets with the model register, so that workspaces can use them like
+// the built-in presets.
+type ModelPresetReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+func NewModelPresetReconciler(client client.Client, recorder record.EventRecorder) *ModelPresetReconciler {
+	return &ModelPresetReconciler{
+		Client:   client,
+		Recorder: recorder,
+	}
+}
+
+func (c *ModelPresetReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	mpObj := &kaitov1alpha1.ModelPreset{}
+	if err := c.Client.Get(ctx, req.NamespacedName, mpObj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "failed to get model preset", "modelpreset", req.Name)
+			return reconcile.Result{}, err
+		}
+		unregisterModelPreset(req.Name)
+		return reconcile.Result{}, nil
+	}
+
+	if !mpObj.DeletionTimestamp.IsZero() {
+		return c.deleteModelPreset(ctx, mpObj)
+	}
+
+	if !controllerutil.ContainsFinalizer(mpObj, consts.ModelPresetFinalizer) {
+		patch := client.MergeFrom(mpObj.DeepCopy())
+		controllerutil.AddFinalizer(mpObj, consts.ModelPresetFinalizer)
+		if err := c.Client.Patch(ctx, mpObj, patch); err != nil {
+			klog.ErrorS(err, "failed to ensure the finalizer to the model preset", "modelpreset", klog.KObj(mpObj))
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !registerModelPreset(mpObj) {
+		message := fmt.Sprintf("the preset %s is built into Kaito and cannot be replaced", mpObj.Name)
+		c.Recorder.Event(mpObj, corev1.EventTypeWarning, "NameConflict", message)
+		return reconcile.Result{}, c.updateStatusConditionIfNotMatch(ctx, mpObj, metav1.ConditionFalse, "NameConflict", message)
+	}
+	return reconcile.Result{}, c.updateStatusConditionIfNotMatch(ctx, mpObj, metav1.ConditionTrue,
+		"ModelPresetRegistered", "the preset is registered and can be used by workspaces")
+}
+
+// deleteModelPreset unregisters the model preset and removes its finalizer once no workspace uses it anymore.
+func (c *ModelPresetReconciler) deleteModelPreset(ctx context.Context, mpObj *kaitov1alpha1.ModelPreset) (reconcile.Result, error) {
+	users, err := c.listWorkspacesUsingPreset(ctx, mpObj.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(users) > 0 {
+		klog.InfoS("model preset is still used by workspaces", "modelpreset", klog.KObj(mpObj), "workspaces", users)
+		if err := c.updateStatusConditionIfNotMatch(ctx, mpObj, metav1.ConditionTrue, "ModelPresetInUse",
+			fmt.Sprintf("the preset remains registered until it is no longer used by workspaces %s", strings.Join(users, ", "))); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: modelPresetInUseRequeueInterval}, nil
+	}
+
+	unregisterModelPreset(mpObj.Name)
+	if controllerutil.RemoveFinalizer(mpObj, consts.ModelPresetFinalizer) {
+		if err := c.Client.Update(ctx, mpObj, &client.UpdateOptions{}); err != nil {
+			klog.ErrorS(err, "failed to remove the finalizer from the model preset", "modelpreset", klog.KObj(mpObj))
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	klog.InfoS("successfully unregistered the model preset", "modelpreset", klog.KObj(mpObj))
+	return reconcile.Result{}, nil
+}
+
+// listWorkspacesUsingPreset returns the namespaced names of the workspaces that run inference or tuning with the preset.
+func (c *ModelPresetReconciler) listWorkspacesUsingPreset(ctx context.Context, name string) ([]string, error) {
+	workspaceList := &kaitov1alpha1.WorkspaceList{}
+	if err := c.Client.List(ctx, workspaceList); err != nil {
+		klog.ErrorS(err, "failed to list workspaces", "modelpreset", name)
+		return nil, err
+	}
+	var users []string
+	for i := range workspaceList.Items {
+		ws := &workspaceList.Items[i]
+		if (ws.Inference != nil && ws.Inference.Preset != nil && string(ws.Inference.Preset.Name) == name) ||
+			(ws.Tuning != nil && ws.Tuning.Preset != nil && string(ws.Tuning.Preset.Name) == name) {
+			users = append(users, klog.KObj(ws).String())
+		}
+	}
+	return users, nil
+}
+
+func (c *ModelPresetReconciler) updateStatusConditionIfNotMatch(ctx context.Context, mpObj *kaitov1alpha1.ModelPreset,
+	cStatus metav1.ConditionStatus, cReason, cMessage string) error {
+	cType := kaitov1alpha1.ModelPresetConditionTypeRegistered
+	if curCondition := meta.FindStatusCondition(mpObj.Status.Conditions, string(cType)); curCondition != nil {
+		if curCondition.Status == cStatus && curCondition.Reason == cReason && curCondition.Message == cMessage {
+			return nil
+		}
+	}
+	klog.InfoS("updateStatusCondition", "modelpreset", klog.KObj(mpObj), "conditionType", cType, "status", cStatus, "reason", cReason, "message", cMessage)
+	cObj := metav1.Condition{
+		Type:               string(cType),
+		Status:             cStatus,
+		Reason:             cReason,
+		ObservedGeneration: mpObj.GetGeneration(),
+		Message:            cMessage,
+	}
+	if err := retry.OnError(retry.DefaultRetry,
+		func(err error) bool {
+			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
+		},
+		func() error {
+			// Read the latest version to avoid update conflict.
+			latest := &kaitov1alpha1.ModelPreset{}
+			if err := c.Client.Get(ctx, client.ObjectKeyFromObject(mpObj), latest); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			meta.SetStatusCondition(&latest.Status.Conditions, cObj)
+			return c.Client.Status().Update(ctx, latest)
+		}); err != nil {
+		klog.ErrorS(err, "failed to update model preset status", "modelpreset", klog.KObj(mpObj))
+		return err
+	}
+	meta.SetStatusCondition(&mpObj.Status.Conditions, cObj)
+	return nil
+}
+
+// registerModelPreset registers the model preset, replacing the previous version of the same ModelPreset. It returns
+// false without registering the model preset if its name is taken by a built-in preset.
+func registerModelPreset(mpObj *kaitov1alpha1.ModelPreset) bool {
+	if plugin.KaitoModelRegister.IsBuiltIn(mpObj.Name) {
+		return false
+	}
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     mpObj.Name,
+		Instance: NewModelPreset(mpObj),
+		Custom:   true,
+	})
+	return true
+}
+
+// unregisterModelPreset unregisters the model preset with the given name, leaving the built-in presets untouched.
+func unregisterModelPreset(name string) {
+	if !plugin.KaitoModelRegister.IsBuiltIn(name) {
+		plugin.KaitoModelRegister.Unregister(name)
+	}
+}
+
+// RegisterModelPresets registers all the existing ModelPresets. It is called before the manager starts, so that the
+// workspaces using them can be reconciled, and validated by the webhook, right away.
+func RegisterModelPresets(ctx context.Context, reader client.Reader) error {
+	mpList := &kaitov1alpha1.ModelPresetList{}
+	if err := reader.List(ctx, mpList); err != nil {
+		return err
+	}
+	for i := range mpList.Items {
+		if !registerModelPreset(&mpList.Items[i]) {
+			klog.InfoS("skipping the model preset with the name of a built-in preset", "modelpreset", klog.KObj(&mpList.Items[i]))
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager. The controller does not need leader election because
+// every replica, including the webhook, has its own model register to keep up to date.
+func (c *ModelPresetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c.Recorder = mgr.GetEventRecorderFor("ModelPreset")
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kaitov1alpha1.ModelPreset{}).
+		WithOptions(controller.Options{NeedLeaderElection: lo.ToPtr(false)}).
+		Complete(c)
+}
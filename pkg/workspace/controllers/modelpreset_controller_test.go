@@ -0,0 +1,183 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func testModelPreset(name string) *v1alpha1.ModelPreset {
+	return &v1alpha1.ModelPreset{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: v1alpha1.ModelPresetSpec{
+			ModelFamilyName: "Custom",
+			Inference: v1alpha1.ModelPresetParams{
+				Tag:                       "0.0.1",
+				DiskStorageRequirement:    "50Gi",
+				GPUCountRequirement:       "1",
+				TotalGPUMemoryRequirement: "16Gi",
+				Runtime: v1alpha1.ModelPresetRuntimeParams{
+					Transformers: &v1alpha1.TransformersRuntimeParams{BaseCommand: "accelerate launch"},
+					VLLM:         &v1alpha1.VLLMRuntimeParams{BaseCommand: "python3 /workspace/vllm/inference_api.py", ModelName: "custom"},
+				},
+			},
+		},
+	}
+}
+
+func TestModelPresetParameters(t *testing.T) {
+	mp := testModelPreset("custom")
+	mp.Spec.Tuning = mp.Spec.Inference.DeepCopy()
+	mp.Spec.Tuning.ReadinessTimeout = &v1.Duration{Duration: time.Hour}
+	m := NewModelPreset(mp)
+
+	params := m.GetInferenceParameters()
+	assert.Equal(t, "Custom", params.ModelFamilyName)
+	assert.Equal(t, "0.0.1", params.Tag)
+	assert.Equal(t, string(v1alpha1.ModelImageAccessModePublic), params.ImageAccessMode)
+	assert.Equal(t, "16Gi", params.TotalGPUMemoryRequirement)
+	assert.Equal(t, "0Gi", params.PerGPUMemoryRequirement)
+	assert.Equal(t, defaultModelPresetReadinessTimeout, params.ReadinessTimeout)
+	assert.Equal(t, "accelerate launch", params.Transformers.BaseCommand)
+	// Building the vLLM command adds to the run parameters, which must not change the model preset.
+	params.GetInferenceCommand(model.RuntimeNameVLLM, "1")
+	assert.Equal(t, "custom", params.VLLM.ModelRunParams["served-model-name"])
+	assert.Equal(t, 0, len(m.GetInferenceParameters().VLLM.ModelRunParams))
+
+	assert.Equal(t, true, m.SupportTuning())
+	assert.Equal(t, time.Hour, m.GetTuningParameters().ReadinessTimeout)
+	assert.Equal(t, false, m.SupportDistributedInference())
+}
+
+type testBuiltInModel struct{}
+
+func (*testBuiltInModel) GetInferenceParameters() *model.PresetParam { return &model.PresetParam{} }
+func (*testBuiltInModel) GetTuningParameters() *model.PresetParam    { return nil }
+func (*testBuiltInModel) SupportDistributedInference() bool          { return false }
+func (*testBuiltInModel) SupportTuning() bool                        { return false }
+
+func TestModelPresetReconcile(t *testing.T) {
+	plugin.KaitoModelRegister.Register(&plugin.Registration{Name: "test-built-in", Instance: &testBuiltInModel{}})
+	defer plugin.KaitoModelRegister.Unregister("test-built-in")
+	defer unregisterModelPreset("test-model-preset")
+
+	testcases := map[string]struct {
+		preset             *v1alpha1.ModelPreset
+		workspaces         []*v1alpha1.Workspace
+		callMocks          func(c *test.MockClient)
+		expectedRegistered bool
+		expectedBuiltIn    bool
+		expectedReason     string
+		expectedRequeue    bool
+	}{
+		"New model preset is registered": {
+			preset: testModelPreset("test-model-preset"),
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything, mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+			},
+			expectedRegistered: true,
+			expectedReason:     "ModelPresetRegistered",
+		},
+		"Model preset with the name of a built-in preset is not registered": {
+			preset: testModelPreset("test-built-in"),
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything, mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+			},
+			expectedBuiltIn: true,
+			expectedReason:  "NameConflict",
+		},
+		"Deleted model preset used by a workspace stays registered": {
+			preset: func() *v1alpha1.ModelPreset {
+				mp := testModelPreset("test-model-preset")
+				mp.DeletionTimestamp = &v1.Time{Time: time.Now()}
+				mp.Finalizers = []string{consts.ModelPresetFinalizer}
+				return mp
+			}(),
+			workspaces: func() []*v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithPreset.DeepCopy()
+				ws.Inference.Preset.Name = "test-model-preset"
+				return []*v1alpha1.Workspace{ws}
+			}(),
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.WorkspaceList{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+			},
+			expectedRegistered: true,
+			expectedReason:     "ModelPresetInUse",
+			expectedRequeue:    true,
+		},
+		"Deleted model preset is unregistered": {
+			preset: func() *v1alpha1.ModelPreset {
+				mp := testModelPreset("test-model-preset")
+				mp.DeletionTimestamp = &v1.Time{Time: time.Now()}
+				mp.Finalizers = []string{consts.ModelPresetFinalizer}
+				return mp
+			}(),
+			workspaces: []*v1alpha1.Workspace{test.MockWorkspaceWithPreset.DeepCopy()},
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.WorkspaceList{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			// Start each case with the previous version of the model preset registered.
+			unregisterModelPreset("test-model-preset")
+			registerModelPreset(testModelPreset("test-model-preset"))
+
+			mockClient := test.NewClient()
+			mockClient.CreateOrUpdateObjectInMap(tc.preset)
+			for _, ws := range tc.workspaces {
+				mockClient.CreateMapWithType(&v1alpha1.WorkspaceList{})[types.NamespacedName{Namespace: ws.Namespace, Name: ws.Name}] = ws
+			}
+			tc.callMocks(mockClient)
+			reconciler := NewModelPresetReconciler(mockClient, record.NewFakeRecorder(10))
+
+			result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: tc.preset.Name}})
+			assert.Check(t, err == nil, "Not expected to return error")
+			assert.Equal(t, tc.expectedRequeue, result.RequeueAfter > 0)
+
+			registered := plugin.KaitoModelRegister.Get(tc.preset.Name)
+			_, isModelPreset := registered.(*modelPreset)
+			assert.Equal(t, tc.expectedRegistered, isModelPreset)
+			if tc.expectedBuiltIn {
+				_, isBuiltIn := registered.(*testBuiltInModel)
+				assert.Check(t, isBuiltIn, "Expected the built-in preset to stay registered")
+			}
+			if tc.expectedReason != "" {
+				mockClient.StatusMock.AssertCalled(t, "Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything)
+				calls := mockClient.StatusMock.Calls
+				updated := calls[len(calls)-1].Arguments.Get(1).(*v1alpha1.ModelPreset)
+				cond := meta.FindStatusCondition(updated.Status.Conditions, string(v1alpha1.ModelPresetConditionTypeRegistered))
+				assert.Check(t, cond != nil, "Expected the Registered condition")
+				assert.Equal(t, tc.expectedReason, cond.Reason)
+			} else {
+				mockClient.AssertCalled(t, "Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.ModelPreset{}), mock.Anything)
+			}
+		})
+	}
+}
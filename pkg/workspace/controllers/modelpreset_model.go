@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+)
+
+// defaultModelPresetReadinessTimeout is used when a ModelPreset does not specify a readiness timeout.
+const defaultModelPresetReadinessTimeout = 30 * time.Minute
+
+// modelPreset adapts a ModelPreset into a model.Model so that it can be registered like the built-in presets.
+type modelPreset struct {
+	spec kaitov1alpha1.ModelPresetSpec
+}
+
+// NewModelPreset returns the model.Model described by the ModelPreset.
+func NewModelPreset(mp *kaitov1alpha1.ModelPreset) model.Model {
+	return &modelPreset{spec: *mp.Spec.DeepCopy()}
+}
+
+func (m *modelPreset) GetInferenceParameters() *model.PresetParam {
+	return m.presetParam(&m.spec.Inference)
+}
+
+func (m *modelPreset) GetTuningParameters() *model.PresetParam {
+	if m.spec.Tuning == nil {
+		return nil
+	}
+	return m.presetParam(m.spec.Tuning)
+}
+
+func (m *modelPreset) SupportDistributedInference() bool {
+	return m.spec.SupportDistributedInference
+}
+
+func (m *modelPreset) SupportTuning() bool {
+	return m.spec.Tuning != nil
+}
+
+// presetParam converts the parameters of the ModelPreset. It returns a new copy on each call because the callers
+// may modify the runtime parameters, and the maps are never nil for the same reason.
+func (m *modelPreset) presetParam(p *kaitov1alpha1.ModelPresetParams) *model.PresetParam {
+	readinessTimeout := defaultModelPresetReadinessTimeout
+	if p.ReadinessTimeout != nil {
+		readinessTimeout = p.ReadinessTimeout.Duration
+	}
+	// The webhook parses the per GPU memory requirement of every preset.
+	perGPUMemoryRequirement := p.PerGPUMemoryRequirement
+	if perGPUMemoryRequirement == "" {
+		perGPUMemoryRequirement = "0Gi"
+	}
+	imageAccessMode := p.ImageAccessMode
+	if imageAccessMode == "" {
+		imageAccessMode = kaitov1alpha1.ModelImageAccessModePublic
+	}
+
+	param := &model.PresetParam{
+		Tag:                           p.Tag,
+		ModelFamilyName:               m.spec.ModelFamilyName,
+		ImageAccessMode:               string(imageAccessMode),
+		DiskStorageRequirement:        p.DiskStorageRequirement,
+		GPUCountRequirement:           p.GPUCountRequirement,
+		TotalGPUMemoryRequirement:     p.TotalGPUMemoryRequirement,
+		PerGPUMemoryRequirement:       perGPUMemoryRequirement,
+		TuningPerGPUMemoryRequirement: p.TuningPerGPUMemoryRequirement,
+		WorldSize:                     p.WorldSize,
+		RuntimeParam: model.RuntimeParam{
+			DisableTensorParallelism: p.Runtime.DisableTensorParallelism,
+		},
+		ReadinessTimeout: readinessTimeout,
+	}
+	if t := p.Runtime.Transformers; t != nil {
+		param.Transformers = model.HuggingfaceTransformersParam{
+			BaseCommand:        t.BaseCommand,
+			TorchRunParams:     t.TorchRunParams,
+			TorchRunRdzvParams: t.TorchRunRdzvParams,
+			InferenceMainFile:  t.InferenceMainFile,
+			ModelRunParams:     t.ModelRunParams,
+		}
+	}
+	if v := p.Runtime.VLLM; v != nil {
+		param.VLLM = model.VLLMParam{
+			BaseCommand:        v.BaseCommand,
+			ModelName:          v.ModelName,
+			DistributionParams: v.DistributionParams,
+			ModelRunParams:     v.ModelRunParams,
+		}
+	}
+	// DeepCopy copies every map into a new, non-nil map.
+	return param.DeepCopy()
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// SKUCatalogReconciler merges the SKUs of the SKU catalog ConfigMap with the built-in SKUs, so that the webhook and
+// the controllers pick up changes to the catalog without a restart.
+type SKUCatalogReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	// Namespace is the release namespace that holds the SKU catalog ConfigMap.
+	Namespace string
+}
+
+func NewSKUCatalogReconciler(client client.Client, recorder record.EventRecorder, namespace string) *SKUCatalogReconciler {
+	return &SKUCatalogReconciler{
+		Client:    client,
+		Recorder:  recorder,
+		Namespace: namespace,
+	}
+}
+
+func (c *SKUCatalogReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Client.Get(ctx, req.NamespacedName, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "failed to get the SKU catalog", "configmap", req.NamespacedName)
+			return reconcile.Result{}, err
+		}
+		klog.InfoS("the SKU catalog is removed, only the built-in SKUs are supported", "configmap", req.NamespacedName)
+		sku.SetCatalog(nil)
+		return reconcile.Result{}, nil
+	}
+
+	catalog, err := sku.ParseCatalog(cm.Data[sku.CatalogKey])
+	if err != nil {
+		// The previous catalog stays in effect until the ConfigMap is fixed, which triggers another reconcile.
+		klog.ErrorS(err, "failed to load the SKU catalog", "configmap", klog.KObj(cm))
+		c.Recorder.Event(cm, corev1.EventTypeWarning, "InvalidSKUCatalog", err.Error())
+		return reconcile.Result{}, nil
+	}
+	sku.SetCatalog(catalog)
+	klog.InfoS("successfully loaded the SKU catalog", "configmap", klog.KObj(cm))
+	return reconcile.Result{}, nil
+}
+
+// LoadSKUCatalog loads the SKU catalog ConfigMap, if any. It is called before the manager starts, so that the webhook
+// validates instance types against the catalog right away.
+func LoadSKUCatalog(ctx context.Context, reader client.Reader, namespace string) error {
+	cm := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: consts.SKUCatalogConfigMapName, Namespace: namespace}, cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	catalog, err := sku.ParseCatalog(cm.Data[sku.CatalogKey])
+	if err != nil {
+		return err
+	}
+	sku.SetCatalog(catalog)
+	return nil
+}
+
+// SKUCatalogCacheByObject restricts the ConfigMap cache of the manager to the SKU catalog ConfigMap in the release
+// namespace, so that the ConfigMaps of the whole cluster are not watched. Other ConfigMaps, e.g., the tuning
+// configs, must then be read without the cache.
+func SKUCatalogCacheByObject(namespace string) map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.ConfigMap{}: {
+			Namespaces: map[string]cache.Config{namespace: {}},
+			Field:      fields.OneTermEqualSelector("metadata.name", consts.SKUCatalogConfigMapName),
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. Like the ModelPreset controller, it does not need leader
+// election because every replica has its own copy of the catalog.
+func (c *SKUCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c.Recorder = mgr.GetEventRecorderFor("SKUCatalog")
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("skucatalog").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetName() == consts.SKUCatalogConfigMapName && o.GetNamespace() == c.Namespace
+		}))).
+		WithOptions(controller.Options{NeedLeaderElection: lo.ToPtr(false)}).
+		Complete(c)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testSKUCatalog = `
+azure:
+  - sku: Standard_ND96isr_H100_v5
+    gpuCount: 8
+    gpuMem: 640
+    gpuModel: NVIDIA H100
+    interconnect: InfiniBand
+    vendor: nvidia
+`
+
+func testSKUCatalogConfigMap(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: consts.SKUCatalogConfigMapName, Namespace: "kaito-workspace"},
+		Data:       map[string]string{sku.CatalogKey: data},
+	}
+}
+
+func TestSKUCatalogReconcile(t *testing.T) {
+	defer sku.SetCatalog(nil)
+
+	// The steps run in order, each starting with the catalog of the previous one.
+	steps := []struct {
+		name          string
+		configMap     *corev1.ConfigMap
+		getErr        error
+		expectedSKU   bool
+		expectedEvent bool
+	}{
+		{
+			name:        "Catalog SKUs are merged with the built-in SKUs",
+			configMap:   testSKUCatalogConfigMap(testSKUCatalog),
+			expectedSKU: true,
+		},
+		{
+			name:          "Invalid catalog keeps the previous catalog",
+			configMap:     testSKUCatalogConfigMap("azure:\n  - sku: Standard_ND96isr_H100_v5\n    gpuCount: 8\n"),
+			expectedSKU:   true,
+			expectedEvent: true,
+		},
+		{
+			name:      "Removed catalog leaves only the built-in SKUs",
+			configMap: testSKUCatalogConfigMap(""),
+			getErr:    apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, consts.SKUCatalogConfigMapName),
+		},
+	}
+
+	for _, tc := range steps {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := test.NewClient()
+			if tc.getErr == nil {
+				mockClient.CreateOrUpdateObjectInMap(tc.configMap)
+			}
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(tc.getErr)
+			recorder := record.NewFakeRecorder(10)
+			reconciler := NewSKUCatalogReconciler(mockClient, recorder, "kaito-workspace")
+
+			_, err := reconciler.Reconcile(context.Background(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: tc.configMap.Name, Namespace: tc.configMap.Namespace},
+			})
+			assert.Check(t, err == nil, "Not expected to return error")
+
+			_, exists := sku.NewAzureSKUHandler().GetGPUConfigs()["Standard_ND96isr_H100_v5"]
+			assert.Equal(t, tc.expectedSKU, exists)
+			assert.Equal(t, tc.expectedEvent, len(recorder.Events) > 0)
+		})
+	}
+}
+
+func TestLoadSKUCatalog(t *testing.T) {
+	defer sku.SetCatalog(nil)
+
+	mockClient := test.NewClient()
+	mockClient.CreateOrUpdateObjectInMap(testSKUCatalogConfigMap(testSKUCatalog))
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+
+	assert.NilError(t, LoadSKUCatalog(context.Background(), mockClient, "kaito-workspace"))
+	config, exists := sku.NewAzureSKUHandler().GetGPUConfigs()["Standard_ND96isr_H100_v5"]
+	assert.Check(t, exists, "Expected the SKU of the catalog")
+	assert.Equal(t, "InfiniBand", config.Interconnect)
+}
+
+func TestSKUCatalogCacheByObject(t *testing.T) {
+	byObject := SKUCatalogCacheByObject("kaito-workspace")
+	assert.Equal(t, 1, len(byObject))
+	for obj, opts := range byObject {
+		_, isConfigMap := obj.(*corev1.ConfigMap)
+		assert.Assert(t, isConfigMap)
+		_, found := opts.Namespaces["kaito-workspace"]
+		assert.Assert(t, found && len(opts.Namespaces) == 1)
+		assert.Assert(t, opts.Field.Matches(fields.Set{"metadata.name": consts.SKUCatalogConfigMapName}))
+		assert.Assert(t, !opts.Field.Matches(fields.Set{"metadata.name": "lora-params-template"}))
+	}
+}
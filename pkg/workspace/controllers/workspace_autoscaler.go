@@ -0,0 +1,161 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/workspace/autoscaler"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	defaultScaleDownStabilizationSeconds = 300
+)
+
+var (
+	// autoscalingInterval is the interval to evaluate the runtime metrics of an autoscaled workspace.
+	autoscalingInterval = 30 * time.Second
+
+	metricsHTTPClient = &http.Client{Timeout: 5 * time.Second}
+	// scrapeRuntimeMetrics fetches the runtime metrics from an inference pod. It is a variable for testing.
+	scrapeRuntimeMetrics = func(ctx context.Context, pod *corev1.Pod, runtime model.Runtime) (*autoscaler.RuntimeMetrics, error) {
+		if runtime.MetricsPath() == "" {
+			return nil, fmt.Errorf("runtime %s does not expose metrics", runtime.Name())
+		}
+		url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, runtime.Port(), runtime.MetricsPath())
+		return autoscaler.ScrapeRuntimeMetrics(ctx, metricsHTTPClient, url)
+	}
+)
+
+// autoscaleInference adjusts the count of the workspace based on the metrics reported by the ready inference pods.
+// Scaling up happens immediately while scaling down waits until the stabilization window since the last scaling
+// has passed. The new count is reconciled like a user change: nodes are provisioned and the workload is updated.
+func (c *WorkspaceReconciler) autoscaleInference(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
+	spec := wObj.Inference.Autoscaling
+	result := reconcile.Result{RequeueAfter: autoscalingInterval}
+	runtime, err := inference.GetWorkspaceRuntime(wObj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1alpha1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var metrics []autoscaler.RuntimeMetrics
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		m, err := scrapeRuntimeMetrics(ctx, pod, runtime)
+		if err != nil {
+			klog.ErrorS(err, "failed to scrape runtime metrics", "workspace", klog.KObj(wObj), "pod", klog.KObj(pod))
+			continue
+		}
+		metrics = append(metrics, *m)
+	}
+
+	currentCount := lo.FromPtr(wObj.Resource.Count)
+	desiredCount := autoscaler.DesiredCount(spec, currentCount, metrics)
+	if desiredCount == currentCount {
+		return result, nil
+	}
+
+	if desiredCount < currentCount && wObj.Status.LastScaleTime != nil {
+		stabilization := time.Duration(lo.FromPtrOr(spec.ScaleDownStabilizationSeconds, defaultScaleDownStabilizationSeconds)) * time.Second
+		if remaining := stabilization - time.Since(wObj.Status.LastScaleTime.Time); remaining > 0 {
+			klog.InfoS("scale down is delayed by the stabilization window", "workspace", klog.KObj(wObj),
+				"currentCount", currentCount, "desiredCount", desiredCount, "remaining", remaining)
+			return reconcile.Result{RequeueAfter: min(remaining, autoscalingInterval)}, nil
+		}
+	}
+
+	klog.InfoS("autoscaling workspace", "workspace", klog.KObj(wObj), "metric", spec.Metric,
+		"currentCount", currentCount, "desiredCount", desiredCount)
+	patch := client.MergeFrom(wObj.DeepCopy())
+	wObj.Resource.Count = lo.ToPtr(desiredCount)
+	wObj.Spec = &kaitov1alpha1.ScaleSpec{Replicas: lo.ToPtr(int32(desiredCount))}
+	if err := c.Client.Patch(ctx, wObj, patch); err != nil {
+		klog.ErrorS(err, "failed to update workspace count", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, err
+	}
+
+	now := metav1.Now()
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace},
+		func(status *kaitov1alpha1.WorkspaceStatus) {
+			status.LastScaleTime = &now
+		}); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, err
+	}
+	wObj.Status.LastScaleTime = &now
+
+	c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "Autoscaled",
+		"Count changed from %d to %d based on metric %s", currentCount, desiredCount, spec.Metric)
+	return result, nil
+}
+
+// syncScaleReplicas keeps the replicas of the scale subresource in sync with the count of the workspace. The replicas
+// are initialized from the count, and a change of the replicas, e.g., by an HPA or KEDA, is applied to the count, which
+// is then reconciled like a user change. A change of the count alone is applied to the replicas instead, which is told
+// apart by the replicas still being those last applied to the workload. The scale subresource bypasses the webhook,
+// so invalid replicas are reverted.
+func (c *WorkspaceReconciler) syncScaleReplicas(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	if !kaitov1alpha1.IsCountMutable(wObj.Inference) {
+		return nil
+	}
+	count := lo.FromPtr(wObj.Resource.Count)
+	patch := client.MergeFrom(wObj.DeepCopy())
+	switch {
+	case wObj.Spec == nil || wObj.Spec.Replicas == nil:
+		wObj.Spec = &kaitov1alpha1.ScaleSpec{Replicas: lo.ToPtr(int32(count))}
+	case int(*wObj.Spec.Replicas) == count:
+		return nil
+	case *wObj.Spec.Replicas < 1:
+		c.Recorder.Eventf(wObj, corev1.EventTypeWarning, "ScaleFailed",
+			"Replicas %d are reverted to the count %d, use the idle policy to scale the workspace to zero", *wObj.Spec.Replicas, count)
+		wObj.Spec.Replicas = lo.ToPtr(int32(count))
+	case *wObj.Spec.Replicas == wObj.Status.DesiredReplicas:
+		// The replicas are those last applied to the workload, so the count has been changed since, e.g., by
+		// kubectl apply of a manifest without the replicas.
+		replicas := *wObj.Spec.Replicas
+		klog.InfoS("syncing workspace replicas with the count", "workspace", klog.KObj(wObj), "count", count, "currentReplicas", replicas)
+		wObj.Spec.Replicas = lo.ToPtr(int32(count))
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "Scaled", "Replicas changed from %d to %d following resource.count", replicas, count)
+	default:
+		replicas := int(*wObj.Spec.Replicas)
+		klog.InfoS("scaling workspace", "workspace", klog.KObj(wObj), "currentCount", count, "replicas", replicas)
+		wObj.Resource.Count = lo.ToPtr(replicas)
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "Scaled", "Count changed from %d to %d through the scale subresource", count, replicas)
+	}
+	if err := c.Client.Patch(ctx, wObj, patch); err != nil {
+		klog.ErrorS(err, "failed to sync workspace replicas", "workspace", klog.KObj(wObj))
+		return err
+	}
+	return nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
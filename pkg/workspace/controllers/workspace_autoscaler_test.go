@@ -0,0 +1,253 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/autoscaler"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAutoscaleInference(t *testing.T) {
+	newPod := func(name, ip string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "kaito"},
+			Status: corev1.PodStatus{
+				PodIP:      ip,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	testcases := map[string]struct {
+		count          int
+		lastScaleTime  *v1.Time
+		pods           []*corev1.Pod
+		podMetrics     map[string]*autoscaler.RuntimeMetrics
+		callMocks      func(c *test.MockClient)
+		expectedCount  int
+		expectedScaled bool
+		expectedError  error
+	}{
+		"Fail to list pods": {
+			count: 1,
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(errors.New("failed to list pods"))
+			},
+			expectedCount: 1,
+			expectedError: errors.New("failed to list pods"),
+		},
+		"Scale up when the pending requests exceed the target": {
+			count: 1,
+			pods:  []*corev1.Pod{newPod("pod1", "10.0.0.1", true)},
+			podMetrics: map[string]*autoscaler.RuntimeMetrics{
+				"10.0.0.1": {PendingRequests: 9},
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedCount:  2,
+			expectedScaled: true,
+		},
+		"Pods that are not ready are ignored": {
+			count: 1,
+			pods:  []*corev1.Pod{newPod("pod1", "10.0.0.1", false)},
+			podMetrics: map[string]*autoscaler.RuntimeMetrics{
+				"10.0.0.1": {PendingRequests: 9},
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			},
+			expectedCount: 1,
+		},
+		"Scale down is delayed by the stabilization window": {
+			count:         2,
+			lastScaleTime: &v1.Time{Time: time.Now().Add(-time.Minute)},
+			pods:          []*corev1.Pod{newPod("pod1", "10.0.0.1", true), newPod("pod2", "10.0.0.2", true)},
+			podMetrics: map[string]*autoscaler.RuntimeMetrics{
+				"10.0.0.1": {},
+				"10.0.0.2": {},
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			},
+			expectedCount: 2,
+		},
+		"Scale down after the stabilization window": {
+			count:         2,
+			lastScaleTime: &v1.Time{Time: time.Now().Add(-10 * time.Minute)},
+			pods:          []*corev1.Pod{newPod("pod1", "10.0.0.1", true), newPod("pod2", "10.0.0.2", true)},
+			podMetrics: map[string]*autoscaler.RuntimeMetrics{
+				"10.0.0.1": {},
+				"10.0.0.2": {},
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedCount:  1,
+			expectedScaled: true,
+		},
+	}
+
+	originalScrape := scrapeRuntimeMetrics
+	defer func() { scrapeRuntimeMetrics = originalScrape }()
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			podMap := mockClient.CreateMapWithType(&corev1.PodList{})
+			for _, pod := range tc.pods {
+				podMap[client.ObjectKeyFromObject(pod)] = pod
+			}
+			tc.callMocks(mockClient)
+			scrapeRuntimeMetrics = func(_ context.Context, pod *corev1.Pod, _ model.Runtime) (*autoscaler.RuntimeMetrics, error) {
+				return tc.podMetrics[pod.Status.PodIP], nil
+			}
+
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Resource.Count = lo.ToPtr(tc.count)
+			workspace.Inference.Autoscaling = &v1alpha1.AutoscalingSpec{
+				MinCount:    1,
+				MaxCount:    3,
+				Metric:      v1alpha1.AutoscalingMetricPendingRequests,
+				TargetValue: 5,
+			}
+			workspace.Status.LastScaleTime = tc.lastScaleTime
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+
+			result, err := reconciler.autoscaleInference(context.Background(), workspace)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			assert.Check(t, err == nil, "Not expected to return error")
+			assert.Check(t, result.RequeueAfter > 0, "Expected to evaluate the metrics again")
+			assert.Equal(t, tc.expectedCount, *workspace.Resource.Count)
+			assert.Equal(t, tc.expectedScaled, len(recorder.Events) == 1)
+			if tc.expectedScaled {
+				assert.Equal(t, int32(tc.expectedCount), *workspace.Spec.Replicas)
+			}
+		})
+	}
+}
+
+func TestSyncScaleReplicas(t *testing.T) {
+	test.RegisterTestModel()
+	testcases := map[string]struct {
+		distributed      bool
+		replicas         *int32
+		desiredReplicas  int32
+		expectedPatch    bool
+		expectedCount    int
+		expectedReplicas *int32
+		expectedEvent    string
+	}{
+		"Replicas are initialized from the count": {
+			expectedPatch:    true,
+			expectedCount:    2,
+			expectedReplicas: lo.ToPtr(int32(2)),
+		},
+		"Replicas in sync with the count": {
+			replicas:         lo.ToPtr(int32(2)),
+			expectedCount:    2,
+			expectedReplicas: lo.ToPtr(int32(2)),
+		},
+		"Replicas changed through the scale subresource": {
+			replicas:         lo.ToPtr(int32(4)),
+			expectedPatch:    true,
+			expectedCount:    4,
+			expectedReplicas: lo.ToPtr(int32(4)),
+			expectedEvent:    "Normal Scaled Count changed from 2 to 4 through the scale subresource",
+		},
+		"Replicas follow the count changed since they were applied": {
+			replicas:         lo.ToPtr(int32(4)),
+			desiredReplicas:  4,
+			expectedPatch:    true,
+			expectedCount:    2,
+			expectedReplicas: lo.ToPtr(int32(2)),
+			expectedEvent:    "Normal Scaled Replicas changed from 4 to 2 following resource.count",
+		},
+		"Scaling to zero is reverted": {
+			replicas:         lo.ToPtr(int32(0)),
+			expectedPatch:    true,
+			expectedCount:    2,
+			expectedReplicas: lo.ToPtr(int32(2)),
+			expectedEvent:    "Warning ScaleFailed Replicas 0 are reverted to the count 2, use the idle policy to scale the workspace to zero",
+		},
+		"Distributed inference is not scaled": {
+			distributed:   true,
+			expectedCount: 2,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			mockClient.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything, mock.Anything).Return(nil)
+
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			if tc.distributed {
+				workspace = test.MockWorkspaceDistributedModel.DeepCopy()
+			}
+			workspace.Resource.Count = lo.ToPtr(2)
+			if tc.replicas != nil {
+				workspace.Spec = &v1alpha1.ScaleSpec{Replicas: tc.replicas}
+			}
+			workspace.Status.DesiredReplicas = tc.desiredReplicas
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+
+			assert.NilError(t, reconciler.syncScaleReplicas(context.Background(), workspace))
+			if tc.expectedPatch {
+				mockClient.AssertNumberOfCalls(t, "Patch", 1)
+			} else {
+				mockClient.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			assert.Equal(t, tc.expectedCount, *workspace.Resource.Count)
+			if tc.expectedReplicas == nil {
+				assert.Assert(t, workspace.Spec == nil)
+			} else {
+				assert.Equal(t, *tc.expectedReplicas, *workspace.Spec.Replicas)
+			}
+			if tc.expectedEvent == "" {
+				assert.Equal(t, 0, len(recorder.Events))
+			} else {
+				assert.Equal(t, tc.expectedEvent, <-recorder.Events)
+			}
+		})
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
 	"github.com/go-logr/logr"
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/machine"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
@@ -35,17 +37,15 @@ import (
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/clock"
-	"knative.dev/pkg/apis"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -57,6 +57,24 @@ const (
 	WorkspaceHashAnnotation = "workspace.kaito.io/hash"
 	WorkspaceNameLabel      = "workspace.kaito.io/name"
 	revisionHashSuffix      = 5
+
+	// workloadPendingReason is the condition reason used while the workload is being deployed or is not ready yet.
+	workloadPendingReason = "WorkloadPending"
+	// workloadTimeoutReason is the condition reason used when the workload is not ready within the readiness timeout.
+	workloadTimeoutReason = "WorkloadReadinessTimeout"
+)
+
+var (
+	// nodeProvisioningRequeueInterval is the interval to check pending nodeClaims/machines in case an event is missed.
+	nodeProvisioningRequeueInterval = 30 * time.Second
+	// nodePluginRequeueInterval is the interval to check whether the GPU device plugins are ready on the nodes.
+	nodePluginRequeueInterval = 10 * time.Second
+	// workloadReadinessRequeueInterval is the maximum interval to check the workload readiness.
+	workloadReadinessRequeueInterval = 30 * time.Second
+	// gpuQuotaRequeueInterval is the interval to check again a workspace whose nodes exceed the GPU quota of its namespace.
+	gpuQuotaRequeueInterval = time.Minute
+	// templateInferenceReadinessTimeout is the readiness timeout of the inference workload created from a pod template.
+	templateInferenceReadinessTimeout = 10 * time.Minute
 )
 
 type WorkspaceReconciler struct {
@@ -94,6 +112,18 @@ func (c *WorkspaceReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 		return c.deleteWorkspace(ctx, workspaceObj)
 	}
 
+	if err := c.rollbackWorkspace(ctx, workspaceObj); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := c.upgradePreset(ctx, workspaceObj); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := c.syncScaleReplicas(ctx, workspaceObj); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if err := c.syncControllerRevision(ctx, workspaceObj); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -103,6 +133,9 @@ func (c *WorkspaceReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 			return reconcile.Result{}, fmt.Errorf("the preset model name %s is not registered for workspace %s/%s",
 				string(workspaceObj.Inference.Preset.Name), workspaceObj.Namespace, workspaceObj.Name)
 		}
+		if err := c.updatePresetUpgradeCondition(ctx, workspaceObj); err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	result, err := c.addOrUpdateWorkspace(ctx, workspaceObj)
@@ -126,30 +159,69 @@ func (c *WorkspaceReconciler) ensureFinalizer(ctx context.Context, workspaceObj
 }
 
 func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
+	var nextScheduleTransition time.Time
+	if wObj.Inference != nil {
+		stopped, next, err := c.applySchedule(ctx, wObj)
+		if err != nil {
+			if updateErr := c.markWorkspaceFailed(ctx, wObj, err); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+			return reconcile.Result{}, err
+		}
+		if stopped {
+			if next.IsZero() {
+				// Stopped by the override annotation, which triggers a reconcile when it is changed.
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{RequeueAfter: max(time.Until(next), time.Second)}, nil
+		}
+		nextScheduleTransition = next
+
+		scaledToZero, err := c.applyIdlePolicy(ctx, wObj)
+		if err != nil {
+			if updateErr := c.markWorkspaceFailed(ctx, wObj, err); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+			return reconcile.Result{}, err
+		}
+		if scaledToZero {
+			// Check periodically whether the activator holds requests that should bring the workspace back.
+			return reconcile.Result{RequeueAfter: idleCheckInterval}, nil
+		}
+	}
+
 	// Read ResourceSpec
-	err := c.applyWorkspaceResource(ctx, wObj)
+	result, err := c.applyWorkspaceResource(ctx, wObj)
 	if err != nil {
-		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-			"workspaceFailed", err.Error()); updateErr != nil {
-			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		if updateErr := c.markWorkspaceFailed(ctx, wObj, err); updateErr != nil {
 			return reconcile.Result{}, updateErr
 		}
-		// If the error is due to machine/nodeClaim instance types unavailability, stop reconcile.
+		// If the error is due to machine/nodeClaim instance types unavailability and there is no fallback
+		// instance type left, stop reconcile.
 		if err.Error() == consts.ErrorInstanceTypesUnavailable {
 			return reconcile.Result{Requeue: false}, err
 		}
 		return reconcile.Result{}, err
 	}
+	if !result.IsZero() {
+		// The nodes are not ready yet, the workspace will be reconciled again when their status changes.
+		return result, nil
+	}
 
 	if wObj.Tuning != nil {
-		if err = c.applyTuning(ctx, wObj); err != nil {
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-				"workspaceFailed", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		if result, err = c.applyTuning(ctx, wObj); err != nil {
+			if updateErr := c.markWorkspaceFailed(ctx, wObj, err); updateErr != nil {
 				return reconcile.Result{}, updateErr
 			}
 			return reconcile.Result{}, err
 		}
+		if !result.IsZero() {
+			if err = c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseWaitingReady); err != nil {
+				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return reconcile.Result{}, err
+			}
+			return result, nil
+		}
 		// Only mark workspace succeeded when job completes.
 		job := &batchv1.Job{}
 		if err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, job); err == nil {
@@ -159,6 +231,13 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
 					return reconcile.Result{}, updateErr
 				}
+				if wObj.Tuning.Output != nil && wObj.Tuning.Output.Volume != nil {
+					outputPath := tuning.GetTuningOutputPath(wObj, job.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation])
+					if updateErr := c.updateStatusTuningOutputPathIfNotMatch(ctx, wObj, outputPath); updateErr != nil {
+						klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+						return reconcile.Result{}, updateErr
+					}
+				}
 			} else { // The job is still running
 				var readyPod int32
 				if job.Status.Ready != nil {
@@ -169,6 +248,13 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
 					return reconcile.Result{}, updateErr
 				}
+				if job.Status.Active > 0 {
+					if updateErr := c.updateTuningProgress(ctx, wObj); updateErr != nil {
+						klog.ErrorS(updateErr, "failed to update tuning progress", "workspace", klog.KObj(wObj))
+					}
+					// Keep scraping the progress until the job completes.
+					result = reconcile.Result{RequeueAfter: tuningProgressInterval}
+				}
 			}
 		} else {
 			klog.ErrorS(err, "failed to get job resource", "workspace", klog.KObj(wObj))
@@ -176,30 +262,66 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 		}
 	} else if wObj.Inference != nil {
 		if err := c.ensureService(ctx, wObj); err != nil {
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-				"workspaceFailed", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			if updateErr := c.markWorkspaceFailed(ctx, wObj, err); updateErr != nil {
 				return reconcile.Result{}, updateErr
 			}
 			return reconcile.Result{}, err
 		}
-		if err = c.applyInference(ctx, wObj); err != nil {
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-				"workspaceFailed", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		if result, err = c.applyInference(ctx, wObj); err != nil {
+			if updateErr := c.markWorkspaceFailed(ctx, wObj, err); updateErr != nil {
 				return reconcile.Result{}, updateErr
 			}
 			return reconcile.Result{}, err
 		}
+		if !result.IsZero() {
+			if err = c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseWaitingReady); err != nil {
+				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return reconcile.Result{}, err
+			}
+			return result, nil
+		}
 
 		if err = c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue,
 			"workspaceSucceeded", "workspace succeeds"); err != nil {
 			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 			return reconcile.Result{}, err
 		}
+
+		if wObj.Inference.Autoscaling != nil {
+			// Autoscaling only starts after the workload is ready so that the metrics reflect all replicas.
+			if result, err = c.autoscaleInference(ctx, wObj); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if wObj.Inference.IdlePolicy != nil && (result.IsZero() || result.RequeueAfter > idleCheckInterval) {
+			// Keep tracking the request activity to scale the workspace to zero once it is idle.
+			result = reconcile.Result{RequeueAfter: idleCheckInterval}
+		}
+		if untilNext := max(time.Until(nextScheduleTransition), time.Second); !nextScheduleTransition.IsZero() && (result.IsZero() || result.RequeueAfter > untilNext) {
+			// Stop the workload at the end of the schedule window.
+			result = reconcile.Result{RequeueAfter: untilNext}
+		}
 	}
 
-	return reconcile.Result{}, nil
+	if err = c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseReady); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, err
+	}
+	return result, nil
+}
+
+// markWorkspaceFailed records the error in the WorkspaceSucceeded condition and moves the workspace to the Failed phase.
+func (c *WorkspaceReconciler) markWorkspaceFailed(ctx context.Context, wObj *kaitov1alpha1.Workspace, cause error) error {
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
+		"workspaceFailed", cause.Error()); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	if err := c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseFailed); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	return nil
 }
 
 func (c *WorkspaceReconciler) deleteWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
@@ -286,6 +408,14 @@ func (c *WorkspaceReconciler) syncControllerRevision(ctx context.Context, wObj *
 		if controllerRevision.Annotations[WorkspaceHashAnnotation] != newRevision.Annotations[WorkspaceHashAnnotation] {
 			return fmt.Errorf("revision name conflicts, the hash values are different")
 		}
+		// Like a Deployment, a workspace restored to a previous revision, e.g., by a rollback, makes that revision the
+		// latest one by giving it the next revision number.
+		if latestRevision != nil && controllerRevision.Name != latestRevision.Name {
+			controllerRevision.Revision = revisionNum
+			if err := c.Update(ctx, controllerRevision); err != nil {
+				return fmt.Errorf("failed to update ControllerRevision %s: %w", controllerRevision.Name, err)
+			}
+		}
 		annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] = strconv.FormatInt(controllerRevision.Revision, 10)
 	}
 	annotations[WorkspaceHashAnnotation] = currentHash
@@ -299,7 +429,7 @@ func (c *WorkspaceReconciler) syncControllerRevision(ctx context.Context, wObj *
 
 func marshalSelectedFields(wObj *kaitov1alpha1.Workspace) ([]byte, error) {
 	partialMap := map[string]interface{}{
-		"resource":  wObj.Resource,
+		"resource":  revisionResource(wObj),
 		"inference": wObj.Inference,
 		"tuning":    wObj.Tuning,
 	}
@@ -315,78 +445,147 @@ func marshalSelectedFields(wObj *kaitov1alpha1.Workspace) ([]byte, error) {
 func computeHash(w *kaitov1alpha1.Workspace) string {
 	hasher := sha256.New()
 	encoder := json.NewEncoder(hasher)
-	encoder.Encode(w.Resource)
+	encoder.Encode(revisionResource(w))
 	encoder.Encode(w.Inference)
 	encoder.Encode(w.Tuning)
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
-// applyWorkspaceResource applies workspace resource spec.
-func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
-	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
-		// Wait for pending nodeClaims if any before we decide whether to create new node or not.
-		if err := nodeclaim.WaitForPendingNodeClaims(ctx, wObj, c.Client); err != nil {
-			return err
+// revisionResource returns the resource spec recorded in the revisions of the workspace. Like the replicas of a
+// Deployment, the count of a workspace that can be scaled is left out, so that scaling, e.g., by an autoscaler, does
+// not create revisions and a rollback keeps the current count.
+func revisionResource(w *kaitov1alpha1.Workspace) kaitov1alpha1.ResourceSpec {
+	if !kaitov1alpha1.IsCountMutable(w.Inference) {
+		return w.Resource
+	}
+	resource := *w.Resource.DeepCopy()
+	resource.Count = nil
+	return resource
+}
+
+// applyWorkspaceResource applies workspace resource spec. It does not wait for new nodes to be provisioned; a non-zero
+// result is returned instead so that the workspace is reconciled again when the nodes are expected to be ready.
+func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
+	// Check pending nodeClaims/machines if any before we decide whether to create new node or not.
+	pendingCount, err := c.getPendingNodeCount(ctx, wObj)
+	if err != nil {
+		if err.Error() == consts.ErrorInstanceTypesUnavailable {
+			// Retry with the next instance type in the fallback list, if any.
+			fellBack, fallbackErr := c.fallbackInstanceType(ctx, wObj)
+			if fallbackErr != nil {
+				return reconcile.Result{}, fallbackErr
+			}
+			if fellBack {
+				return reconcile.Result{Requeue: true}, nil
+			}
 		}
-	} else {
-		// Wait for pending machines if any before we decide whether to create new machine or not.
-		if err := machine.WaitForPendingMachines(ctx, wObj, c.Client); err != nil {
-			return err
+		return reconcile.Result{}, err
+	}
+	if pendingCount > 0 {
+		klog.InfoS("waiting for pending nodes to be provisioned", "workspace", klog.KObj(wObj), "pendingCount", pendingCount)
+		if err := c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseProvisioningNodes); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: nodeProvisioningRequeueInterval}, nil
+	}
+
+	// Release the nodes exceeding the count if the workspace has been scaled down.
+	if err := c.releaseSurplusNodes(ctx, wObj); err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
+			"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, updateErr
 		}
+		return reconcile.Result{}, err
 	}
 
 	// Find all nodes that meet the requirements, they are not necessarily created by machines/nodeClaims.
 	validNodes, err := c.getAllQualifiedNodes(ctx, wObj)
 	if err != nil {
-		return err
+		return reconcile.Result{}, err
 	}
 
 	selectedNodes := utils.SelectNodes(validNodes, wObj.Resource.PreferredNodes, wObj.Status.WorkerNodes, lo.FromPtr(wObj.Resource.Count))
 
 	newNodesCount := lo.FromPtr(wObj.Resource.Count) - len(selectedNodes)
 
+	if newNodesCount > 0 && !utils.IsNodeProvisioningEnabled() {
+		// The controller does not watch nodes, check again later in case nodes are added or become ready.
+		klog.InfoS("waiting for more existing nodes", "workspace", klog.KObj(wObj), "NodeCount", newNodesCount)
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
+			"InsufficientNodes", fmt.Sprintf("found %d of the %d ready nodes of instance type %s, nodes are not provisioned on %s clusters",
+				len(selectedNodes), lo.FromPtr(wObj.Resource.Count), kaitov1alpha1.GetWorkspaceInstanceType(wObj), consts.OnPremCloudName)); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: nodeProvisioningRequeueInterval}, nil
+	}
+
 	if newNodesCount > 0 {
 		klog.InfoS("need to create more nodes", "NodeCount", newNodesCount)
+		// The controller does not watch GPU quotas, check again later in case the quota is raised or freed up.
+		if withinQuota, err := c.checkGPUQuota(ctx, wObj); err != nil {
+			return reconcile.Result{}, err
+		} else if !withinQuota {
+			return reconcile.Result{RequeueAfter: gpuQuotaRequeueInterval}, nil
+		}
 		if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
 			if err := c.updateStatusConditionIfNotMatch(ctx, wObj,
 				kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionUnknown,
 				"CreateNodeClaimPending", fmt.Sprintf("creating %d nodeClaims", newNodesCount)); err != nil {
 				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return err
+				return reconcile.Result{}, err
 			}
 		} else if err := c.updateStatusConditionIfNotMatch(ctx, wObj,
 			kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionUnknown,
 			"CreateMachinePending", fmt.Sprintf("creating %d machines", newNodesCount)); err != nil {
 			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return err
+			return reconcile.Result{}, err
+		}
+		if err := c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseProvisioningNodes); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+		if err := c.updateStatusInstanceTypeIfNotMatch(ctx, wObj, kaitov1alpha1.GetWorkspaceInstanceType(wObj)); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
 		}
 
 		for i := 0; i < newNodesCount; i++ {
-			newNode, err := c.createAndValidateNode(ctx, wObj)
-			if err != nil {
+			if err := c.createNode(ctx, wObj); err != nil {
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 					"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-					return updateErr
+					return reconcile.Result{}, updateErr
 				}
-				return err
+				return reconcile.Result{}, err
 			}
-			selectedNodes = append(selectedNodes, newNode)
 		}
+		return reconcile.Result{RequeueAfter: nodeProvisioningRequeueInterval}, nil
 	}
 
-	// Ensure all gpu plugins are running successfully.
-	if strings.Contains(wObj.Resource.InstanceType, consts.GpuSkuPrefix) { // GPU skus
+	// Ensure all gpu plugins are running successfully. They are not needed by the models served on CPUs.
+	if strings.Contains(kaitov1alpha1.GetWorkspaceInstanceType(wObj), consts.GpuSkuPrefix) && !inference.IsCPUInference(wObj) { // GPU skus
+		pluginsReady := true
 		for i := range selectedNodes {
-			err = c.ensureNodePlugins(ctx, wObj, selectedNodes[i])
+			ready, err := c.ensureNodePlugins(ctx, wObj, selectedNodes[i])
 			if err != nil {
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 					"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-					return updateErr
+					return reconcile.Result{}, updateErr
 				}
-				return err
+				return reconcile.Result{}, err
 			}
+			pluginsReady = pluginsReady && ready
+		}
+		if !pluginsReady {
+			if err := c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseInstallingPlugins); err != nil {
+				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: nodePluginRequeueInterval}, nil
 		}
 	}
 
@@ -395,13 +594,13 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 			kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionTrue,
 			"installNodePluginsSuccess", "nodeClaim plugins have been installed successfully"); err != nil {
 			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return err
+			return reconcile.Result{}, err
 		}
 	} else if err = c.updateStatusConditionIfNotMatch(ctx, wObj,
 		kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionTrue,
 		"installNodePluginsSuccess", "machines plugins have been installed successfully"); err != nil {
 		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
-		return err
+		return reconcile.Result{}, err
 	}
 
 	// Add the valid nodes names to the WorkspaceStatus.WorkerNodes.
@@ -410,18 +609,37 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 			"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return updateErr
+			return reconcile.Result{}, updateErr
 		}
-		return err
+		return reconcile.Result{}, err
 	}
 
 	if err = c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionTrue,
 		"workspaceResourceStatusSuccess", "workspace resource is ready"); err != nil {
 		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
-		return err
+		return reconcile.Result{}, err
 	}
 
-	return nil
+	return reconcile.Result{}, nil
+}
+
+// getPendingNodeCount returns the number of nodeClaims/machines created by the workspace that are not ready yet.
+func (c *WorkspaceReconciler) getPendingNodeCount(ctx context.Context, wObj *kaitov1alpha1.Workspace) (int, error) {
+	if !utils.IsNodeProvisioningEnabled() {
+		return 0, nil
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+		pendingNodeClaims, err := nodeclaim.GetPendingNodeClaims(ctx, wObj, c.Client)
+		if err != nil {
+			return 0, err
+		}
+		return len(pendingNodeClaims), nil
+	}
+	pendingMachines, err := machine.GetPendingMachines(ctx, wObj, c.Client)
+	if err != nil {
+		return 0, err
+	}
+	return len(pendingMachines), nil
 }
 
 func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) ([]*corev1.Node, error) {
@@ -441,8 +659,8 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 
 	for index := range nodeList.Items {
 		nodeObj := nodeList.Items[index]
-		// skip nodes that are being deleted
-		if nodeObj.DeletionTimestamp != nil {
+		// skip nodes that are being deleted or cordoned, e.g., released by a scale down
+		if nodeObj.DeletionTimestamp != nil || nodeObj.Spec.Unschedulable {
 			continue
 		}
 
@@ -460,8 +678,8 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 			continue
 		}
 
-		// match the instanceType
-		if nodeObj.Labels[corev1.LabelInstanceTypeStable] == wObj.Resource.InstanceType {
+		// match the instanceType, which is the GPU product of nodes without an instance type on on-prem clusters
+		if sku.NodeSKU(&nodeObj) == kaitov1alpha1.GetWorkspaceInstanceType(wObj) {
 			qualifiedNodes = append(qualifiedNodes, lo.ToPtr(nodeObj))
 		}
 	}
@@ -469,134 +687,111 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 	return qualifiedNodes, nil
 }
 
-// createAndValidateNode creates a new node and validates status.
-func (c *WorkspaceReconciler) createAndValidateNode(ctx context.Context, wObj *kaitov1alpha1.Workspace) (*corev1.Node, error) {
+// createNode creates a new nodeClaim or machine for the workspace without waiting for it to be ready.
+// The workspace is reconciled again when the status of the nodeClaim or machine changes.
+func (c *WorkspaceReconciler) createNode(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
 	var nodeOSDiskSize string
 	if wObj.Inference != nil && wObj.Inference.Preset != nil && wObj.Inference.Preset.Name != "" {
-		presetName := string(wObj.Inference.Preset.Name)
-		nodeOSDiskSize = plugin.KaitoModelRegister.MustGet(presetName).
-			GetInferenceParameters().DiskStorageRequirement
+		model, err := kaitov1alpha1.GetInferencePresetModel(ctx, c.Client, wObj)
+		if err != nil {
+			return err
+		}
+		nodeOSDiskSize = model.GetInferenceParameters().DiskStorageRequirement
 	}
 	if nodeOSDiskSize == "" {
 		nodeOSDiskSize = "0" // The default OS size is used
 	}
 
 	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
-		return c.CreateNodeClaim(ctx, wObj, nodeOSDiskSize)
+		return c.createNodeClaim(ctx, wObj, nodeOSDiskSize)
 	} else {
-		return c.CreateMachine(ctx, wObj, nodeOSDiskSize)
+		return c.createMachine(ctx, wObj, nodeOSDiskSize)
 	}
 }
 
-func (c *WorkspaceReconciler) CreateMachine(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) (*corev1.Node, error) {
+func (c *WorkspaceReconciler) createMachine(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) error {
 RetryWithDifferentName:
 	newMachine := machine.GenerateMachineManifest(ctx, nodeOSDiskSize, wObj)
 
-	if err := machine.CreateMachine(ctx, newMachine, c.Client); err != nil {
+	klog.InfoS("CreateMachine", "machine", klog.KObj(newMachine))
+	if err := c.Client.Create(ctx, newMachine, &client.CreateOptions{}); err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			klog.InfoS("A machine exists with the same name, retry with a different name", "machine", klog.KObj(newMachine))
 			goto RetryWithDifferentName
-		} else {
-
-			klog.ErrorS(err, "failed to create machine", "machine", newMachine.Name)
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
-				"machineFailedCreation", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return nil, updateErr
-			}
-			return nil, err
 		}
-	}
 
-	// check machine status until it is ready
-	err := machine.CheckMachineStatus(ctx, newMachine, c.Client)
-	if err != nil {
+		klog.ErrorS(err, "failed to create machine", "machine", newMachine.Name)
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
-			"checkMachineStatusFailed", err.Error()); updateErr != nil {
+			"machineFailedCreation", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return nil, updateErr
+			return updateErr
 		}
-		return nil, err
+		return err
 	}
-
-	// get the node object from the machine status nodeName.
-	return resources.GetNode(ctx, newMachine.Status.NodeName, c.Client)
+	return nil
 }
 
-func (c *WorkspaceReconciler) CreateNodeClaim(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) (*corev1.Node, error) {
+func (c *WorkspaceReconciler) createNodeClaim(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) error {
+	if err := nodeclaim.CheckNodeClass(ctx, c.Client); err != nil {
+		return err
+	}
+
 RetryWithDifferentName:
 	newNodeClaim := nodeclaim.GenerateNodeClaimManifest(ctx, nodeOSDiskSize, wObj)
 
-	if err := nodeclaim.CreateNodeClaim(ctx, newNodeClaim, c.Client); err != nil {
+	klog.InfoS("CreateNodeClaim", "nodeClaim", klog.KObj(newNodeClaim))
+	if err := c.Client.Create(ctx, newNodeClaim, &client.CreateOptions{}); err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			klog.InfoS("There exists a nodeClaim with the same name, retry with a different name", "nodeClaim", klog.KObj(newNodeClaim))
 			goto RetryWithDifferentName
-		} else {
-
-			klog.ErrorS(err, "failed to create nodeClaim", "nodeClaim", newNodeClaim.Name)
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
-				"nodeClaimFailedCreation", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return nil, updateErr
-			}
-			return nil, err
 		}
-	}
 
-	// check nodeClaim status until it is ready
-	err := nodeclaim.CheckNodeClaimStatus(ctx, newNodeClaim, c.Client)
-	if err != nil {
+		klog.ErrorS(err, "failed to create nodeClaim", "nodeClaim", newNodeClaim.Name)
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
-			"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
+			"nodeClaimFailedCreation", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return nil, updateErr
+			return updateErr
 		}
-		return nil, err
+		return err
 	}
-
-	// get the node object from the nodeClaim status nodeName.
-	return resources.GetNode(ctx, newNodeClaim.Status.NodeName, c.Client)
+	return nil
 }
 
-// ensureNodePlugins ensures node plugins are installed.
-func (c *WorkspaceReconciler) ensureNodePlugins(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeObj *corev1.Node) error {
-	timeClock := clock.RealClock{}
-	tick := timeClock.NewTicker(consts.NodePluginInstallTimeout)
-	defer tick.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-tick.C():
-			return fmt.Errorf("node plugin installation timed out. node %s is not ready", nodeObj.Name)
-		default:
-			//Nvidia Plugin
-			if found := resources.CheckNvidiaPlugin(ctx, nodeObj); !found {
-				if err := resources.UpdateNodeWithLabel(ctx, nodeObj.Name, resources.LabelKeyNvidia, resources.LabelValueNvidia, c.Client); err != nil {
-					if apierrors.IsNotFound(err) {
-						klog.ErrorS(err, "nvidia plugin cannot be installed, node not found", "node", nodeObj.Name)
-						if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
-							if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
-								"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
-								klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-								return updateErr
-							}
-						} else {
-							if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
-								"checkMachineStatusFailed", err.Error()); updateErr != nil {
-								klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-								return updateErr
-							}
-						}
-						return err
-					}
+// ensureNodePlugins ensures node plugins are installed. It returns false if the plugins are not ready on the node yet.
+func (c *WorkspaceReconciler) ensureNodePlugins(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeObj *corev1.Node) (bool, error) {
+	gpuVendor := utils.GetSKUGPUVendor(ctx, c.Client, nil, kaitov1alpha1.GetWorkspaceInstanceType(wObj))
+	if gpuVendor != sku.GPUVendorNvidia {
+		return resources.CheckGPUCapacity(nodeObj, sku.GetGPUVendorConfig(gpuVendor).ResourceName), nil
+	}
+
+	//Nvidia Plugin
+	if found := resources.CheckNvidiaPlugin(ctx, nodeObj); found {
+		return true, nil
+	}
+	if nodeObj.Labels[resources.LabelKeyNvidia] == resources.LabelValueNvidia {
+		// The node is labeled, wait for the device plugin to report the GPU capacity.
+		return false, nil
+	}
+	if err := resources.UpdateNodeWithLabel(ctx, nodeObj.Name, resources.LabelKeyNvidia, resources.LabelValueNvidia, c.Client); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "nvidia plugin cannot be installed, node not found", "node", nodeObj.Name)
+			if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
+					"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
+					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+					return false, updateErr
+				}
+			} else {
+				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
+					"checkMachineStatusFailed", err.Error()); updateErr != nil {
+					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+					return false, updateErr
 				}
-				time.Sleep(1 * time.Second)
 			}
-			return nil
 		}
+		return false, err
 	}
+	return false, nil
 }
 
 // getPresetName returns the preset name from wObj if available
@@ -632,12 +827,23 @@ func (c *WorkspaceReconciler) ensureService(ctx context.Context, wObj *kaitov1al
 	}
 
 	supportsDistributedInference := false
+	targetPort := int32(model.DefaultRuntimePort)
 	if presetName := getPresetName(wObj); presetName != "" {
-		model := plugin.KaitoModelRegister.MustGet(presetName)
+		model := plugin.KaitoModelRegister.Get(presetName)
+		if model == nil {
+			return fmt.Errorf("preset %s is not registered", presetName)
+		}
 		supportsDistributedInference = model.SupportDistributedInference()
+		if wObj.Inference != nil {
+			runtime, err := inference.GetWorkspaceRuntime(wObj)
+			if err != nil {
+				return err
+			}
+			targetPort = runtime.Port()
+		}
 	}
 
-	serviceObj := manifests.GenerateServiceManifest(ctx, wObj, serviceType, supportsDistributedInference)
+	serviceObj := manifests.GenerateServiceManifest(ctx, wObj, serviceType, supportsDistributedInference, targetPort)
 	if err := resources.CreateResource(ctx, serviceObj, c.Client); err != nil {
 		return err
 	}
@@ -652,48 +858,46 @@ func (c *WorkspaceReconciler) ensureService(ctx context.Context, wObj *kaitov1al
 	return nil
 }
 
-func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+// applyTuning applies tuning spec. A non-zero result is returned when the tuning job has not started yet.
+func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
 	var err error
+	var workloadObj client.Object
+	var readinessTimeout time.Duration
 	func() {
 		if wObj.Tuning.Preset != nil {
 			presetName := string(wObj.Tuning.Preset.Name)
-			model := plugin.KaitoModelRegister.MustGet(presetName)
+			model := plugin.KaitoModelRegister.Get(presetName)
+			if model == nil {
+				err = fmt.Errorf("preset %s is not registered", presetName)
+				return
+			}
 
 			tuningParam := model.GetTuningParameters()
+			readinessTimeout = tuningParam.ReadinessTimeout
 			existingObj := &batchv1.Job{}
 			revisionNum := wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation]
 			if err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingObj); err == nil {
 				klog.InfoS("A tuning workload already exists for workspace", "workspace", klog.KObj(wObj))
+				workloadObj = existingObj
 
 				if existingObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] != revisionNum {
 					deletePolicy := metav1.DeletePropagationForeground
-					if err := c.Delete(ctx, existingObj, &client.DeleteOptions{
+					if err = c.Delete(ctx, existingObj, &client.DeleteOptions{
 						PropagationPolicy: &deletePolicy,
 					}); err != nil {
 						return
 					}
-
-					var workloadObj client.Object
-					workloadObj, err = tuning.CreatePresetTuning(ctx, wObj, revisionNum, tuningParam, c.Client)
-					if err != nil {
+					if err = c.startWorkloadDeployment(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus); err != nil {
 						return
 					}
-					existingObj = workloadObj.(*batchv1.Job)
-				}
-
-				if err = resources.CheckResourceStatus(existingObj, c.Client, tuningParam.ReadinessTimeout); err != nil {
-					return
+					workloadObj, err = tuning.CreatePresetTuning(ctx, wObj, revisionNum, tuningParam, c.Client)
 				}
 			} else if apierrors.IsNotFound(err) {
-				var workloadObj client.Object
 				// Need to create a new workload
-				workloadObj, err = tuning.CreatePresetTuning(ctx, wObj, revisionNum, tuningParam, c.Client)
-				if err != nil {
-					return
-				}
-				if err = resources.CheckResourceStatus(workloadObj, c.Client, tuningParam.ReadinessTimeout); err != nil {
+				if err = c.startWorkloadDeployment(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus); err != nil {
 					return
 				}
+				workloadObj, err = tuning.CreatePresetTuning(ctx, wObj, revisionNum, tuningParam, c.Client)
 			}
 		}
 	}()
@@ -702,99 +906,76 @@ func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1alph
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus, metav1.ConditionFalse,
 			"WorkspaceTuningJobStatusFailed", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return updateErr
+			return reconcile.Result{}, updateErr
 		}
-		return err
+		return reconcile.Result{}, err
 	}
-
-	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus, metav1.ConditionTrue,
-		"WorkspaceTuningJobStatusStarted", "Tuning job has started"); err != nil {
-		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
-		return err
+	if workloadObj == nil {
+		return reconcile.Result{}, nil
 	}
 
-	return nil
+	return c.checkWorkloadReadiness(ctx, wObj, workloadObj, readinessTimeout, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus,
+		"WorkspaceTuningJobStatusStarted", "Tuning job has started", "WorkspaceTuningJobStatusFailed")
 }
 
-// applyInference applies inference spec.
-func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+// applyInference applies inference spec. A non-zero result is returned when the inference workload is not ready yet.
+func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
 	var err error
+	var workloadObj client.Object
+	var readinessTimeout time.Duration
 	func() {
+		revisionStr := wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation]
+		var desiredObj, existingObj client.Object
 		if wObj.Inference.Template != nil {
-			var workloadObj client.Object
-			// TODO: handle update
-			workloadObj, err = inference.CreateTemplateInference(ctx, wObj, c.Client)
-			if err != nil {
+			readinessTimeout = templateInferenceReadinessTimeout
+			existingObj = &appsv1.Deployment{}
+			if desiredObj, err = inference.GenerateTemplateInference(ctx, wObj, revisionStr); err != nil {
 				return
 			}
-			if err = resources.CheckResourceStatus(workloadObj, c.Client, time.Duration(10)*time.Minute); err != nil {
+		} else if wObj.Inference != nil && wObj.Inference.Preset != nil {
+			model, modelErr := kaitov1alpha1.GetInferencePresetModel(ctx, c.Client, wObj)
+			if modelErr != nil {
+				err = modelErr
 				return
 			}
-		} else if wObj.Inference != nil && wObj.Inference.Preset != nil {
-			presetName := string(wObj.Inference.Preset.Name)
-			model := plugin.KaitoModelRegister.MustGet(presetName)
 
 			inferenceParam := model.GetInferenceParameters()
+			readinessTimeout = inferenceParam.ReadinessTimeout
 
-			var existingObj client.Object
 			if model.SupportDistributedInference() {
 				existingObj = &appsv1.StatefulSet{}
 			} else {
 				existingObj = &appsv1.Deployment{}
+			}
+			if desiredObj, err = inference.GeneratePresetInference(ctx, wObj, revisionStr, model, c.Client); err != nil {
+				return
+			}
+		} else {
+			return
+		}
 
+		if err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingObj); err == nil {
+			workloadObj = existingObj
+			// Compare the rendered workload against the live object and the last applied configuration so that
+			// spec changes are rolled out and manual drift on the managed fields is reverted.
+			var patch client.Patch
+			if patch, err = resources.CreateThreeWayPatch(existingObj, desiredObj); err != nil || patch == nil {
+				return
 			}
-			revisionStr := wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation]
-			if err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingObj); err == nil {
-				klog.InfoS("An inference workload already exists for workspace", "workspace", klog.KObj(wObj))
-				if !model.SupportDistributedInference() {
-					deployment := existingObj.(*appsv1.Deployment)
-					if deployment.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] != revisionStr {
-						var volumes []corev1.Volume
-						var volumeMounts []corev1.VolumeMount
-						shmVolume, shmVolumeMount := utils.ConfigSHMVolume(*wObj.Resource.Count)
-						if shmVolume.Name != "" {
-							volumes = append(volumes, shmVolume)
-						}
-						if shmVolumeMount.Name != "" {
-							volumeMounts = append(volumeMounts, shmVolumeMount)
-						}
-
-						if len(wObj.Inference.Adapters) > 0 {
-							adapterVolume, adapterVolumeMount := utils.ConfigAdapterVolume()
-							volumes = append(volumes, adapterVolume)
-							volumeMounts = append(volumeMounts, adapterVolumeMount)
-						}
-						initContainers, envs := manifests.GenerateInitContainers(wObj, volumeMounts)
-						spec := &deployment.Spec
-
-						spec.Template.Spec.InitContainers = initContainers
-						spec.Template.Spec.Containers[0].Env = envs
-						spec.Template.Spec.Containers[0].VolumeMounts = volumeMounts
-						deployment.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] = revisionStr
-						spec.Template.Spec.Volumes = volumes
-
-						_, imagePullSecrets := inference.GetInferenceImageInfo(ctx, wObj, inferenceParam)
-						deployment.Spec.Template.Spec.ImagePullSecrets = imagePullSecrets
-
-						if err := c.Update(ctx, deployment); err != nil {
-							return
-						}
-					}
-				}
-				if err = resources.CheckResourceStatus(existingObj, c.Client, inferenceParam.ReadinessTimeout); err != nil {
-					return
-				}
-			} else if apierrors.IsNotFound(err) {
-				var workloadObj client.Object
-				// Need to create a new workload
-				workloadObj, err = inference.CreatePresetInference(ctx, wObj, revisionStr, model, c.Client)
-				if err != nil {
-					return
-				}
-				if err = resources.CheckResourceStatus(workloadObj, c.Client, inferenceParam.ReadinessTimeout); err != nil {
-					return
-				}
+			klog.InfoS("Updating the inference workload", "workspace", klog.KObj(wObj), "revision", revisionStr)
+			if err = c.startWorkloadDeployment(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus); err != nil {
+				return
+			}
+			err = c.Patch(ctx, existingObj, patch)
+		} else if apierrors.IsNotFound(err) {
+			// Need to create a new workload
+			if err = c.startWorkloadDeployment(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus); err != nil {
+				return
+			}
+			if err = resources.CreateResource(ctx, desiredObj, c.Client); err != nil && apierrors.IsAlreadyExists(err) {
+				err = nil
 			}
+			workloadObj = desiredObj
 		}
 	}()
 
@@ -802,20 +983,86 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1a
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse,
 			"WorkspaceInferenceStatusFailed", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return updateErr
+			return reconcile.Result{}, updateErr
 		} else {
-			return err
+			return reconcile.Result{}, err
 		}
 	}
+	if workloadObj == nil {
+		return reconcile.Result{}, nil
+	}
+	if err = c.updateStatusInferenceIfNotMatch(ctx, wObj, workloadObj); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, err
+	}
 
-	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionTrue,
-		"WorkspaceInferenceStatusSuccess", "Inference has been deployed successfully"); err != nil {
+	return c.checkWorkloadReadiness(ctx, wObj, workloadObj, readinessTimeout, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus,
+		"WorkspaceInferenceStatusSuccess", "Inference has been deployed successfully", "WorkspaceInferenceStatusFailed")
+}
+
+// startWorkloadDeployment moves the workspace to the Deploying phase and resets the workload condition to Unknown.
+// The transition time of the condition is used as the start of the readiness timeout.
+func (c *WorkspaceReconciler) startWorkloadDeployment(ctx context.Context, wObj *kaitov1alpha1.Workspace, cType kaitov1alpha1.ConditionType) error {
+	if err := c.updateStatusPhaseIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspacePhaseDeploying); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, cType, metav1.ConditionUnknown,
+		workloadPendingReason, "workload is being deployed"); err != nil {
 		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 		return err
 	}
 	return nil
 }
 
+// checkWorkloadReadiness checks the workload status once and updates the workload condition accordingly.
+// If the workload is not ready yet, a non-zero result is returned to check it again before the readiness timeout,
+// which is measured from the last time the condition became Unknown, is reached.
+func (c *WorkspaceReconciler) checkWorkloadReadiness(ctx context.Context, wObj *kaitov1alpha1.Workspace, workloadObj client.Object,
+	readinessTimeout time.Duration, cType kaitov1alpha1.ConditionType, successReason, successMessage, failedReason string) (reconcile.Result, error) {
+	ready, err := resources.IsResourceReady(workloadObj)
+	if err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, cType, metav1.ConditionFalse, failedReason, err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+	if ready {
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, cType, metav1.ConditionTrue, successReason, successMessage); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	condition := meta.FindStatusCondition(wObj.Status.Conditions, string(cType))
+	if condition != nil && condition.Status == metav1.ConditionFalse && condition.Reason == workloadTimeoutReason {
+		// The workload already timed out, it stays failed until it becomes ready or the workspace is updated.
+		return reconcile.Result{}, fmt.Errorf("%s", condition.Message)
+	}
+	if condition == nil || condition.Status != metav1.ConditionUnknown {
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, cType, metav1.ConditionUnknown,
+			workloadPendingReason, "waiting for workload to be ready"); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: min(readinessTimeout, workloadReadinessRequeueInterval)}, nil
+	}
+
+	elapsed := time.Since(condition.LastTransitionTime.Time)
+	if elapsed >= readinessTimeout {
+		timeoutErr := fmt.Errorf("workload %s is not ready after %s", workloadObj.GetName(), readinessTimeout)
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, cType, metav1.ConditionFalse,
+			workloadTimeoutReason, timeoutErr.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, timeoutErr
+	}
+	return reconcile.Result{RequeueAfter: min(readinessTimeout-elapsed, workloadReadinessRequeueInterval)}, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	c.Recorder = mgr.GetEventRecorderFor("Workspace")
@@ -835,6 +1082,10 @@ func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&batchv1.Job{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 5})
 
+	// On-prem clusters may not have the nodeClaim and machine CRDs.
+	if !utils.IsNodeProvisioningEnabled() {
+		return builder.Complete(c)
+	}
 	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
 		builder.Watches(&v1beta1.NodeClaim{}, c.watchNodeClaims()) // watches for nodeClaim with labels indicating workspace name.
 	} else {
@@ -856,14 +1107,6 @@ func (c *WorkspaceReconciler) watchMachines() handler.EventHandler {
 			if !ok {
 				return nil
 			}
-			_, conditionFound := lo.Find(machineObj.GetConditions(), func(condition apis.Condition) bool {
-				return condition.Type == apis.ConditionReady &&
-					condition.Status == v1.ConditionTrue
-			})
-			if conditionFound && machineObj.DeletionTimestamp.IsZero() {
-				// No need to reconcile workspace if the machine is in READY state unless machine is deleted.
-				return nil
-			}
 			return []reconcile.Request{
 				{
 					NamespacedName: client.ObjectKey{
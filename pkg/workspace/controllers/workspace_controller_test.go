@@ -11,6 +11,7 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,7 +23,10 @@ import (
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
@@ -30,7 +34,6 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
@@ -321,43 +324,34 @@ func TestSelectWorkspaceNodes(t *testing.T) {
 	}
 }
 
-func TestCreateAndValidateMachineNode(t *testing.T) {
+func TestCreateNode(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
 		callMocks             func(c *test.MockClient)
-		objectConditions      apis.Conditions
 		workspace             v1alpha1.Workspace
 		karpenterFeatureGates bool
 		expectedError         error
 	}{
-		"Node is not created because machine creation fails": {
+		"Machine creation fails": {
 			callMocks: func(c *test.MockClient) {
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(errors.New("failed to create machine"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
-			objectConditions: apis.Conditions{
-				{
-					Type:    v1alpha5.MachineLaunched,
-					Status:  corev1.ConditionFalse,
-					Message: consts.ErrorInstanceTypesUnavailable,
-				},
-			},
 			workspace:     *test.MockWorkspaceWithPreset,
-			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
+			expectedError: errors.New("failed to create machine"),
 		},
 		"A machine is successfully created": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
 			},
-			objectConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
+			workspace:     *test.MockWorkspaceDistributedModel,
+			expectedError: nil,
+		},
+		"A machine is created with a different name if the name already exists": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(test.IsAlreadyExistsError()).Once()
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceDistributedModel,
 			expectedError: nil,
@@ -365,82 +359,41 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 		"An Azure nodeClaim is successfully created": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
 				os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 			},
-			objectConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
-			},
 			workspace:             *test.MockWorkspaceDistributedModel,
 			karpenterFeatureGates: true,
 			expectedError:         nil,
 		},
 		"An AWS nodeClaim is successfully created": {
 			callMocks: func(c *test.MockClient) {
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&awsv1beta1.EC2NodeClass{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&awsv1beta1.EC2NodeClass{}), mock.Anything).Return(test.NotFoundError())
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&awsv1beta1.EC2NodeClass{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
 				os.Setenv("CLOUD_PROVIDER", "aws")
 			},
-			objectConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
-			},
 			workspace:             *test.MockWorkspaceDistributedModel,
 			karpenterFeatureGates: true,
 			expectedError:         nil,
 		},
-		"Node is not created because nodeClaim creation fails": {
+		"NodeClaim creation fails": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(errors.New("failed to create nodeClaim"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 			},
-			objectConditions: apis.Conditions{
-				{
-					Type:    v1beta1.Launched,
-					Status:  corev1.ConditionFalse,
-					Message: consts.ErrorInstanceTypesUnavailable,
-				},
-			},
 			workspace:             *test.MockWorkspaceWithPreset,
 			karpenterFeatureGates: true,
-			expectedError:         errors.New(consts.ErrorInstanceTypesUnavailable),
+			expectedError:         errors.New("failed to create nodeClaim"),
 		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
 			mockClient := test.NewClient()
-			mockMachine := &v1alpha5.Machine{}
-			mockNodeClaim := &v1beta1.NodeClaim{}
-
-			mockClient.UpdateCb = func(key types.NamespacedName) {
-				mockClient.GetObjectFromMap(mockMachine, key)
-				mockMachine.Status.Conditions = tc.objectConditions
-				mockClient.CreateOrUpdateObjectInMap(mockMachine)
-
-				if tc.karpenterFeatureGates {
-					mockClient.GetObjectFromMap(mockNodeClaim, key)
-					mockNodeClaim.Status.Conditions = tc.objectConditions
-					mockClient.CreateOrUpdateObjectInMap(mockNodeClaim)
-				}
-			}
-
 			tc.callMocks(mockClient)
 
 			reconciler := &WorkspaceReconciler{
@@ -450,92 +403,9 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 			ctx := context.Background()
 			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = tc.karpenterFeatureGates
 
-			node, err := reconciler.createAndValidateNode(ctx, &tc.workspace)
+			err := reconciler.createNode(ctx, &tc.workspace)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
-				assert.Check(t, node != nil, "Response node should not be nil")
-			} else {
-				assert.Equal(t, tc.expectedError.Error(), err.Error())
-			}
-		})
-	}
-}
-
-func TestCreateAndValidateNodeClaimNode(t *testing.T) {
-	test.RegisterTestModel()
-	testcases := map[string]struct {
-		callMocks             func(c *test.MockClient)
-		karpenterFeatureGates bool
-		nodeClaimConditions   apis.Conditions
-		workspace             v1alpha1.Workspace
-		expectedError         error
-	}{
-		"Node is not created because nodeClaim creation fails": {
-			callMocks: func(c *test.MockClient) {
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
-				os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
-			},
-			karpenterFeatureGates: true,
-			nodeClaimConditions: apis.Conditions{
-				{
-					Type:    v1beta1.Launched,
-					Status:  corev1.ConditionFalse,
-					Message: consts.ErrorInstanceTypesUnavailable,
-				},
-			},
-			workspace:     *test.MockWorkspaceWithPreset,
-			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
-		},
-		"A nodeClaim is successfully created": {
-			callMocks: func(c *test.MockClient) {
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
-			},
-			karpenterFeatureGates: true,
-			nodeClaimConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
-			},
-			workspace:     *test.MockWorkspaceDistributedModel,
-			expectedError: nil,
-		},
-	}
-
-	for k, tc := range testcases {
-		t.Run(k, func(t *testing.T) {
-			mockClient := test.NewClient()
-			mockNodeClaim := &v1beta1.NodeClaim{}
-
-			mockClient.UpdateCb = func(key types.NamespacedName) {
-				mockClient.GetObjectFromMap(mockNodeClaim, key)
-				mockNodeClaim.Status.Conditions = tc.nodeClaimConditions
-				mockClient.CreateOrUpdateObjectInMap(mockNodeClaim)
-			}
-
-			tc.callMocks(mockClient)
-
-			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = tc.karpenterFeatureGates
-
-			reconciler := &WorkspaceReconciler{
-				Client: mockClient,
-				Scheme: test.NewTestScheme(),
-			}
-			ctx := context.Background()
-
-			node, err := reconciler.createAndValidateNode(ctx, &tc.workspace)
-			if tc.expectedError == nil {
-				assert.Check(t, err == nil, "Not expected to return error")
-				assert.Check(t, node != nil, "Response node should not be nil")
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
@@ -608,12 +478,14 @@ func TestEnsureService(t *testing.T) {
 func TestApplyInferenceWithPreset(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
-		callMocks     func(c *test.MockClient)
-		workspace     v1alpha1.Workspace
-		expectedError error
+		callMocks       func(c *test.MockClient)
+		workspace       v1alpha1.Workspace
+		expectedError   error
+		expectedRequeue bool
 	}{
 		"Fail to get inference because associated workload with workspace cannot be retrieved": {
 			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(errors.New("Failed to get resource"))
 
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
@@ -639,20 +511,20 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
-			workspace:     *test.MockWorkspaceWithPreset,
-			expectedError: nil,
+			workspace:       *test.MockWorkspaceWithPreset,
+			expectedError:   nil,
+			expectedRequeue: true,
 		},
 		"Apply inference from existing workload": {
 			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
 				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
-					depObj := &appsv1.StatefulSet{}
-					key := client.ObjectKey{Namespace: "kaito", Name: "testWorkspace"}
-					c.GetObjectFromMap(depObj, key)
+					ss := args.Get(2).(*appsv1.StatefulSet)
 					numRep := int32(1)
-					depObj.Status.ReadyReplicas = numRep
-					depObj.Spec.Replicas = &numRep
-					c.CreateOrUpdateObjectInMap(depObj)
+					ss.Status.ReadyReplicas = numRep
+					ss.Spec.Replicas = &numRep
 				})
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything, mock.Anything).Return(nil)
 
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
@@ -671,7 +543,7 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 					}).
 					Return(nil)
 
-				c.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything).Return(nil)
 
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
@@ -679,6 +551,54 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 			workspace:     *test.MockWorkspaceWithPreset,
 			expectedError: nil,
 		},
+		"Keep existing workload untouched when it matches the rendered manifest": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					dep := args.Get(2).(*appsv1.Deployment)
+					desired, err := inference.GeneratePresetInference(context.Background(), test.MockWorkspaceWithPreset,
+						test.MockWorkspaceWithPreset.Annotations[v1alpha1.WorkspaceRevisionAnnotation],
+						plugin.KaitoModelRegister.MustGet("test-model"), c)
+					if err != nil {
+						panic(err)
+					}
+					*dep = *desired.(*appsv1.Deployment)
+					dep.Status.ReadyReplicas = 1
+				})
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:     *test.MockWorkspaceWithPreset,
+			expectedError: nil,
+		},
+		"Inference workload is not ready after the readiness timeout": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					ss := args.Get(2).(*appsv1.StatefulSet)
+					numRep := int32(1)
+					ss.Name = "testWorkspace"
+					ss.Spec.Replicas = &numRep
+				})
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything, mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace: func() v1alpha1.Workspace {
+				w := test.MockWorkspaceDistributedModel.DeepCopy()
+				w.Status.Conditions = []v1.Condition{
+					{
+						Type:               string(v1alpha1.WorkspaceConditionTypeInferenceStatus),
+						Status:             v1.ConditionUnknown,
+						Reason:             workloadPendingReason,
+						LastTransitionTime: v1.NewTime(time.Now().Add(-time.Hour)),
+					},
+				}
+				return *w
+			}(),
+			expectedError: errors.New("workload testWorkspace is not ready after 30m0s"),
+		},
 	}
 
 	for k, tc := range testcases {
@@ -692,9 +612,10 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 			}
 			ctx := context.Background()
 
-			err := reconciler.applyInference(ctx, &tc.workspace)
+			result, err := reconciler.applyInference(ctx, &tc.workspace)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
+				assert.Equal(t, tc.expectedRequeue, !result.IsZero())
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
@@ -704,12 +625,14 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 
 func TestApplyInferenceWithTemplate(t *testing.T) {
 	testcases := map[string]struct {
-		callMocks     func(c *test.MockClient)
-		workspace     v1alpha1.Workspace
-		expectedError error
+		callMocks       func(c *test.MockClient)
+		workspace       v1alpha1.Workspace
+		expectedError   error
+		expectedRequeue bool
 	}{
 		"Fail to apply inference from workspace template": {
 			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(test.NotFoundError())
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(errors.New("Failed to create deployment"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
@@ -717,29 +640,67 @@ func TestApplyInferenceWithTemplate(t *testing.T) {
 			workspace:     *test.MockWorkspaceWithInferenceTemplate,
 			expectedError: errors.New("Failed to create deployment"),
 		},
-		"Apply inference from workspace template": {
+		"Create inference from workspace template": {
 			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(test.NotFoundError())
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
-				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:       *test.MockWorkspaceWithInferenceTemplate,
+			expectedError:   nil,
+			expectedRequeue: true,
+		},
+		"Apply inference from existing workspace template deployment": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					dep := args.Get(2).(*appsv1.Deployment)
+					desired, err := inference.GenerateTemplateInference(context.Background(), test.MockWorkspaceWithInferenceTemplate, "")
+					if err != nil {
+						panic(err)
+					}
+					*dep = *desired.(*appsv1.Deployment)
+					dep.Status.ReadyReplicas = 1
+				})
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceWithInferenceTemplate,
 			expectedError: nil,
 		},
+		"Update existing workspace template deployment when the template changes": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					dep := args.Get(2).(*appsv1.Deployment)
+					w := test.MockWorkspaceWithInferenceTemplate.DeepCopy()
+					w.Inference.Template.Spec.Containers = []corev1.Container{{Name: "inference", Image: "nginx:1.0"}}
+					w.Resource.Count = lo.ToPtr(2)
+					desired, err := inference.GenerateTemplateInference(context.Background(), w, "")
+					if err != nil {
+						panic(err)
+					}
+					*dep = *desired.(*appsv1.Deployment)
+				})
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.MatchedBy(func(patch client.Patch) bool {
+					data, _ := patch.Data(nil)
+					return strings.Contains(string(data), `"replicas":1`) && strings.Contains(string(data), `"image":"nginx:1.1"`)
+				}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace: func() v1alpha1.Workspace {
+				w := test.MockWorkspaceWithInferenceTemplate.DeepCopy()
+				w.Inference.Template.Spec.Containers = []corev1.Container{{Name: "inference", Image: "nginx:1.1"}}
+				return *w
+			}(),
+			expectedError:   nil,
+			expectedRequeue: true,
+		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
 			mockClient := test.NewClient()
-			depObj := &appsv1.Deployment{}
-
-			mockClient.UpdateCb = func(key types.NamespacedName) {
-				mockClient.GetObjectFromMap(depObj, key)
-				depObj.Status.ReadyReplicas = 1
-				mockClient.CreateOrUpdateObjectInMap(depObj)
-			}
-
 			tc.callMocks(mockClient)
 
 			reconciler := &WorkspaceReconciler{
@@ -748,9 +709,10 @@ func TestApplyInferenceWithTemplate(t *testing.T) {
 			}
 			ctx := context.Background()
 
-			err := reconciler.applyInference(ctx, &tc.workspace)
+			result, err := reconciler.applyInference(ctx, &tc.workspace)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
+				assert.Equal(t, tc.expectedRequeue, !result.IsZero())
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
@@ -907,10 +869,18 @@ func TestGetAllQualifiedNodes(t *testing.T) {
 
 func TestApplyWorkspaceResource(t *testing.T) {
 	test.RegisterTestModel()
+	readyConditions := apis.Conditions{
+		{
+			Type:   apis.ConditionReady,
+			Status: corev1.ConditionTrue,
+		},
+	}
 	testcases := map[string]struct {
 		callMocks                   func(c *test.MockClient)
 		karpenterFeatureGateEnabled bool
+		cloudProvider               string
 		expectedError               error
+		expectedRequeue             bool
 		workspace                   v1alpha1.Workspace
 	}{
 		"Fail to apply workspace because associated machines cannot be retrieved": {
@@ -922,21 +892,12 @@ func TestApplyWorkspaceResource(t *testing.T) {
 		},
 		"Fail to apply workspace because can't get qualified nodes": {
 			callMocks: func(c *test.MockClient) {
-				machineList := test.MockMachineList
-				relevantMap := c.CreateMapWithType(machineList)
-				c.CreateOrUpdateObjectInMap(&test.MockMachine)
-
-				//insert machine objects into the map
-				for _, obj := range machineList.Items {
-					m := obj
-					objKey := client.ObjectKeyFromObject(&m)
-
-					relevantMap[objKey] = &m
-				}
+				relevantMap := c.CreateMapWithType(test.MockMachineList)
+				machineObj := test.MockMachine.DeepCopy()
+				machineObj.Status.Conditions = readyConditions
+				relevantMap[client.ObjectKeyFromObject(machineObj)] = machineObj
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(errors.New("failed to list nodes"))
 			},
 			workspace:     *test.MockWorkspaceDistributedModel,
@@ -944,9 +905,7 @@ func TestApplyWorkspaceResource(t *testing.T) {
 		},
 		"Fail to apply workspace because associated nodeClaim cannot be retrieved": {
 			callMocks: func(c *test.MockClient) {
-				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(errors.New("failed to retrieve nodeClaims"))
-
 			},
 			karpenterFeatureGateEnabled: true,
 			workspace:                   *test.MockWorkspaceDistributedModel,
@@ -954,26 +913,143 @@ func TestApplyWorkspaceResource(t *testing.T) {
 		},
 		"Fail to apply workspace with nodeClaims because can't get qualified nodes": {
 			callMocks: func(c *test.MockClient) {
-				nodeClaimList := test.MockNodeClaimList
-				relevantMap := c.CreateMapWithType(nodeClaimList)
-				c.CreateOrUpdateObjectInMap(&test.MockNodeClaim)
+				relevantMap := c.CreateMapWithType(test.MockNodeClaimList)
+				nodeClaimObj := test.MockNodeClaim.DeepCopy()
+				nodeClaimObj.Status.Conditions = readyConditions
+				relevantMap[client.ObjectKeyFromObject(nodeClaimObj)] = nodeClaimObj
 
-				//insert nodeClaim objects into the map
-				for _, obj := range nodeClaimList.Items {
-					m := obj
-					objKey := client.ObjectKeyFromObject(&m)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(errors.New("failed to list nodes"))
+			},
+			karpenterFeatureGateEnabled: true,
+			workspace:                   *test.MockWorkspaceDistributedModel,
+			expectedError:               errors.New("failed to list nodes"),
+		},
+		"Fail to apply workspace because the instance type of a pending nodeClaim is unavailable": {
+			callMocks: func(c *test.MockClient) {
+				relevantMap := c.CreateMapWithType(test.MockNodeClaimList)
+				nodeClaimObj := test.MockNodeClaim.DeepCopy()
+				nodeClaimObj.Status.Conditions = apis.Conditions{
+					{
+						Type:    v1beta1.Launched,
+						Status:  corev1.ConditionFalse,
+						Message: consts.ErrorInstanceTypesUnavailable,
+					},
+				}
+				relevantMap[client.ObjectKeyFromObject(nodeClaimObj)] = nodeClaimObj
 
-					relevantMap[objKey] = &m
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+			},
+			karpenterFeatureGateEnabled: true,
+			workspace:                   *test.MockWorkspaceDistributedModel,
+			expectedError:               errors.New(consts.ErrorInstanceTypesUnavailable),
+		},
+		"Requeue workspace while a nodeClaim is being provisioned": {
+			callMocks: func(c *test.MockClient) {
+				relevantMap := c.CreateMapWithType(test.MockNodeClaimList)
+				nodeClaimObj := test.MockNodeClaim.DeepCopy()
+				relevantMap[client.ObjectKeyFromObject(nodeClaimObj)] = nodeClaimObj
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			karpenterFeatureGateEnabled: true,
+			workspace:                   *test.MockWorkspaceDistributedModel,
+			expectedRequeue:             true,
+		},
+		"Create a nodeClaim without waiting for it when there are not enough nodes": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.GPUQuotaList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+			},
+			karpenterFeatureGateEnabled: true,
+			workspace:                   *test.MockWorkspaceDistributedModel,
+			expectedRequeue:             true,
+		},
+		"Create a nodeClaim when the nodes fit in the GPU quota of the namespace": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateMapWithType(&v1alpha1.GPUQuotaList{})[client.ObjectKey{Name: "team-quota"}] = &v1alpha1.GPUQuota{
+					ObjectMeta: v1.ObjectMeta{Name: "team-quota"},
+					Spec:       v1alpha1.GPUQuotaSpec{Namespaces: []string{"kaito"}, MaxGPUs: lo.ToPtr(8), MaxNodes: lo.ToPtr(4)},
 				}
-				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.GPUQuotaList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.WorkspaceList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.RAGEngineList{}), mock.Anything).Return(nil)
 
-				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(errors.New("failed to list nodes"))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 			},
 			karpenterFeatureGateEnabled: true,
 			workspace:                   *test.MockWorkspaceDistributedModel,
-			expectedError:               errors.New("failed to list nodes"),
+			expectedRequeue:             true,
+		},
+		"Do not create nodeClaims beyond the GPU quota of the namespace": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateMapWithType(&v1alpha1.GPUQuotaList{})[client.ObjectKey{Name: "team-quota"}] = &v1alpha1.GPUQuota{
+					ObjectMeta: v1.ObjectMeta{Name: "team-quota"},
+					Spec:       v1alpha1.GPUQuotaSpec{Namespaces: []string{"kaito"}, MaxGPUs: lo.ToPtr(3)},
+				}
+				otherWorkspace := test.MockWorkspaceDistributedModel.DeepCopy()
+				otherWorkspace.Name = "otherWorkspace"
+				c.CreateMapWithType(&v1alpha1.WorkspaceList{})[client.ObjectKeyFromObject(otherWorkspace)] = otherWorkspace
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.GPUQuotaList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.WorkspaceList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.RAGEngineList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				os.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+			},
+			karpenterFeatureGateEnabled: true,
+			workspace:                   *test.MockWorkspaceDistributedModel,
+			expectedRequeue:             true,
+		},
+		"Wait for existing nodes without creating nodeClaims on on-prem clusters": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			karpenterFeatureGateEnabled: true,
+			cloudProvider:               consts.OnPremCloudName,
+			workspace:                   *test.MockWorkspaceDistributedModel,
+			expectedRequeue:             true,
+		},
+		"Requeue workspace while GPU plugins are being installed": {
+			callMocks: func(c *test.MockClient) {
+				relevantMap := c.CreateMapWithType(test.MockNodeList)
+				node := test.MockNodeList.Items[0].DeepCopy()
+				node.Status.Capacity = nil
+				relevantMap[client.ObjectKeyFromObject(node)] = node
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:       *test.MockWorkspaceDistributedModel,
+			expectedRequeue: true,
 		},
 		"Successfully apply workspace resource with machine": {
 			callMocks: func(c *test.MockClient) {
@@ -988,7 +1064,6 @@ func TestApplyWorkspaceResource(t *testing.T) {
 				}
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
 
@@ -1011,11 +1086,7 @@ func TestApplyWorkspaceResource(t *testing.T) {
 					relevantMap[objKey] = &n
 				}
 
-				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
 
@@ -1033,28 +1104,8 @@ func TestApplyWorkspaceResource(t *testing.T) {
 		t.Run(k, func(t *testing.T) {
 			mockClient := test.NewClient()
 			tc.callMocks(mockClient)
-
-			mockMachine := &v1alpha5.Machine{}
-			mockNodeClaim := &v1beta1.NodeClaim{}
-
-			mockClient.UpdateCb = func(key types.NamespacedName) {
-				mockClient.GetObjectFromMap(mockMachine, key)
-				mockMachine.Status.Conditions = apis.Conditions{
-					{
-						Type:   apis.ConditionReady,
-						Status: corev1.ConditionTrue,
-					},
-				}
-				mockClient.CreateOrUpdateObjectInMap(mockMachine)
-
-				mockClient.GetObjectFromMap(mockNodeClaim, key)
-				mockNodeClaim.Status.Conditions = apis.Conditions{
-					{
-						Type:   apis.ConditionReady,
-						Status: corev1.ConditionTrue,
-					},
-				}
-				mockClient.CreateOrUpdateObjectInMap(mockNodeClaim)
+			if tc.cloudProvider != "" {
+				t.Setenv("CLOUD_PROVIDER", tc.cloudProvider)
 			}
 
 			reconciler := &WorkspaceReconciler{
@@ -1064,9 +1115,10 @@ func TestApplyWorkspaceResource(t *testing.T) {
 			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = tc.karpenterFeatureGateEnabled
 			ctx := context.Background()
 
-			err := reconciler.applyWorkspaceResource(ctx, &tc.workspace)
+			result, err := reconciler.applyWorkspaceResource(ctx, &tc.workspace)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
+				assert.Equal(t, tc.expectedRequeue, !result.IsZero())
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
@@ -1075,6 +1127,7 @@ func TestApplyWorkspaceResource(t *testing.T) {
 }
 
 func TestUpdateControllerRevision1(t *testing.T) {
+	test.RegisterTestModel()
 	testcases := map[string]struct {
 		callMocks     func(c *test.MockClient)
 		workspace     v1alpha1.Workspace
@@ -1091,7 +1144,7 @@ func TestUpdateControllerRevision1(t *testing.T) {
 						*dep = appsv1.ControllerRevision{
 							ObjectMeta: v1.ObjectMeta{
 								Annotations: map[string]string{
-									WorkspaceHashAnnotation: "1171dc5d15043c92e684c8f06689eb241763a735181fdd2b59c8bd8fd6eecdd4",
+									WorkspaceHashAnnotation: "b2a72832f786e15535c55c08c0b90fd71ed71df305b8e72f5d944f1da6065381",
 								},
 							},
 						}
@@ -1111,6 +1164,49 @@ func TestUpdateControllerRevision1(t *testing.T) {
 			},
 		},
 
+		"Renumber the restored revision": {
+			callMocks: func(c *test.MockClient) {
+				revisionMap := c.CreateMapWithType(&appsv1.ControllerRevisionList{})
+				for i := 1; i <= 2; i++ {
+					revision := &appsv1.ControllerRevision{
+						ObjectMeta: v1.ObjectMeta{Name: fmt.Sprintf("revision-%d", i)},
+						Revision:   int64(i),
+					}
+					revisionMap[client.ObjectKeyFromObject(revision)] = revision
+				}
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.ControllerRevision{}), mock.Anything).
+					Run(func(args mock.Arguments) {
+						dep := args.Get(2).(*appsv1.ControllerRevision)
+						*dep = appsv1.ControllerRevision{
+							ObjectMeta: v1.ObjectMeta{
+								Name: "revision-1",
+								Annotations: map[string]string{
+									WorkspaceHashAnnotation: "b2a72832f786e15535c55c08c0b90fd71ed71df305b8e72f5d944f1da6065381",
+								},
+							},
+							Revision: 1,
+						}
+					}).
+					Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(cr *appsv1.ControllerRevision) bool {
+					return cr.Name == "revision-1" && cr.Revision == 3
+				}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(w *kaitov1alpha1.Workspace) bool {
+					return w.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] == "3"
+				}), mock.Anything).Return(nil)
+			},
+			workspace:     test.MockWorkspaceWithComputeHash,
+			expectedError: nil,
+			verifyCalls: func(c *test.MockClient) {
+				c.AssertNumberOfCalls(t, "List", 1)
+				c.AssertNumberOfCalls(t, "Create", 0)
+				c.AssertNumberOfCalls(t, "Get", 1)
+				c.AssertNumberOfCalls(t, "Delete", 0)
+				c.AssertNumberOfCalls(t, "Update", 2)
+			},
+		},
+
 		"Fail to create ControllerRevision": {
 			callMocks: func(c *test.MockClient) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything, mock.Anything).Return(nil)
@@ -1256,3 +1352,17 @@ func TestUpdateControllerRevision1(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeHashIgnoresScaling(t *testing.T) {
+	test.RegisterTestModel()
+	scaled := func(w *v1alpha1.Workspace, count int) *v1alpha1.Workspace {
+		w = w.DeepCopy()
+		w.Resource.Count = lo.ToPtr(count)
+		return w
+	}
+
+	// The count of a workspace that runs one replica per node is changed by scaling, which is not a new revision.
+	assert.Equal(t, computeHash(scaled(test.MockWorkspaceWithPreset, 1)), computeHash(scaled(test.MockWorkspaceWithPreset, 3)))
+	// The count of distributed inference is part of the revision since it cannot be scaled.
+	assert.Check(t, computeHash(scaled(test.MockWorkspaceDistributedModel, 1)) != computeHash(scaled(test.MockWorkspaceDistributedModel, 3)))
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// checkGPUQuota checks the nodes of the workspace against the GPU quotas of its namespace before new nodes are created.
+// It returns false, and records the reason in the GPUQuotaSatisfied condition, if the nodes exceed a quota.
+func (c *WorkspaceReconciler) checkGPUQuota(ctx context.Context, wObj *kaitov1alpha1.Workspace) (bool, error) {
+	err := kaitov1alpha1.ValidateGPUQuota(ctx, c.Client, wObj,
+		[]string{kaitov1alpha1.GetWorkspaceInstanceType(wObj)}, lo.FromPtr(wObj.Resource.Count))
+	if err != nil {
+		if !errors.Is(err, kaitov1alpha1.ErrGPUQuotaExceeded) {
+			return false, err
+		}
+		klog.InfoS("workspace exceeds GPU quota, not creating nodes", "workspace", klog.KObj(wObj), "reason", err.Error())
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeGPUQuotaStatus, metav1.ConditionFalse,
+			"GPUQuotaExceeded", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return false, updateErr
+		}
+		return false, nil
+	}
+
+	// Only flip the condition back once it has been reported, workspaces that never hit a quota do not carry it.
+	if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1alpha1.ConditionTypeGPUQuotaStatus)); cond != nil && cond.Status != metav1.ConditionTrue {
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeGPUQuotaStatus, metav1.ConditionTrue,
+			"GPUQuotaSatisfied", "nodes of the workspace fit in the GPU quotas of the namespace"); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return false, err
+		}
+	}
+	return true, nil
+}
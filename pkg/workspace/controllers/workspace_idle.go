@@ -0,0 +1,336 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/activator"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/machine"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	idleScaledToZeroReason  = "WorkspaceScaledToZero"
+	idleScaledToZeroMessage = "the inference workload is scaled to zero until a request is received"
+)
+
+var (
+	// idleCheckInterval is the interval to check the request activity of a workspace with an idle policy. It also
+	// bounds the delay before a request held by the activator triggers the workspace to be brought back.
+	idleCheckInterval = 10 * time.Second
+
+	activatorHTTPClient = &http.Client{Timeout: 5 * time.Second}
+	// scrapeActivatorStats fetches the request activity from an activator pod. It is a variable for testing.
+	scrapeActivatorStats = func(ctx context.Context, pod *corev1.Pod) (*activator.Stats, error) {
+		url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, manifests.ActivatorStatsPort, activator.StatsPath)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := activatorHTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+		stats := &activator.Stats{}
+		if err := json.NewDecoder(resp.Body).Decode(stats); err != nil {
+			return nil, fmt.Errorf("failed to decode stats from %s: %w", url, err)
+		}
+		return stats, nil
+	}
+)
+
+// applyIdlePolicy scales the workspace to zero once the activator has not seen a request for the idle period of the
+// idle policy, and brings it back as soon as the activator holds a request. It returns true while the workspace is
+// scaled to zero, in which case neither nodes nor the workload should be applied.
+func (c *WorkspaceReconciler) applyIdlePolicy(ctx context.Context, wObj *kaitov1alpha1.Workspace) (bool, error) {
+	scaledToZero := meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeScaledToZero))
+	policy := wObj.Inference.IdlePolicy
+	if policy == nil {
+		if err := c.deleteActivator(ctx, wObj); err != nil {
+			return false, err
+		}
+		if scaledToZero {
+			if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeScaledToZero, metav1.ConditionFalse,
+				"IdlePolicyRemoved", "the idle policy has been removed"); err != nil {
+				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	if err := c.ensureActivator(ctx, wObj); err != nil {
+		return false, err
+	}
+	stats, err := c.getActivatorStats(ctx, wObj)
+	if err != nil {
+		return false, err
+	}
+
+	if scaledToZero {
+		if stats == nil || stats.ActiveRequests == 0 {
+			// Keep the workspace at zero, e.g., in case the workload was scaled up by someone else.
+			return true, c.scaleToZero(ctx, wObj, kaitov1alpha1.WorkspacePhaseScaledToZero, idleScaledToZeroReason, idleScaledToZeroMessage)
+		}
+		klog.InfoS("requests received, bringing the workspace back", "workspace", klog.KObj(wObj), "activeRequests", stats.ActiveRequests)
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeScaledToZero, metav1.ConditionFalse,
+			"RequestsReceived", fmt.Sprintf("%d requests are waiting for the workspace", stats.ActiveRequests)); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return false, err
+		}
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "Activated", "Bringing the workspace back for %d requests", stats.ActiveRequests)
+		return false, nil
+	}
+
+	// Never scale to zero without knowing the request activity.
+	if stats == nil || stats.ActiveRequests > 0 {
+		return false, nil
+	}
+	// The workspace is also considered active since it was last brought back, either by requests or by its schedule,
+	// as the activator may not have seen a request for a long time before.
+	lastActiveTime := stats.LastRequestTime
+	for _, conditionType := range []kaitov1alpha1.ConditionType{kaitov1alpha1.WorkspaceConditionTypeScaledToZero, kaitov1alpha1.WorkspaceConditionTypeScheduleActive} {
+		if condition := meta.FindStatusCondition(wObj.Status.Conditions, string(conditionType)); condition != nil && condition.LastTransitionTime.After(lastActiveTime) {
+			lastActiveTime = condition.LastTransitionTime.Time
+		}
+	}
+	idlePeriod := time.Duration(policy.IdleMinutes) * time.Minute
+	if time.Since(lastActiveTime) < idlePeriod {
+		return false, nil
+	}
+
+	klog.InfoS("workspace is idle, scaling to zero", "workspace", klog.KObj(wObj), "lastRequestTime", stats.LastRequestTime)
+	if err := c.scaleToZero(ctx, wObj, kaitov1alpha1.WorkspacePhaseScaledToZero, idleScaledToZeroReason, idleScaledToZeroMessage); err != nil {
+		return false, err
+	}
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeScaledToZero, metav1.ConditionTrue,
+		"Idle", fmt.Sprintf("no request has been received in the last %d minutes", policy.IdleMinutes)); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return false, err
+	}
+	c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "ScaledToZero", "No request has been received in the last %d minutes", policy.IdleMinutes)
+	return true, nil
+}
+
+// ensureActivator creates the activator of the workspace, or updates it if it has drifted from the desired state,
+// e.g., after the workspace controller is upgraded.
+func (c *WorkspaceReconciler) ensureActivator(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	image := os.Getenv(consts.ActivatorImageEnvVar)
+	if image == "" {
+		return fmt.Errorf("the %s environment variable must be set to use the idle policy", consts.ActivatorImageEnvVar)
+	}
+	isStatefulSet := false
+	if presetName := getPresetName(wObj); presetName != "" {
+		model := plugin.KaitoModelRegister.Get(presetName)
+		if model == nil {
+			return fmt.Errorf("preset %s is not registered", presetName)
+		}
+		isStatefulSet = model.SupportDistributedInference()
+	}
+
+	for _, obj := range []struct{ desired, existing client.Object }{
+		{manifests.GenerateActivatorServiceAccountManifest(wObj), &corev1.ServiceAccount{}},
+		{manifests.GenerateActivatorRoleManifest(wObj), &rbacv1.Role{}},
+		{manifests.GenerateActivatorRoleBindingManifest(wObj), &rbacv1.RoleBinding{}},
+		{manifests.GenerateActivatorDeploymentManifest(wObj, image, isStatefulSet), &appsv1.Deployment{}},
+		{manifests.GenerateActivatorServiceManifest(wObj), &corev1.Service{}},
+	} {
+		if err := resources.SetLastAppliedConfiguration(obj.desired); err != nil {
+			return err
+		}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj.desired), obj.existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			if err := resources.CreateResource(ctx, obj.desired, c.Client); client.IgnoreAlreadyExists(err) != nil {
+				return err
+			}
+			continue
+		}
+		patch, err := resources.CreateThreeWayPatch(obj.existing, obj.desired)
+		if err != nil {
+			return err
+		}
+		if patch == nil {
+			continue
+		}
+		klog.InfoS("updating the activator of the workspace", "workspace", klog.KObj(wObj), "kind", fmt.Sprintf("%T", obj.existing))
+		if err := c.Client.Patch(ctx, obj.existing, patch); err != nil {
+			klog.ErrorS(err, "failed to update the activator", "workspace", klog.KObj(wObj))
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteActivator deletes the activator of a workspace whose idle policy has been removed. Requests sent to the
+// activator service are no longer accepted; the clients should use the workspace service again.
+func (c *WorkspaceReconciler) deleteActivator(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	key := client.ObjectKey{Name: manifests.GetActivatorName(wObj), Namespace: wObj.Namespace}
+	for _, obj := range []client.Object{
+		&corev1.Service{}, &appsv1.Deployment{}, &rbacv1.RoleBinding{}, &rbacv1.Role{}, &corev1.ServiceAccount{},
+	} {
+		if err := c.Client.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		// Objects of the same name that were not created for the workspace are left alone.
+		if !metav1.IsControlledBy(obj, wObj) || !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		klog.InfoS("deleting the activator of the workspace", "workspace", klog.KObj(wObj), "kind", fmt.Sprintf("%T", obj))
+		if err := c.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			klog.ErrorS(err, "failed to delete the activator", "workspace", klog.KObj(wObj))
+			return err
+		}
+	}
+	return nil
+}
+
+// getActivatorStats sums up the request activity reported by the ready activator pods of the workspace. It returns
+// nil if no activator pod reports its activity.
+func (c *WorkspaceReconciler) getActivatorStats(ctx context.Context, wObj *kaitov1alpha1.Workspace) (*activator.Stats, error) {
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1alpha1.LabelActivatorName: wObj.Name}); err != nil {
+		return nil, err
+	}
+
+	var total *activator.Stats
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !isPodReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		stats, err := scrapeActivatorStats(ctx, pod)
+		if err != nil {
+			klog.ErrorS(err, "failed to scrape activator stats", "workspace", klog.KObj(wObj), "pod", klog.KObj(pod))
+			continue
+		}
+		if total == nil {
+			total = &activator.Stats{}
+		}
+		total.ActiveRequests += stats.ActiveRequests
+		if stats.LastRequestTime.After(total.LastRequestTime) {
+			total.LastRequestTime = stats.LastRequestTime
+		}
+	}
+	return total, nil
+}
+
+// scaleToZero scales the inference workload of the workspace to zero replicas and deletes the nodeClaims/machines
+// created for the workspace. Nodes brought by the user are kept but no longer listed as worker nodes. The workspace
+// is moved to the given phase, with the reason and message recorded in the InferenceReady condition.
+func (c *WorkspaceReconciler) scaleToZero(ctx context.Context, wObj *kaitov1alpha1.Workspace, phase kaitov1alpha1.WorkspacePhase,
+	reason, message string) error {
+	for _, workloadObj := range []client.Object{&appsv1.Deployment{}, &appsv1.StatefulSet{}} {
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), workloadObj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		var replicas **int32
+		switch workload := workloadObj.(type) {
+		case *appsv1.Deployment:
+			replicas = &workload.Spec.Replicas
+		case *appsv1.StatefulSet:
+			replicas = &workload.Spec.Replicas
+		}
+		if lo.FromPtrOr(*replicas, 1) == 0 {
+			continue
+		}
+		patch := client.MergeFrom(workloadObj.DeepCopyObject().(client.Object))
+		*replicas = lo.ToPtr(int32(0))
+		if err := c.Client.Patch(ctx, workloadObj, patch); err != nil {
+			klog.ErrorS(err, "failed to scale the workload to zero", "workspace", klog.KObj(wObj))
+			return err
+		}
+	}
+
+	var owners []client.Object
+	switch {
+	case !utils.IsNodeProvisioningEnabled():
+		// The existing nodes of on-prem clusters are kept.
+	case featuregates.FeatureGates[consts.FeatureFlagKarpenter]:
+		ncList, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+		if err != nil {
+			return err
+		}
+		for i := range ncList.Items {
+			owners = append(owners, &ncList.Items[i])
+		}
+	default:
+		mList, err := machine.ListMachines(ctx, wObj, c.Client)
+		if err != nil {
+			return err
+		}
+		for i := range mList.Items {
+			owners = append(owners, &mList.Items[i])
+		}
+	}
+	for _, owner := range owners {
+		if !owner.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		klog.InfoS("deleting the node of an idle workspace", "workspace", klog.KObj(wObj), "owner", klog.KObj(owner))
+		if err := c.Client.Delete(ctx, owner); client.IgnoreNotFound(err) != nil {
+			klog.ErrorS(err, "failed to delete the node owner", "owner", klog.KObj(owner))
+			return err
+		}
+	}
+
+	if len(wObj.Status.WorkerNodes) > 0 || wObj.Status.ReadyReplicas > 0 || wObj.Status.DesiredReplicas > 0 {
+		if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace},
+			func(status *kaitov1alpha1.WorkspaceStatus) {
+				status.WorkerNodes = nil
+				status.ReadyReplicas = 0
+				status.DesiredReplicas = 0
+			}); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return err
+		}
+		wObj.Status.WorkerNodes = nil
+		wObj.Status.ReadyReplicas = 0
+		wObj.Status.DesiredReplicas = 0
+	}
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse,
+		reason, message); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	if err := c.updateStatusPhaseIfNotMatch(ctx, wObj, phase); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	return nil
+}
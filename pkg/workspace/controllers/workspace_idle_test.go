@@ -0,0 +1,317 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/activator"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestApplyIdlePolicy(t *testing.T) {
+	os.Setenv(consts.ActivatorImageEnvVar, "kaito/workspace:test")
+	defer os.Unsetenv(consts.ActivatorImageEnvVar)
+
+	activatorPod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "testWorkspace-activator-0",
+			Namespace: "kaito",
+			Labels:    map[string]string{v1alpha1.LabelActivatorName: "testWorkspace"},
+		},
+		Status: corev1.PodStatus{
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	activatorMocks := func(c *test.MockClient) {
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ServiceAccount{}), mock.Anything).Return(nil)
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&rbacv1.Role{}), mock.Anything).Return(nil)
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything).Return(nil)
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+		c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+	}
+	statusMocks := func(c *test.MockClient) {
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+		c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+	}
+	scaleToZeroMocks := func(c *test.MockClient) {
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).
+			Return(apierrors.NewNotFound(schema.GroupResource{}, "testWorkspace"))
+		c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything).Return(nil)
+		c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+		c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+	}
+
+	testcases := map[string]struct {
+		scaledToZero         bool
+		stats                *activator.Stats
+		callMocks            func(c *test.MockClient)
+		expectedScaledToZero bool
+		expectedEvent        string
+		expectedPhase        v1alpha1.WorkspacePhase
+	}{
+		"Active workspace is kept": {
+			stats: &activator.Stats{LastRequestTime: time.Now().Add(-time.Minute)},
+			callMocks: func(c *test.MockClient) {
+				activatorMocks(c)
+			},
+		},
+		"Workspace with held requests is kept": {
+			stats: &activator.Stats{ActiveRequests: 1, LastRequestTime: time.Now().Add(-time.Hour)},
+			callMocks: func(c *test.MockClient) {
+				activatorMocks(c)
+			},
+		},
+		"Idle workspace is scaled to zero": {
+			stats: &activator.Stats{LastRequestTime: time.Now().Add(-10 * time.Minute)},
+			callMocks: func(c *test.MockClient) {
+				activatorMocks(c)
+				statusMocks(c)
+				scaleToZeroMocks(c)
+			},
+			expectedScaledToZero: true,
+			expectedEvent:        "Normal ScaledToZero No request has been received in the last 5 minutes",
+			expectedPhase:        v1alpha1.WorkspacePhaseScaledToZero,
+		},
+		"Workspace scaled to zero stays at zero without requests": {
+			scaledToZero: true,
+			stats:        &activator.Stats{LastRequestTime: time.Now().Add(-time.Hour)},
+			callMocks: func(c *test.MockClient) {
+				activatorMocks(c)
+				statusMocks(c)
+				scaleToZeroMocks(c)
+			},
+			expectedScaledToZero: true,
+			expectedPhase:        v1alpha1.WorkspacePhaseScaledToZero,
+		},
+		"Workspace scaled to zero is brought back on requests": {
+			scaledToZero: true,
+			stats:        &activator.Stats{ActiveRequests: 2, LastRequestTime: time.Now()},
+			callMocks: func(c *test.MockClient) {
+				activatorMocks(c)
+				statusMocks(c)
+			},
+			expectedEvent: "Normal Activated Bringing the workspace back for 2 requests",
+		},
+	}
+
+	originalScrape := scrapeActivatorStats
+	defer func() { scrapeActivatorStats = originalScrape }()
+	originalKarpenter := featuregates.FeatureGates[consts.FeatureFlagKarpenter]
+	defer func() { featuregates.FeatureGates[consts.FeatureFlagKarpenter] = originalKarpenter }()
+	featuregates.FeatureGates[consts.FeatureFlagKarpenter] = false
+	test.RegisterTestModel()
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Inference.IdlePolicy = &v1alpha1.IdlePolicySpec{IdleMinutes: 5}
+			workspace.Status.WorkerNodes = []string{"node1"}
+			if tc.scaledToZero {
+				meta.SetStatusCondition(&workspace.Status.Conditions, v1.Condition{
+					Type:   string(v1alpha1.WorkspaceConditionTypeScaledToZero),
+					Status: v1.ConditionTrue,
+					Reason: "Idle",
+				})
+			}
+
+			mockClient := test.NewClient()
+			mockClient.CreateMapWithType(&corev1.PodList{})[client.ObjectKeyFromObject(activatorPod)] = activatorPod
+			workload := &appsv1.Deployment{
+				ObjectMeta: v1.ObjectMeta{Name: workspace.Name, Namespace: workspace.Namespace},
+				Spec:       appsv1.DeploymentSpec{Replicas: lo.ToPtr(int32(1))},
+			}
+			mockClient.CreateOrUpdateObjectInMap(workload)
+			for _, obj := range generateActivator(workspace, false) {
+				mockClient.CreateOrUpdateObjectInMap(obj)
+			}
+			machineObj := &v1alpha5.Machine{ObjectMeta: v1.ObjectMeta{Name: "machine1"}}
+			mockClient.CreateMapWithType(&v1alpha5.MachineList{})[client.ObjectKeyFromObject(machineObj)] = machineObj
+			tc.callMocks(mockClient)
+			scrapeActivatorStats = func(_ context.Context, _ *corev1.Pod) (*activator.Stats, error) {
+				return tc.stats, nil
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+
+			scaledToZero, err := reconciler.applyIdlePolicy(context.Background(), workspace)
+			assert.Check(t, err == nil, "Not expected to return error")
+			assert.Equal(t, tc.expectedScaledToZero, scaledToZero)
+			if tc.expectedEvent != "" {
+				assert.Equal(t, 1, len(recorder.Events))
+				assert.Equal(t, tc.expectedEvent, <-recorder.Events)
+			} else {
+				assert.Equal(t, 0, len(recorder.Events))
+			}
+			if tc.expectedScaledToZero {
+				mockClient.AssertCalled(t, "Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything)
+				mockClient.AssertCalled(t, "Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything)
+				assert.Equal(t, 0, len(workspace.Status.WorkerNodes))
+				assert.Equal(t, tc.expectedPhase, workspace.Status.Phase)
+			}
+		})
+	}
+}
+
+// generateActivator returns the activator objects of the workspace as applied by the controller.
+func generateActivator(workspace *v1alpha1.Workspace, isStatefulSet bool) []client.Object {
+	objs := []client.Object{
+		manifests.GenerateActivatorServiceAccountManifest(workspace),
+		manifests.GenerateActivatorRoleManifest(workspace),
+		manifests.GenerateActivatorRoleBindingManifest(workspace),
+		manifests.GenerateActivatorDeploymentManifest(workspace, "kaito/workspace:test", isStatefulSet),
+		manifests.GenerateActivatorServiceManifest(workspace),
+	}
+	for _, obj := range objs {
+		_ = resources.SetLastAppliedConfiguration(obj)
+	}
+	return objs
+}
+
+func TestEnsureActivator(t *testing.T) {
+	test.RegisterTestModel()
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	workspace.Inference.IdlePolicy = &v1alpha1.IdlePolicySpec{IdleMinutes: 5}
+	activatorTypes := []client.Object{
+		&corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}, &appsv1.Deployment{}, &corev1.Service{},
+	}
+
+	testcases := map[string]struct {
+		image           string
+		existing        func() []client.Object
+		expectedError   bool
+		expectedCreates int
+		expectedPatches []client.Object
+	}{
+		"Activator image is required": {
+			expectedError: true,
+		},
+		"Activator is created": {
+			image:           "kaito/workspace:test",
+			expectedCreates: 5,
+		},
+		"Activator up to date is kept": {
+			image: "kaito/workspace:test",
+			existing: func() []client.Object {
+				return generateActivator(workspace, false)
+			},
+		},
+		"Outdated activator is updated": {
+			image: "kaito/workspace:test",
+			existing: func() []client.Object {
+				objs := generateActivator(workspace, false)
+				// The deployment created by an earlier controller, which probed the upstream service.
+				deployment := objs[3].(*appsv1.Deployment)
+				deployment.Annotations = nil
+				deployment.Spec.Template.Spec.ServiceAccountName = ""
+				deployment.Spec.Template.Spec.Containers[0].Command = []string{"/activator",
+					"--upstream=http://testWorkspace.kaito.svc.cluster.local:80"}
+				return objs
+			},
+			expectedPatches: []client.Object{&appsv1.Deployment{}},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			if tc.image != "" {
+				os.Setenv(consts.ActivatorImageEnvVar, tc.image)
+				defer os.Unsetenv(consts.ActivatorImageEnvVar)
+			}
+			mockClient := test.NewClient()
+			existingTypes := map[string]bool{}
+			if tc.existing != nil {
+				for _, obj := range tc.existing() {
+					mockClient.CreateOrUpdateObjectInMap(obj)
+					existingTypes[fmt.Sprintf("%T", obj)] = true
+				}
+			}
+			for _, obj := range activatorTypes {
+				var err error
+				if !existingTypes[fmt.Sprintf("%T", obj)] {
+					err = apierrors.NewNotFound(schema.GroupResource{}, manifests.GetActivatorName(workspace))
+				}
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(obj), mock.Anything).Return(err)
+				mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(obj), mock.Anything).Return(nil)
+				mockClient.On("Patch", mock.IsType(context.Background()), mock.IsType(obj), mock.Anything, mock.Anything).Return(nil)
+			}
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			err := reconciler.ensureActivator(context.Background(), workspace)
+			if tc.expectedError {
+				assert.Check(t, err != nil, "Expected an error without the activator image")
+				return
+			}
+			assert.Check(t, err == nil, "Not expected to return error")
+			mockClient.AssertNumberOfCalls(t, "Create", tc.expectedCreates)
+			mockClient.AssertNumberOfCalls(t, "Patch", len(tc.expectedPatches))
+			for _, obj := range tc.expectedPatches {
+				mockClient.AssertCalled(t, "Patch", mock.IsType(context.Background()), mock.IsType(obj), mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeleteActivator(t *testing.T) {
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	activator := generateActivator(workspace, false)
+	// A service account of the same name that was not created for the workspace.
+	serviceAccount := activator[0].(*corev1.ServiceAccount)
+	serviceAccount.OwnerReferences = nil
+
+	mockClient := test.NewClient()
+	for _, obj := range []client.Object{serviceAccount, activator[3], activator[4]} {
+		mockClient.CreateOrUpdateObjectInMap(obj)
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(obj), mock.Anything).Return(nil)
+	}
+	for _, obj := range []client.Object{&rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(obj), mock.Anything).
+			Return(apierrors.NewNotFound(schema.GroupResource{}, manifests.GetActivatorName(workspace)))
+	}
+	mockClient.On("Delete", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+	reconciler := &WorkspaceReconciler{
+		Client:   mockClient,
+		Scheme:   test.NewTestScheme(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	err := reconciler.deleteActivator(context.Background(), workspace)
+	assert.Check(t, err == nil, "Not expected to return error")
+	mockClient.AssertNumberOfCalls(t, "Delete", 2)
+	mockClient.AssertCalled(t, "Delete", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything)
+	mockClient.AssertCalled(t, "Delete", mock.IsType(context.Background()), mock.IsType(&corev1.Service{}), mock.Anything)
+}
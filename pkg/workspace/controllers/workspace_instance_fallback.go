@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/machine"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+// fallbackInstanceType switches the workspace to the next candidate instance type after nodes of the current instance
+// type failed to launch because the SKU is unavailable. All the nodeClaims/machines of the current instance type are
+// deleted, including the launched ones, so that the workload runs on nodes of the same instance type that are created
+// with the next instance type. It returns false if there is no instance type left to try.
+//
+// The fallback only moves forward: the workspace keeps the instance type it fell back to, and the preferred instance
+// types are not retried when they become available again, since that would replace working nodes. The instance types
+// are immutable, so the workspace has to be recreated to start over from resource.instanceType.
+func (c *WorkspaceReconciler) fallbackInstanceType(ctx context.Context, wObj *kaitov1alpha1.Workspace) (bool, error) {
+	current := kaitov1alpha1.GetWorkspaceInstanceType(wObj)
+	next := nextInstanceType(&wObj.Resource, current)
+	if next == "" {
+		return false, nil
+	}
+
+	if err := c.deleteInstanceTypeNodes(ctx, wObj, current); err != nil {
+		return false, err
+	}
+
+	klog.InfoS("instance type is unavailable, falling back to the next instance type", "workspace", klog.KObj(wObj),
+		"instanceType", current, "nextInstanceType", next)
+	if err := c.updateStatusInstanceTypeIfNotMatch(ctx, wObj, next); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return false, err
+	}
+	c.Recorder.Eventf(wObj, corev1.EventTypeWarning, "InstanceTypeFallback",
+		"Instance type %s is unavailable, provisioning nodes with instance type %s", current, next)
+	return true, nil
+}
+
+// nextInstanceType returns the instance type following current in the ordered candidates of the resource spec,
+// or an empty string if current is the last one.
+func nextInstanceType(r *kaitov1alpha1.ResourceSpec, current string) string {
+	candidates := append([]string{r.InstanceType}, r.FallbackInstanceTypes...)
+	index := lo.IndexOf(candidates, current)
+	if index < 0 || index+1 >= len(candidates) {
+		return ""
+	}
+	return candidates[index+1]
+}
+
+// deleteInstanceTypeNodes deletes the nodeClaims/machines of the workspace that request the instance type or failed to
+// launch because the instance type is unavailable.
+func (c *WorkspaceReconciler) deleteInstanceTypeNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace, instanceType string) error {
+	var abandoned []client.Object
+	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+		nodeClaims, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+		if err != nil {
+			return err
+		}
+		for i := range nodeClaims.Items {
+			nodeClaim := &nodeClaims.Items[i]
+			requested := lo.ContainsBy(nodeClaim.Spec.Requirements, func(requirement v1beta1.NodeSelectorRequirementWithMinValues) bool {
+				return requirement.Key == corev1.LabelInstanceTypeStable && lo.Contains(requirement.Values, instanceType)
+			})
+			if (requested || nodeclaim.IsNodeClaimInstanceTypeUnavailable(nodeClaim)) && nodeClaim.DeletionTimestamp.IsZero() {
+				abandoned = append(abandoned, nodeClaim)
+			}
+		}
+	} else {
+		machines, err := machine.ListMachines(ctx, wObj, c.Client)
+		if err != nil {
+			return err
+		}
+		for i := range machines.Items {
+			machineObj := &machines.Items[i]
+			requested := lo.ContainsBy(machineObj.Spec.Requirements, func(requirement corev1.NodeSelectorRequirement) bool {
+				return requirement.Key == corev1.LabelInstanceTypeStable && lo.Contains(requirement.Values, instanceType)
+			})
+			if (requested || machine.IsMachineInstanceTypeUnavailable(machineObj)) && machineObj.DeletionTimestamp.IsZero() {
+				abandoned = append(abandoned, machineObj)
+			}
+		}
+	}
+
+	for _, obj := range abandoned {
+		klog.InfoS("deleting node of the abandoned instance type", "workspace", klog.KObj(wObj), "name", obj.GetName(),
+			"instanceType", instanceType)
+		if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+func TestNextInstanceType(t *testing.T) {
+	resource := &v1alpha1.ResourceSpec{
+		InstanceType:          "Standard_NC24ads_A100_v4",
+		FallbackInstanceTypes: []string{"Standard_NC48ads_A100_v4", "Standard_NC96ads_A100_v4"},
+	}
+	testcases := map[string]struct {
+		current  string
+		expected string
+	}{
+		"Primary instance type falls back to the first fallback": {
+			current:  "Standard_NC24ads_A100_v4",
+			expected: "Standard_NC48ads_A100_v4",
+		},
+		"Fallback instance type falls back to the next one": {
+			current:  "Standard_NC48ads_A100_v4",
+			expected: "Standard_NC96ads_A100_v4",
+		},
+		"Last instance type has no fallback": {
+			current:  "Standard_NC96ads_A100_v4",
+			expected: "",
+		},
+		"Unknown instance type has no fallback": {
+			current:  "Standard_NC6s_v3",
+			expected: "",
+		},
+	}
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nextInstanceType(resource, tc.current))
+		})
+	}
+}
+
+func TestFallbackInstanceType(t *testing.T) {
+	unavailableNodeClaim := &v1beta1.NodeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "nodeclaim-1"},
+		Status: v1beta1.NodeClaimStatus{
+			Conditions: []apis.Condition{
+				{
+					Type:    v1beta1.Launched,
+					Status:  corev1.ConditionFalse,
+					Message: consts.ErrorInstanceTypesUnavailable,
+				},
+			},
+		},
+	}
+	nodeClaimOfInstanceType := func(name, instanceType string) *v1beta1.NodeClaim {
+		return &v1beta1.NodeClaim{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Spec: v1beta1.NodeClaimSpec{
+				Requirements: []v1beta1.NodeSelectorRequirementWithMinValues{
+					{
+						NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+							Key:      corev1.LabelInstanceTypeStable,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{instanceType},
+						},
+					},
+				},
+			},
+		}
+	}
+	launchedNodeClaim := nodeClaimOfInstanceType("nodeclaim-2", "Standard_NC12s_v3")
+	fallbackNodeClaim := nodeClaimOfInstanceType("nodeclaim-3", "Standard_NC24s_v3")
+
+	testcases := map[string]struct {
+		fallbackInstanceTypes []string
+		statusInstanceType    string
+		callMocks             func(c *test.MockClient)
+		expectedFallback      bool
+		expectedInstanceType  string
+	}{
+		"Fall back to the next instance type and delete the nodeClaims of the abandoned instance type": {
+			fallbackInstanceTypes: []string{"Standard_NC24s_v3"},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				// The launched nodeClaim of the abandoned instance type is released too.
+				c.On("Delete", mock.IsType(context.Background()), mock.MatchedBy(func(nc *v1beta1.NodeClaim) bool {
+					return nc.Name == "nodeclaim-1" || nc.Name == "nodeclaim-2"
+				}), mock.Anything).Return(nil).Times(2)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedFallback:     true,
+			expectedInstanceType: "Standard_NC24s_v3",
+		},
+		"No fallback instance type is left": {
+			fallbackInstanceTypes: []string{"Standard_NC24s_v3"},
+			statusInstanceType:    "Standard_NC24s_v3",
+			callMocks:             func(c *test.MockClient) {},
+			expectedFallback:      false,
+			expectedInstanceType:  "Standard_NC24s_v3",
+		},
+		"No fallback instance type is configured": {
+			callMocks:            func(c *test.MockClient) {},
+			expectedFallback:     false,
+			expectedInstanceType: "",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			originalKarpenter := featuregates.FeatureGates[consts.FeatureFlagKarpenter]
+			defer func() { featuregates.FeatureGates[consts.FeatureFlagKarpenter] = originalKarpenter }()
+			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = true
+
+			mockClient := test.NewClient()
+			ncMap := mockClient.CreateMapWithType(&v1beta1.NodeClaimList{})
+			for _, nc := range []*v1beta1.NodeClaim{unavailableNodeClaim, launchedNodeClaim, fallbackNodeClaim} {
+				ncMap[client.ObjectKeyFromObject(nc)] = nc.DeepCopy()
+			}
+			tc.callMocks(mockClient)
+
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Resource.FallbackInstanceTypes = tc.fallbackInstanceTypes
+			workspace.Status.InstanceType = tc.statusInstanceType
+
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			fellBack, err := reconciler.fallbackInstanceType(context.Background(), workspace)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedFallback, fellBack)
+			assert.Equal(t, tc.expectedInstanceType, workspace.Status.InstanceType)
+			mockClient.AssertExpectations(t)
+			mockClient.AssertNotCalled(t, "Delete", mock.Anything, mock.MatchedBy(func(nc *v1beta1.NodeClaim) bool {
+				return nc.Name == "nodeclaim-3"
+			}), mock.Anything)
+		})
+	}
+}
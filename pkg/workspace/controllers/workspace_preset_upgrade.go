@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	presetUpgradeAvailableReason = "PresetUpgradeAvailable"
+	presetUpToDateReason         = "PresetUpToDate"
+	presetUpgradedReason         = "PresetUpgraded"
+	presetUpgradeFailedReason    = "PresetUpgradeFailed"
+)
+
+// upgradePreset pins the version of the preset to the tag requested by the upgrade-preset annotation, after which the
+// new preset image is rolled out like any other change of the workspace. The annotation is always removed so that an
+// upgrade is attempted only once, and the result is recorded in an event.
+func (c *WorkspaceReconciler) upgradePreset(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	value, found := wObj.Annotations[kaitov1alpha1.AnnotationUpgradePreset]
+	if !found {
+		return nil
+	}
+
+	fromTag, toTag, upgradeErr := getPresetUpgradeTags(wObj, value)
+	if upgradeErr == nil {
+		wObj.Inference.Preset.PresetOptions.Version = toTag
+	}
+	delete(wObj.Annotations, kaitov1alpha1.AnnotationUpgradePreset)
+	if err := c.Update(ctx, wObj); err != nil {
+		return fmt.Errorf("failed to upgrade preset to %s: %w", value, err)
+	}
+
+	if upgradeErr != nil {
+		klog.ErrorS(upgradeErr, "failed to upgrade preset", "workspace", klog.KObj(wObj), "version", value)
+		c.Recorder.Eventf(wObj, corev1.EventTypeWarning, presetUpgradeFailedReason, "Upgrade of the preset to %s failed: %v", value, upgradeErr)
+		return nil
+	}
+	klog.InfoS("upgraded preset", "workspace", klog.KObj(wObj), "from", fromTag, "to", toTag)
+	c.Recorder.Eventf(wObj, corev1.EventTypeNormal, presetUpgradedReason, "Upgraded preset %s from tag %s to tag %s",
+		wObj.Inference.Preset.Name, fromTag, toTag)
+	return nil
+}
+
+// getPresetUpgradeTags returns the current tag of the preset image of the workspace and the tag requested by the
+// value of the upgrade-preset annotation.
+func getPresetUpgradeTags(wObj *kaitov1alpha1.Workspace, value string) (string, string, error) {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return "", "", fmt.Errorf("the workspace has no inference preset")
+	}
+	presetName := string(wObj.Inference.Preset.Name)
+	presetModel := plugin.KaitoModelRegister.Get(presetName)
+	if presetModel == nil {
+		return "", "", fmt.Errorf("preset %s is not registered", presetName)
+	}
+	params := presetModel.GetInferenceParameters()
+	if wObj.Inference.Preset.AccessMode == kaitov1alpha1.ModelImageAccessModePrivate ||
+		params.ImageAccessMode == string(kaitov1alpha1.ModelImageAccessModePrivate) {
+		return "", "", fmt.Errorf("the image of preset %s is private", presetName)
+	}
+
+	fromTag := kaitov1alpha1.GetPresetTag(wObj.Inference.Preset, params.Tag)
+	if value == kaitov1alpha1.PresetUpgradeLatest {
+		return fromTag, model.GetPresetTags(presetName, params.Tag)[0], nil
+	}
+	if !model.IsKnownPresetTag(presetName, params.Tag, value) {
+		return "", "", fmt.Errorf("%s is not a released tag of preset %s", value, presetName)
+	}
+	return fromTag, value, nil
+}
+
+// updatePresetUpgradeCondition sets the PresetUpgradeAvailable condition when a newer tag of the preset image than
+// the one used by the workspace is released. The condition is only added when an upgrade is available, so that the
+// workspaces that have always been up to date do not carry it.
+func (c *WorkspaceReconciler) updatePresetUpgradeCondition(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil || wObj.Inference.Preset.AccessMode == kaitov1alpha1.ModelImageAccessModePrivate {
+		return nil
+	}
+	presetName := string(wObj.Inference.Preset.Name)
+	presetModel := plugin.KaitoModelRegister.Get(presetName)
+	if presetModel == nil {
+		// The ModelPreset of the workspace has been deleted, so no newer tag can be known.
+		return nil
+	}
+	params := presetModel.GetInferenceParameters()
+	if params.ImageAccessMode == string(kaitov1alpha1.ModelImageAccessModePrivate) {
+		return nil
+	}
+
+	currentTag := kaitov1alpha1.GetPresetTag(wObj.Inference.Preset, params.Tag)
+	if newerTag := model.GetNewerPresetTag(presetName, params.Tag, currentTag); newerTag != "" {
+		return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypePresetUpgradeAvailable, metav1.ConditionTrue,
+			presetUpgradeAvailableReason, fmt.Sprintf("tag %s of preset %s is available, the workspace uses tag %s", newerTag, presetName, currentTag))
+	}
+	if meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypePresetUpgradeAvailable)) == nil {
+		return nil
+	}
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypePresetUpgradeAvailable, metav1.ConditionFalse,
+		presetUpToDateReason, fmt.Sprintf("the workspace uses the newest tag %s of preset %s", currentTag, presetName))
+}
@@ -0,0 +1,153 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestUpgradePreset(t *testing.T) {
+	test.RegisterTestModel()
+	model.RegisterPresetTags("test-model", []string{"0.0.2", "0.0.1"})
+
+	testcases := map[string]struct {
+		annotations     map[string]string
+		callMocks       func(c *test.MockClient)
+		expectedVersion string
+	}{
+		"No upgrade requested": {
+			annotations: map[string]string{},
+			callMocks:   func(c *test.MockClient) {},
+		},
+		"Upgrade to the latest tag": {
+			annotations: map[string]string{v1alpha1.AnnotationUpgradePreset: v1alpha1.PresetUpgradeLatest},
+			callMocks: func(c *test.MockClient) {
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(w *v1alpha1.Workspace) bool {
+					_, found := w.Annotations[v1alpha1.AnnotationUpgradePreset]
+					return !found && w.Inference.Preset.PresetOptions.Version == "0.0.2"
+				}), mock.Anything).Return(nil)
+			},
+			expectedVersion: "0.0.2",
+		},
+		"Upgrade to a released tag": {
+			annotations: map[string]string{v1alpha1.AnnotationUpgradePreset: "0.0.1"},
+			callMocks: func(c *test.MockClient) {
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedVersion: "0.0.1",
+		},
+		"Tag is not released": {
+			annotations: map[string]string{v1alpha1.AnnotationUpgradePreset: "0.0.9"},
+			callMocks: func(c *test.MockClient) {
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(w *v1alpha1.Workspace) bool {
+					_, found := w.Annotations[v1alpha1.AnnotationUpgradePreset]
+					return !found
+				}), mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Annotations = tc.annotations
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			assert.NilError(t, reconciler.upgradePreset(context.Background(), workspace))
+			assert.Equal(t, tc.expectedVersion, workspace.Inference.Preset.PresetOptions.Version)
+			_, found := workspace.Annotations[v1alpha1.AnnotationUpgradePreset]
+			assert.Assert(t, !found)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePresetUpgradeCondition(t *testing.T) {
+	test.RegisterTestModel()
+	model.RegisterPresetTags("test-model", []string{"0.0.2", "0.0.1"})
+
+	testcases := map[string]struct {
+		presetName        string
+		version           string
+		conditions        []v1.Condition
+		callMocks         func(c *test.MockClient)
+		expectedCondition v1.ConditionStatus
+	}{
+		"Newer tag is available": {
+			version: "0.0.1",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedCondition: v1.ConditionTrue,
+		},
+		"Newest tag without the condition": {
+			version:   "0.0.2",
+			callMocks: func(c *test.MockClient) {},
+		},
+		"Preset of a deleted ModelPreset": {
+			presetName: "deleted-model-preset",
+			version:    "0.0.1",
+			callMocks:  func(c *test.MockClient) {},
+		},
+		"Newest tag after an upgrade": {
+			version: "0.0.2",
+			conditions: []v1.Condition{{
+				Type:   string(v1alpha1.WorkspaceConditionTypePresetUpgradeAvailable),
+				Status: v1.ConditionTrue,
+				Reason: presetUpgradeAvailableReason,
+			}},
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedCondition: v1.ConditionFalse,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			if tc.presetName != "" {
+				workspace.Inference.Preset.Name = v1alpha1.ModelName(tc.presetName)
+			}
+			workspace.Inference.Preset.PresetOptions.Version = tc.version
+			workspace.Status.Conditions = tc.conditions
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			assert.NilError(t, reconciler.updatePresetUpgradeCondition(context.Background(), workspace))
+			condition := meta.FindStatusCondition(workspace.Status.Conditions, string(v1alpha1.WorkspaceConditionTypePresetUpgradeAvailable))
+			if tc.expectedCondition == "" {
+				assert.Assert(t, condition == nil)
+			} else {
+				assert.Assert(t, condition != nil)
+				assert.Equal(t, tc.expectedCondition, condition.Status)
+			}
+		})
+	}
+}
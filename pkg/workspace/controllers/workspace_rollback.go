@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	rollbackSucceededReason = "RollbackSucceeded"
+	rollbackFailedReason    = "RollbackFailed"
+)
+
+// revisionData is the content of a workspace ControllerRevision, see marshalSelectedFields.
+type revisionData struct {
+	Resource  kaitov1alpha1.ResourceSpec   `json:"resource"`
+	Inference *kaitov1alpha1.InferenceSpec `json:"inference"`
+	Tuning    *kaitov1alpha1.TuningSpec    `json:"tuning"`
+}
+
+// rollbackWorkspace restores the resource, inference and tuning fields of the workspace from the ControllerRevision
+// requested by the rollback-to-revision annotation. Like a Deployment rollback, the restored revision becomes the
+// latest one by getting the next revision number, which is done by syncControllerRevision once the workspace is
+// updated so that the revisions are left untouched if the update fails. The annotation is always removed so that a
+// rollback is attempted only once, and the result is recorded in the RolledBack condition and an event.
+func (c *WorkspaceReconciler) rollbackWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	value, found := wObj.Annotations[kaitov1alpha1.AnnotationRollbackToRevision]
+	if !found {
+		return nil
+	}
+
+	fromRevision := wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation]
+	target, rollbackErr := c.getRollbackRevision(ctx, wObj, value)
+	var data revisionData
+	if rollbackErr == nil {
+		if err := json.Unmarshal(target.Data.Raw, &data); err != nil {
+			rollbackErr = fmt.Errorf("failed to unmarshal revision %d: %w", target.Revision, err)
+		}
+	}
+
+	if rollbackErr != nil {
+		klog.ErrorS(rollbackErr, "failed to roll back workspace", "workspace", klog.KObj(wObj), "revision", value)
+		delete(wObj.Annotations, kaitov1alpha1.AnnotationRollbackToRevision)
+		if err := c.Update(ctx, wObj); err != nil {
+			return fmt.Errorf("failed to remove rollback annotation: %w", err)
+		}
+		c.Recorder.Eventf(wObj, corev1.EventTypeWarning, rollbackFailedReason, "Rollback to revision %s failed: %v", value, rollbackErr)
+		return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeRolledBack, metav1.ConditionFalse,
+			rollbackFailedReason, fmt.Sprintf("rollback to revision %s failed: %v", value, rollbackErr))
+	}
+
+	count := wObj.Resource.Count
+	wObj.Resource = data.Resource
+	wObj.Inference = data.Inference
+	wObj.Tuning = data.Tuning
+	if kaitov1alpha1.IsCountMutable(wObj.Inference) {
+		// The count follows the replicas of the workspace, which are not part of the revisions.
+		wObj.Resource.Count = count
+	}
+	delete(wObj.Annotations, kaitov1alpha1.AnnotationRollbackToRevision)
+	if err := c.Update(ctx, wObj); err != nil {
+		return fmt.Errorf("failed to restore workspace from revision %s: %w", value, err)
+	}
+
+	message := fmt.Sprintf("rolled back from revision %s to revision %s", fromRevision, value)
+	klog.InfoS(message, "workspace", klog.KObj(wObj))
+	c.Recorder.Eventf(wObj, corev1.EventTypeNormal, rollbackSucceededReason, "Rolled back from revision %s to revision %s", fromRevision, value)
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeRolledBack, metav1.ConditionTrue,
+		rollbackSucceededReason, message)
+}
+
+// getRollbackRevision returns the ControllerRevision with the given revision number.
+func (c *WorkspaceReconciler) getRollbackRevision(ctx context.Context, wObj *kaitov1alpha1.Workspace, value string) (*appsv1.ControllerRevision, error) {
+	revisionNum, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revisionNum <= 0 {
+		return nil, fmt.Errorf("invalid revision %q", value)
+	}
+
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions, client.InNamespace(wObj.Namespace), client.MatchingLabels{WorkspaceNameLabel: wObj.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	for i := range revisions.Items {
+		if revisions.Items[i].Revision == revisionNum {
+			return &revisions.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d is not found", revisionNum)
+}
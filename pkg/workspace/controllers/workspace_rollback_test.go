@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRollbackWorkspace(t *testing.T) {
+	test.RegisterTestModel()
+	oldWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+	oldWorkspace.Resource.Count = lo.ToPtr(1)
+	oldWorkspace.Inference.Preset.PresetOptions.Version = "0.0.1"
+	oldData, err := marshalSelectedFields(oldWorkspace)
+	assert.NilError(t, err)
+	newWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+	newWorkspace.Resource.Count = lo.ToPtr(3)
+	newWorkspace.Inference.Preset.PresetOptions.Version = "0.0.2"
+	newData, err := marshalSelectedFields(newWorkspace)
+	assert.NilError(t, err)
+
+	revisions := []*appsv1.ControllerRevision{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "revision-1", Namespace: "kaito"},
+			Revision:   1,
+			Data:       runtime.RawExtension{Raw: oldData},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "revision-2", Namespace: "kaito"},
+			Revision:   2,
+			Data:       runtime.RawExtension{Raw: newData},
+		},
+	}
+
+	testcases := map[string]struct {
+		annotations       map[string]string
+		callMocks         func(c *test.MockClient)
+		expectedVersion   string
+		expectedCondition v1.ConditionStatus
+		expectedError     error
+	}{
+		"No rollback requested": {
+			annotations:     map[string]string{},
+			callMocks:       func(c *test.MockClient) {},
+			expectedVersion: "0.0.2",
+		},
+		"Roll back to a previous revision": {
+			annotations: map[string]string{
+				v1alpha1.AnnotationRollbackToRevision: "1",
+				v1alpha1.WorkspaceRevisionAnnotation:  "2",
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(w *v1alpha1.Workspace) bool {
+					_, found := w.Annotations[v1alpha1.AnnotationRollbackToRevision]
+					// The count of the workspace is kept since it is changed by scaling rather than by revisions.
+					return !found && w.Inference.Preset.PresetOptions.Version == "0.0.1" && *w.Resource.Count == 3
+				}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedVersion:   "0.0.1",
+			expectedCondition: v1.ConditionTrue,
+		},
+		"Revision is not found": {
+			annotations: map[string]string{
+				v1alpha1.AnnotationRollbackToRevision: "5",
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedVersion:   "0.0.2",
+			expectedCondition: v1.ConditionFalse,
+		},
+		"Fail to update workspace": {
+			annotations: map[string]string{
+				v1alpha1.AnnotationRollbackToRevision: "1",
+			},
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(errors.New("conflict"))
+			},
+			expectedVersion: "0.0.1",
+			expectedError:   errors.New("failed to restore workspace from revision 1: conflict"),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			crMap := mockClient.CreateMapWithType(&appsv1.ControllerRevisionList{})
+			for _, cr := range revisions {
+				crMap[client.ObjectKeyFromObject(cr)] = cr.DeepCopy()
+			}
+			tc.callMocks(mockClient)
+
+			workspace := newWorkspace.DeepCopy()
+			workspace.Annotations = tc.annotations
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			err := reconciler.rollbackWorkspace(context.Background(), workspace)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, tc.expectedVersion, workspace.Inference.Preset.PresetOptions.Version)
+			assert.Equal(t, 3, *workspace.Resource.Count)
+			// The revisions are renumbered by syncControllerRevision once the workspace is updated.
+			mockClient.AssertNotCalled(t, "Update", mock.Anything, mock.IsType(&appsv1.ControllerRevision{}), mock.Anything)
+			if tc.expectedCondition != "" {
+				condition := meta.FindStatusCondition(workspace.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeRolledBack))
+				assert.Assert(t, condition != nil)
+				assert.Equal(t, tc.expectedCondition, condition.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/machine"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodeCordonedByAnnotation marks the nodes cordoned by a workspace while they are drained, so that the nodes brought
+// by the user are uncordoned afterwards even if the drain spans several reconciliations.
+const nodeCordonedByAnnotation = "kaito.sh/cordoned-by-workspace"
+
+// releasedPodDeletionCost is the lowest pod deletion cost, given to the pods on the released nodes.
+var releasedPodDeletionCost = strconv.Itoa(math.MinInt32)
+
+// releaseSurplusNodes shrinks the worker nodes of the workspace to the desired count. The released nodes are
+// chosen by utils.SelectNodesToRelease and cordoned while they are drained. The inference workload is scaled down
+// first, removing the pods on the released nodes, and any pods left on them are evicted so that PodDisruptionBudgets
+// are respected. Afterwards, the nodeClaims/machines of the nodes created by the workspace are deleted, while the
+// nodes brought by the user are uncordoned and removed from the worker node list.
+func (c *WorkspaceReconciler) releaseSurplusNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	count := lo.FromPtr(wObj.Resource.Count)
+	if len(wObj.Status.WorkerNodes) <= count {
+		return nil
+	}
+
+	var workerNodes []*corev1.Node
+	for _, name := range wObj.Status.WorkerNodes {
+		node, err := resources.GetNode(ctx, name, c.Client)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		workerNodes = append(workerNodes, node)
+	}
+	keep, release := utils.SelectNodesToRelease(workerNodes, wObj.Resource.PreferredNodes, count)
+
+	if len(release) > 0 {
+		owners, err := c.getWorkspaceNodeOwners(ctx, wObj)
+		if err != nil {
+			return err
+		}
+		podList := &corev1.PodList{}
+		if err := c.Client.List(ctx, podList, client.InNamespace(wObj.Namespace),
+			client.MatchingLabels{kaitov1alpha1.LabelWorkspaceName: wObj.Name}); err != nil {
+			return err
+		}
+
+		var releasedPods []*corev1.Pod
+		for _, node := range release {
+			klog.InfoS("releasing surplus node", "workspace", klog.KObj(wObj), "node", node.Name, "createdByWorkspace", lo.HasKey(owners, node.Name))
+			if err := c.cordonNode(ctx, wObj, node); err != nil {
+				return err
+			}
+			for i := range podList.Items {
+				if pod := &podList.Items[i]; pod.Spec.NodeName == node.Name && pod.DeletionTimestamp.IsZero() {
+					releasedPods = append(releasedPods, pod)
+				}
+			}
+		}
+
+		// The pods on the released nodes are the first ones removed by the ReplicaSet when the workload is scaled down.
+		for _, pod := range releasedPods {
+			if pod.Annotations[corev1.PodDeletionCost] == releasedPodDeletionCost {
+				continue
+			}
+			patch := client.MergeFrom(pod.DeepCopy())
+			if pod.Annotations == nil {
+				pod.Annotations = map[string]string{}
+			}
+			pod.Annotations[corev1.PodDeletionCost] = releasedPodDeletionCost
+			if err := c.Client.Patch(ctx, pod, patch); client.IgnoreNotFound(err) != nil {
+				klog.ErrorS(err, "failed to set the deletion cost of pod", "pod", klog.KObj(pod))
+				return err
+			}
+		}
+		if err := c.scaleDownWorkload(ctx, wObj, count); err != nil {
+			return err
+		}
+		for _, pod := range releasedPods {
+			eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+			if err := c.Client.SubResource("eviction").Create(ctx, pod, eviction); client.IgnoreNotFound(err) != nil {
+				klog.ErrorS(err, "failed to evict pod", "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+				return err
+			}
+		}
+
+		for _, node := range release {
+			if owner, owned := owners[node.Name]; owned {
+				if err := c.Client.Delete(ctx, owner); client.IgnoreNotFound(err) != nil {
+					klog.ErrorS(err, "failed to delete the node owner", "owner", klog.KObj(owner), "node", node.Name)
+					return err
+				}
+			} else if err := c.uncordonNode(ctx, wObj, node); err != nil {
+				return err
+			}
+		}
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "NodesReleased", "Released %d nodes: %v", len(release),
+			lo.Map(release, func(n *corev1.Node, _ int) string { return n.Name }))
+	}
+
+	if err := c.updateStatusNodeListIfNotMatch(ctx, wObj, keep); err != nil {
+		return err
+	}
+	wObj.Status.WorkerNodes = lo.Map(keep, func(n *corev1.Node, _ int) string { return n.Name })
+	return nil
+}
+
+// scaleDownWorkload reduces the replicas of the inference Deployment of the workspace to the count, if the workspace
+// can be scaled. The workload of the other workspaces is left to be drained by evictions.
+func (c *WorkspaceReconciler) scaleDownWorkload(ctx context.Context, wObj *kaitov1alpha1.Workspace, count int) error {
+	if !kaitov1alpha1.IsCountMutable(wObj.Inference) {
+		return nil
+	}
+	deployment := &appsv1.Deployment{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: wObj.Name}, deployment); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas <= int32(count) {
+		return nil
+	}
+	patch := client.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Replicas = lo.ToPtr(int32(count))
+	if err := c.Client.Patch(ctx, deployment, patch); err != nil {
+		klog.ErrorS(err, "failed to scale down the workload", "deployment", klog.KObj(deployment))
+		return err
+	}
+	return nil
+}
+
+// cordonNode marks the node unschedulable, recording that the workspace cordoned it unless it was already cordoned.
+func (c *WorkspaceReconciler) cordonNode(ctx context.Context, wObj *kaitov1alpha1.Workspace, node *corev1.Node) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = true
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[nodeCordonedByAnnotation] = client.ObjectKeyFromObject(wObj).String()
+	if err := c.Client.Patch(ctx, node, patch); err != nil {
+		klog.ErrorS(err, "failed to cordon node", "node", node.Name)
+		return err
+	}
+	return nil
+}
+
+// uncordonNode makes the node schedulable again if it was cordoned by the workspace.
+func (c *WorkspaceReconciler) uncordonNode(ctx context.Context, wObj *kaitov1alpha1.Workspace, node *corev1.Node) error {
+	if node.Annotations[nodeCordonedByAnnotation] != client.ObjectKeyFromObject(wObj).String() {
+		return nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = false
+	delete(node.Annotations, nodeCordonedByAnnotation)
+	if err := c.Client.Patch(ctx, node, patch); err != nil {
+		klog.ErrorS(err, "failed to uncordon node", "node", node.Name)
+		return err
+	}
+	return nil
+}
+
+// getWorkspaceNodeOwners returns the nodeClaims or machines created by the workspace indexed by their node names.
+func (c *WorkspaceReconciler) getWorkspaceNodeOwners(ctx context.Context, wObj *kaitov1alpha1.Workspace) (map[string]client.Object, error) {
+	owners := map[string]client.Object{}
+	if !utils.IsNodeProvisioningEnabled() {
+		return owners, nil
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+		ncList, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ncList.Items {
+			if nodeName := ncList.Items[i].Status.NodeName; nodeName != "" {
+				owners[nodeName] = &ncList.Items[i]
+			}
+		}
+	} else {
+		mList, err := machine.ListMachines(ctx, wObj, c.Client)
+		if err != nil {
+			return nil, err
+		}
+		for i := range mList.Items {
+			if nodeName := mList.Items[i].Status.NodeName; nodeName != "" {
+				owners[nodeName] = &mList.Items[i]
+			}
+		}
+	}
+	return owners, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+func TestReleaseSurplusNodes(t *testing.T) {
+	test.RegisterTestModel()
+	now := time.Now()
+	oldNode := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-1", CreationTimestamp: v1.NewTime(now.Add(-time.Hour))}}
+	newNode := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-2", CreationTimestamp: v1.NewTime(now.Add(-time.Minute))}}
+	podOnNewNode := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "pod-2", Namespace: "kaito"},
+		Spec:       corev1.PodSpec{NodeName: "node-2"},
+	}
+	nodeClaim := &v1beta1.NodeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "nodeclaim-2"},
+		Status:     v1beta1.NodeClaimStatus{NodeName: "node-2"},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: v1.ObjectMeta{Name: "testWorkspace", Namespace: "kaito"},
+		Spec:       appsv1.DeploymentSpec{Replicas: lo.ToPtr(int32(2))},
+	}
+	cordoned := func(name string) interface{} {
+		return mock.MatchedBy(func(n *corev1.Node) bool {
+			return n.Name == name && n.Spec.Unschedulable && n.Annotations[nodeCordonedByAnnotation] == "kaito/testWorkspace"
+		})
+	}
+	uncordoned := func(name string) interface{} {
+		return mock.MatchedBy(func(n *corev1.Node) bool {
+			return n.Name == name && !n.Spec.Unschedulable && !lo.HasKey(n.Annotations, nodeCordonedByAnnotation)
+		})
+	}
+	podOnNewNodeWithDeletionCost := mock.MatchedBy(func(p *corev1.Pod) bool {
+		return p.Name == "pod-2" && p.Annotations[corev1.PodDeletionCost] == "-2147483648"
+	})
+	scaledDown := mock.MatchedBy(func(d *appsv1.Deployment) bool {
+		return *d.Spec.Replicas == 1
+	})
+
+	testcases := map[string]struct {
+		count               int
+		preferredNodes      []string
+		nodeClaims          []*v1beta1.NodeClaim
+		callMocks           func(c *test.MockClient)
+		expectedWorkerNodes []string
+		expectedError       error
+	}{
+		"Count is not reduced": {
+			count:               2,
+			callMocks:           func(c *test.MockClient) {},
+			expectedWorkerNodes: []string{"node-1", "node-2"},
+		},
+		"Release the most recently created node and delete its nodeClaim": {
+			count:      1,
+			nodeClaims: []*v1beta1.NodeClaim{nodeClaim},
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), cordoned("node-2"), mock.Anything, mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), podOnNewNodeWithDeletionCost, mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), scaledDown, mock.Anything, mock.Anything).Return(nil)
+				c.SubResourceMock.On("Create", mock.IsType(context.Background()), mock.MatchedBy(func(p *corev1.Pod) bool {
+					return p.Name == "pod-2"
+				}), mock.IsType(&policyv1.Eviction{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.MatchedBy(func(nc *v1beta1.NodeClaim) bool {
+					return nc.Name == "nodeclaim-2"
+				}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedWorkerNodes: []string{"node-1"},
+		},
+		"Node not created by the workspace is cordoned during the drain and not deleted": {
+			count: 1,
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), cordoned("node-2"), mock.Anything, mock.Anything).Return(nil).Once()
+				c.On("Patch", mock.IsType(context.Background()), podOnNewNodeWithDeletionCost, mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), scaledDown, mock.Anything, mock.Anything).Return(nil)
+				c.SubResourceMock.On("Create", mock.IsType(context.Background()), mock.IsType(&corev1.Pod{}), mock.IsType(&policyv1.Eviction{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), uncordoned("node-2"), mock.Anything, mock.Anything).Return(nil).Once()
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedWorkerNodes: []string{"node-1"},
+		},
+		"Preferred node is kept": {
+			count:          1,
+			preferredNodes: []string{"node-2"},
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), cordoned("node-1"), mock.Anything, mock.Anything).Return(nil).Once()
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), scaledDown, mock.Anything, mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), uncordoned("node-1"), mock.Anything, mock.Anything).Return(nil).Once()
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedWorkerNodes: []string{"node-2"},
+		},
+		"Eviction is blocked by a PodDisruptionBudget": {
+			count:      1,
+			nodeClaims: []*v1beta1.NodeClaim{nodeClaim},
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), cordoned("node-2"), mock.Anything, mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), podOnNewNodeWithDeletionCost, mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), scaledDown, mock.Anything, mock.Anything).Return(nil)
+				c.SubResourceMock.On("Create", mock.IsType(context.Background()), mock.IsType(&corev1.Pod{}), mock.IsType(&policyv1.Eviction{}), mock.Anything).
+					Return(apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10))
+			},
+			expectedWorkerNodes: []string{"node-1", "node-2"},
+			expectedError:       errors.New("Cannot evict pod as it would violate the pod's disruption budget."),
+		},
+		"Fail to delete nodeClaim": {
+			count:      1,
+			nodeClaims: []*v1beta1.NodeClaim{nodeClaim},
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.SubResourceMock.On("Create", mock.IsType(context.Background()), mock.IsType(&corev1.Pod{}), mock.IsType(&policyv1.Eviction{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(errors.New("failed to delete nodeClaim"))
+			},
+			expectedWorkerNodes: []string{"node-1", "node-2"},
+			expectedError:       errors.New("failed to delete nodeClaim"),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			originalKarpenter := featuregates.FeatureGates[consts.FeatureFlagKarpenter]
+			defer func() { featuregates.FeatureGates[consts.FeatureFlagKarpenter] = originalKarpenter }()
+			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = true
+
+			mockClient := test.NewClient()
+			mockClient.CreateOrUpdateObjectInMap(oldNode.DeepCopy())
+			mockClient.CreateOrUpdateObjectInMap(newNode.DeepCopy())
+			podMap := mockClient.CreateMapWithType(&corev1.PodList{})
+			podMap[client.ObjectKeyFromObject(podOnNewNode)] = podOnNewNode.DeepCopy()
+			mockClient.CreateOrUpdateObjectInMap(deployment.DeepCopy())
+			ncMap := mockClient.CreateMapWithType(&v1beta1.NodeClaimList{})
+			for _, nc := range tc.nodeClaims {
+				ncMap[client.ObjectKeyFromObject(nc)] = nc.DeepCopy()
+			}
+			tc.callMocks(mockClient)
+
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Resource.Count = lo.ToPtr(tc.count)
+			workspace.Resource.PreferredNodes = tc.preferredNodes
+			workspace.Status.WorkerNodes = []string{"node-1", "node-2"}
+
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			err := reconciler.releaseSurplusNodes(context.Background(), workspace)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.Check(t, err == nil, "Not expected to return error")
+			}
+			assert.DeepEqual(t, tc.expectedWorkerNodes, workspace.Status.WorkerNodes)
+			mockClient.AssertExpectations(t)
+			mockClient.SubResourceMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxScheduleWindowChain bounds the number of back-to-back windows merged to find the end of the running period.
+const maxScheduleWindowChain = 100
+
+// applySchedule starts and stops the inference workload according to the schedule of the workspace and the schedule
+// override annotation. It returns true while the workspace is stopped, in which case neither nodes nor the workload
+// should be applied, and the next time the schedule changes the state of the workspace, which is zero if unknown.
+func (c *WorkspaceReconciler) applySchedule(ctx context.Context, wObj *kaitov1alpha1.Workspace) (bool, time.Time, error) {
+	schedule := wObj.Inference.Schedule
+	if schedule == nil {
+		if meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeScheduleActive)) != nil {
+			if err := c.updateScheduleStatus(ctx, wObj, true, "ScheduleRemoved", "the schedule has been removed", time.Time{}); err != nil {
+				return false, time.Time{}, err
+			}
+		}
+		return false, time.Time{}, nil
+	}
+
+	running, next, err := evaluateSchedule(schedule, time.Now())
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	reason, message := "InsideWindow", "the inference workload runs inside a window of the schedule"
+	if !running {
+		reason, message = "OutsideWindow", "the inference workload is stopped outside the windows of the schedule"
+	}
+	switch wObj.Annotations[kaitov1alpha1.AnnotationScheduleOverride] {
+	case kaitov1alpha1.ScheduleOverrideRunning:
+		running, next = true, time.Time{}
+		reason, message = "Overridden", fmt.Sprintf("the schedule is overridden by the %s annotation", kaitov1alpha1.AnnotationScheduleOverride)
+	case kaitov1alpha1.ScheduleOverrideStopped:
+		running, next = false, time.Time{}
+		reason, message = "Overridden", fmt.Sprintf("the schedule is overridden by the %s annotation", kaitov1alpha1.AnnotationScheduleOverride)
+	}
+
+	wasRunning := !meta.IsStatusConditionFalse(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeScheduleActive))
+	if running {
+		if err := c.updateScheduleStatus(ctx, wObj, true, reason, message, next); err != nil {
+			return false, time.Time{}, err
+		}
+		if !wasRunning {
+			klog.InfoS("starting the workspace by its schedule", "workspace", klog.KObj(wObj))
+			c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "ScheduleStarted", "Starting the inference workload: %s", message)
+		}
+		return false, next, nil
+	}
+
+	if wasRunning {
+		klog.InfoS("stopping the workspace by its schedule", "workspace", klog.KObj(wObj))
+	}
+	if err := c.scaleToZero(ctx, wObj, kaitov1alpha1.WorkspacePhaseStopped, "WorkspaceStopped", message); err != nil {
+		return false, time.Time{}, err
+	}
+	// The workspace is brought back by the schedule rather than by requests, so it is no longer idle.
+	if meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeScaledToZero)) {
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeScaledToZero, metav1.ConditionFalse,
+			"WorkspaceStopped", "the workspace is stopped by its schedule"); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return false, time.Time{}, err
+		}
+	}
+	if err := c.updateScheduleStatus(ctx, wObj, false, reason, message, next); err != nil {
+		return false, time.Time{}, err
+	}
+	if wasRunning {
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, "ScheduleStopped", "Stopping the inference workload: %s", message)
+	}
+	return true, next, nil
+}
+
+// updateScheduleStatus records the schedule state in the ScheduleActive condition and the next transition time.
+func (c *WorkspaceReconciler) updateScheduleStatus(ctx context.Context, wObj *kaitov1alpha1.Workspace, running bool,
+	reason, message string, next time.Time) error {
+	status := metav1.ConditionTrue
+	if !running {
+		status = metav1.ConditionFalse
+	}
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeScheduleActive, status, reason, message); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+
+	var nextTime *metav1.Time
+	if !next.IsZero() {
+		nextTime = &metav1.Time{Time: next}
+	}
+	current := wObj.Status.NextScheduleTransitionTime
+	if (current == nil && nextTime == nil) || (current != nil && nextTime != nil && current.Equal(nextTime)) {
+		return nil
+	}
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace},
+		func(status *kaitov1alpha1.WorkspaceStatus) {
+			status.NextScheduleTransitionTime = nextTime.DeepCopy()
+		}); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	wObj.Status.NextScheduleTransitionTime = nextTime
+	return nil
+}
+
+// evaluateSchedule returns whether the inference workload should be running at now and the next time this changes.
+// The workload runs from LeadMinutes before the start of a window until the end of the window.
+func evaluateSchedule(schedule *kaitov1alpha1.ScheduleSpec, now time.Time) (bool, time.Time, error) {
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid time zone %s: %w", schedule.TimeZone, err)
+		}
+	}
+	now = now.In(location)
+	lead := time.Duration(schedule.LeadMinutes) * time.Minute
+
+	type window struct {
+		start    cron.Schedule
+		duration time.Duration
+	}
+	windows := make([]window, 0, len(schedule.Windows))
+	for _, w := range schedule.Windows {
+		start, err := cron.ParseStandard(w.Start)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid cron expression %q: %w", w.Start, err)
+		}
+		windows = append(windows, window{start: start, duration: w.Duration.Duration})
+	}
+
+	// A window is running at now if it starts in (now-duration, now+lead]. The earliest start after now-duration
+	// tells whether there is such a window, or else when the next window starts.
+	var end, nextStart time.Time
+	for _, w := range windows {
+		start := w.start.Next(now.Add(-w.duration))
+		if start.IsZero() {
+			continue
+		}
+		if !start.After(now.Add(lead)) {
+			if windowEnd := start.Add(w.duration); windowEnd.After(end) {
+				end = windowEnd
+			}
+		} else if startTime := start.Add(-lead); nextStart.IsZero() || startTime.Before(nextStart) {
+			nextStart = startTime
+		}
+	}
+	if end.IsZero() {
+		return false, nextStart, nil
+	}
+
+	// Merge the windows that overlap with the running period, or start within the lead time of its end.
+	for i := 0; i < maxScheduleWindowChain; i++ {
+		extended := false
+		for _, w := range windows {
+			start := w.start.Next(end.Add(-w.duration))
+			if !start.IsZero() && !start.After(end.Add(lead)) {
+				end = start.Add(w.duration)
+				extended = true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return true, end, nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	weekdays := v1alpha1.ScheduleWindow{Start: "0 8 * * 1-5", Duration: v1.Duration{Duration: 12 * time.Hour}}
+	// 2024-05-15 is a Wednesday.
+	utc := func(day, hour, minute int) time.Time { return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC) }
+
+	testcases := map[string]struct {
+		schedule        v1alpha1.ScheduleSpec
+		now             time.Time
+		expectedRunning bool
+		expectedNext    time.Time
+	}{
+		"Inside a window": {
+			schedule:        v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{weekdays}},
+			now:             utc(15, 10, 0),
+			expectedRunning: true,
+			expectedNext:    utc(15, 20, 0),
+		},
+		"After a window": {
+			schedule:     v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{weekdays}},
+			now:          utc(15, 20, 0),
+			expectedNext: utc(16, 8, 0),
+		},
+		"Over the weekend": {
+			schedule:     v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{weekdays}},
+			now:          utc(17, 21, 0),
+			expectedNext: utc(20, 8, 0),
+		},
+		"Within the lead time": {
+			schedule:        v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{weekdays}, LeadMinutes: 30},
+			now:             utc(15, 7, 45),
+			expectedRunning: true,
+			expectedNext:    utc(15, 20, 0),
+		},
+		"Before the lead time": {
+			schedule:     v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{weekdays}, LeadMinutes: 30},
+			now:          utc(15, 7, 0),
+			expectedNext: utc(15, 7, 30),
+		},
+		"Time zone": {
+			schedule:        v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{weekdays}, TimeZone: "Europe/Berlin"},
+			now:             utc(15, 7, 0),
+			expectedRunning: true,
+			expectedNext:    utc(15, 18, 0),
+		},
+		"Back-to-back windows are merged": {
+			schedule: v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{
+				{Start: "0 8 * * *", Duration: v1.Duration{Duration: 4 * time.Hour}},
+				{Start: "0 12 * * *", Duration: v1.Duration{Duration: 4 * time.Hour}},
+			}},
+			now:             utc(15, 9, 0),
+			expectedRunning: true,
+			expectedNext:    utc(15, 16, 0),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			running, next, err := evaluateSchedule(&tc.schedule, tc.now)
+			assert.Check(t, err == nil, "Not expected to return error")
+			assert.Equal(t, tc.expectedRunning, running)
+			assert.Check(t, next.Equal(tc.expectedNext), "expected next transition %v, got %v", tc.expectedNext, next)
+		})
+	}
+}
+
+func TestApplySchedule(t *testing.T) {
+	testcases := map[string]struct {
+		override        string
+		wasStopped      bool
+		callMocks       func(c *test.MockClient)
+		expectedStopped bool
+		expectedEvent   string
+		expectedPhase   v1alpha1.WorkspacePhase
+	}{
+		"Running workspace is stopped": {
+			override: v1alpha1.ScheduleOverrideStopped,
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).
+					Return(apierrors.NewNotFound(schema.GroupResource{}, "testWorkspace"))
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			},
+			expectedStopped: true,
+			expectedEvent:   "Normal ScheduleStopped Stopping the inference workload: the schedule is overridden by the kaito.sh/schedule-override annotation",
+			expectedPhase:   v1alpha1.WorkspacePhaseStopped,
+		},
+		"Stopped workspace is started": {
+			override:   v1alpha1.ScheduleOverrideRunning,
+			wasStopped: true,
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			expectedEvent: "Normal ScheduleStarted Starting the inference workload: the schedule is overridden by the kaito.sh/schedule-override annotation",
+		},
+		"Running workspace keeps running": {
+			override:  v1alpha1.ScheduleOverrideRunning,
+			callMocks: func(c *test.MockClient) {},
+		},
+	}
+
+	originalKarpenter := featuregates.FeatureGates[consts.FeatureFlagKarpenter]
+	defer func() { featuregates.FeatureGates[consts.FeatureFlagKarpenter] = originalKarpenter }()
+	featuregates.FeatureGates[consts.FeatureFlagKarpenter] = false
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Annotations[v1alpha1.AnnotationScheduleOverride] = tc.override
+			workspace.Inference.Schedule = &v1alpha1.ScheduleSpec{Windows: []v1alpha1.ScheduleWindow{
+				{Start: "0 8 * * 1-5", Duration: v1.Duration{Duration: 12 * time.Hour}},
+			}}
+			status := v1.ConditionTrue
+			if tc.wasStopped {
+				status = v1.ConditionFalse
+			}
+			meta.SetStatusCondition(&workspace.Status.Conditions, v1.Condition{
+				Type:    string(v1alpha1.WorkspaceConditionTypeScheduleActive),
+				Status:  status,
+				Reason:  "Overridden",
+				Message: "the schedule is overridden by the kaito.sh/schedule-override annotation",
+			})
+
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+
+			stopped, next, err := reconciler.applySchedule(context.Background(), workspace)
+			assert.Check(t, err == nil, "Not expected to return error")
+			assert.Equal(t, tc.expectedStopped, stopped)
+			assert.Check(t, next.IsZero(), "Expected no scheduled transition with an override")
+			if tc.expectedEvent != "" {
+				assert.Equal(t, 1, len(recorder.Events))
+				assert.Equal(t, tc.expectedEvent, <-recorder.Events)
+			} else {
+				assert.Equal(t, 0, len(recorder.Events))
+			}
+			assert.Equal(t, !tc.expectedStopped, meta.IsStatusConditionTrue(workspace.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeScheduleActive)))
+			if tc.expectedStopped {
+				mockClient.AssertCalled(t, "Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything)
+				assert.Equal(t, tc.expectedPhase, workspace.Status.Phase)
+			}
+		})
+	}
+}
@@ -5,22 +5,40 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sort"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (c *WorkspaceReconciler) updateWorkspaceStatus(ctx context.Context, name *client.ObjectKey, condition *metav1.Condition, workerNodes []string) error {
+	return c.updateWorkspaceStatusWith(ctx, name, func(status *kaitov1alpha1.WorkspaceStatus) {
+		if condition != nil {
+			meta.SetStatusCondition(&status.Conditions, *condition)
+		}
+		if workerNodes != nil {
+			status.WorkerNodes = workerNodes
+		}
+	})
+}
+
+// updateWorkspaceStatusWith applies the modifyStatus function to the latest version of the workspace status and persists it.
+func (c *WorkspaceReconciler) updateWorkspaceStatusWith(ctx context.Context, name *client.ObjectKey, modifyStatus func(*kaitov1alpha1.WorkspaceStatus)) error {
 	return retry.OnError(retry.DefaultRetry,
 		func(err error) bool {
 			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
@@ -34,12 +52,7 @@ func (c *WorkspaceReconciler) updateWorkspaceStatus(ctx context.Context, name *c
 				}
 				return nil
 			}
-			if condition != nil {
-				meta.SetStatusCondition(&wObj.Status.Conditions, *condition)
-			}
-			if workerNodes != nil {
-				wObj.Status.WorkerNodes = workerNodes
-			}
+			modifyStatus(&wObj.Status)
 			return c.Client.Status().Update(ctx, wObj)
 		})
 }
@@ -60,7 +73,133 @@ func (c *WorkspaceReconciler) updateStatusConditionIfNotMatch(ctx context.Contex
 		ObservedGeneration: wObj.GetGeneration(),
 		Message:            cMessage,
 	}
-	return c.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, &cObj, nil)
+	if err := c.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, &cObj, nil); err != nil {
+		return err
+	}
+	// Keep the in-memory object consistent so that later checks in the same reconcile see the new condition.
+	meta.SetStatusCondition(&wObj.Status.Conditions, cObj)
+	return nil
+}
+
+// updateStatusPhaseIfNotMatch records the phase together with the generation of the workspace it was computed for.
+func (c *WorkspaceReconciler) updateStatusPhaseIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, phase kaitov1alpha1.WorkspacePhase) error {
+	generation := wObj.GetGeneration()
+	if wObj.Status.Phase == phase && wObj.Status.ObservedGeneration == generation {
+		return nil
+	}
+	klog.InfoS("updateStatusPhase", "workspace", klog.KObj(wObj), "phase", phase, "generation", generation)
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.Phase = phase
+		status.ObservedGeneration = generation
+	}); err != nil {
+		return err
+	}
+	wObj.Status.Phase = phase
+	wObj.Status.ObservedGeneration = generation
+	return nil
+}
+
+func (c *WorkspaceReconciler) updateStatusInstanceTypeIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, instanceType string) error {
+	if wObj.Status.InstanceType == instanceType {
+		return nil
+	}
+	klog.InfoS("updateStatusInstanceType", "workspace", klog.KObj(wObj), "instanceType", instanceType)
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.InstanceType = instanceType
+	}); err != nil {
+		return err
+	}
+	wObj.Status.InstanceType = instanceType
+	return nil
+}
+
+// updateStatusInferenceIfNotMatch summarizes the replicas and the resolved configuration of the inference workload
+// in the workspace status.
+func (c *WorkspaceReconciler) updateStatusInferenceIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, workloadObj client.Object) error {
+	readyReplicas, desiredReplicas, inferenceStatus := getInferenceStatus(wObj, workloadObj)
+	// The selector of the inference pods is reported to the scale subresource, e.g., for the HPA to read pod metrics.
+	selector := labels.SelectorFromSet(labels.Set{kaitov1alpha1.LabelWorkspaceName: wObj.Name}).String()
+	if wObj.Status.ReadyReplicas == readyReplicas && wObj.Status.DesiredReplicas == desiredReplicas &&
+		reflect.DeepEqual(wObj.Status.Inference, inferenceStatus) && wObj.Status.Selector == selector {
+		return nil
+	}
+	klog.InfoS("updateStatusInference", "workspace", klog.KObj(wObj), "readyReplicas", readyReplicas, "desiredReplicas", desiredReplicas)
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.ReadyReplicas = readyReplicas
+		status.DesiredReplicas = desiredReplicas
+		status.Inference = inferenceStatus.DeepCopy()
+		status.Selector = selector
+	}); err != nil {
+		return err
+	}
+	wObj.Status.ReadyReplicas = readyReplicas
+	wObj.Status.DesiredReplicas = desiredReplicas
+	wObj.Status.Inference = inferenceStatus
+	wObj.Status.Selector = selector
+	return nil
+}
+
+// updateStatusTuningOutputPathIfNotMatch records the directory in the output volume that contains the results of the
+// completed tuning job, so that other workspaces can consume them.
+func (c *WorkspaceReconciler) updateStatusTuningOutputPathIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, outputPath string) error {
+	if wObj.Status.Tuning != nil && wObj.Status.Tuning.OutputPath == outputPath {
+		return nil
+	}
+	klog.InfoS("updateStatusTuningOutputPath", "workspace", klog.KObj(wObj), "outputPath", outputPath)
+	setOutputPath := func(status *kaitov1alpha1.WorkspaceStatus) {
+		if status.Tuning == nil {
+			status.Tuning = &kaitov1alpha1.TuningStatus{}
+		}
+		status.Tuning.OutputPath = outputPath
+	}
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, setOutputPath); err != nil {
+		return err
+	}
+	setOutputPath(&wObj.Status)
+	return nil
+}
+
+func getInferenceStatus(wObj *kaitov1alpha1.Workspace, workloadObj client.Object) (int32, int32, *kaitov1alpha1.InferenceStatus) {
+	var readyReplicas, desiredReplicas int32
+	var podSpec *corev1.PodSpec
+	switch workload := workloadObj.(type) {
+	case *appsv1.Deployment:
+		readyReplicas = workload.Status.ReadyReplicas
+		desiredReplicas = lo.FromPtrOr(workload.Spec.Replicas, 1)
+		podSpec = &workload.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		readyReplicas = workload.Status.ReadyReplicas
+		desiredReplicas = lo.FromPtrOr(workload.Spec.Replicas, 1)
+		podSpec = &workload.Spec.Template.Spec
+	}
+
+	serviceName := wObj.Name
+	if wObj.Inference.IdlePolicy != nil {
+		// Requests must go through the activator to keep the workspace active and to bring it back from zero.
+		serviceName = manifests.GetActivatorName(wObj)
+	}
+	inferenceStatus := &kaitov1alpha1.InferenceStatus{
+		Endpoint: fmt.Sprintf("http://%s:%d", utils.GetServiceHost(serviceName, wObj.Namespace), manifests.InferenceServicePort),
+		Port:     manifests.InferenceServicePort,
+	}
+	if podSpec != nil && len(podSpec.Containers) > 0 {
+		inferenceStatus.Image = podSpec.Containers[0].Image
+	}
+	if wObj.Inference.Preset != nil {
+		inferenceStatus.Runtime = string(kaitov1alpha1.GetWorkspaceRuntimeName(wObj))
+		// The preset of a deleted ModelPreset is no longer registered, leaving only the pinned version, if any.
+		var defaultTag string
+		if presetModel := plugin.KaitoModelRegister.Get(string(wObj.Inference.Preset.Name)); presetModel != nil {
+			defaultTag = presetModel.GetInferenceParameters().Tag
+		}
+		inferenceStatus.PresetTag = kaitov1alpha1.GetPresetTag(wObj.Inference.Preset, defaultTag)
+	}
+	for _, adapter := range wObj.Inference.Adapters {
+		if adapter.Source != nil && adapter.Source.Name != "" {
+			inferenceStatus.Adapters = append(inferenceStatus.Adapters, adapter.Source.Name)
+		}
+	}
+	return readyReplicas, desiredReplicas, inferenceStatus
 }
 
 func (c *WorkspaceReconciler) updateStatusNodeListIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, validNodeList []*corev1.Node) error {
@@ -9,9 +9,13 @@ import (
 	"testing"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -170,3 +174,113 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestUpdateStatusInferenceIfNotMatch(t *testing.T) {
+	test.RegisterTestModel()
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: lo.ToPtr(int32(2)),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "testWorkspace", Image: "registry/kaito-test-model:0.0.1"}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+
+	t.Run("Should record the inference workload in the status", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
+		}
+		ctx := context.Background()
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Inference.Adapters = []kaitov1alpha1.AdapterSpec{{Source: &kaitov1alpha1.DataSource{Name: "adapter-1"}}}
+
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+		err := reconciler.updateStatusInferenceIfNotMatch(ctx, workspace, deployment)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), workspace.Status.ReadyReplicas)
+		assert.Equal(t, int32(2), workspace.Status.DesiredReplicas)
+		assert.Equal(t, &kaitov1alpha1.InferenceStatus{
+			Endpoint: "http://testWorkspace.kaito.svc:80",
+			Port:     80,
+			Runtime:  "transformers",
+			Image:    "registry/kaito-test-model:0.0.1",
+			Adapters: []string{"adapter-1"},
+		}, workspace.Status.Inference)
+		assert.Equal(t, "kaito.sh/workspace=testWorkspace", workspace.Status.Selector)
+		mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("Should report the activator endpoint with an idle policy", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Inference.IdlePolicy = &kaitov1alpha1.IdlePolicySpec{IdleMinutes: 30}
+
+		_, _, inferenceStatus := getInferenceStatus(workspace, deployment)
+		assert.Equal(t, "http://testWorkspace-activator.kaito.svc:80", inferenceStatus.Endpoint)
+	})
+
+	t.Run("Should report the endpoint with the cluster domain", func(t *testing.T) {
+		t.Setenv(consts.ClusterDomainEnvVar, "example.internal")
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+
+		_, _, inferenceStatus := getInferenceStatus(workspace, deployment)
+		assert.Equal(t, "http://testWorkspace.kaito.svc.example.internal:80", inferenceStatus.Endpoint)
+	})
+
+	t.Run("Should not update when the status matches", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
+		}
+		ctx := context.Background()
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Status.ReadyReplicas, workspace.Status.DesiredReplicas, workspace.Status.Inference = getInferenceStatus(workspace, deployment)
+		workspace.Status.Selector = "kaito.sh/workspace=testWorkspace"
+
+		err := reconciler.updateStatusInferenceIfNotMatch(ctx, workspace, deployment)
+		assert.Nil(t, err)
+		mockClient.StatusMock.AssertNotCalled(t, "Update")
+	})
+}
+
+func TestUpdateStatusTuningOutputPathIfNotMatch(t *testing.T) {
+	t.Run("Should record the output path in the status", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
+		}
+		ctx := context.Background()
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+		err := reconciler.updateStatusTuningOutputPathIfNotMatch(ctx, workspace, "testWorkspace/2")
+		assert.Nil(t, err)
+		assert.Equal(t, &kaitov1alpha1.TuningStatus{OutputPath: "testWorkspace/2"}, workspace.Status.Tuning)
+		mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("Should not update when the output path matches", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
+		}
+		ctx := context.Background()
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Status.Tuning = &kaitov1alpha1.TuningStatus{OutputPath: "testWorkspace/2"}
+
+		err := reconciler.updateStatusTuningOutputPathIfNotMatch(ctx, workspace, "testWorkspace/2")
+		assert.Nil(t, err)
+		mockClient.StatusMock.AssertNotCalled(t, "Update")
+	})
+}
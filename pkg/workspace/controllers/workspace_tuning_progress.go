@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/workspace/tuning"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	tuningEpochCompletedReason  = "TuningEpochCompleted"
+	tuningCheckpointSavedReason = "TuningCheckpointSaved"
+	tuningResumedReason         = "TuningResumed"
+	tuningProgressMissingReason = "TuningProgressNotReported"
+)
+
+// errTuningProgressNotFound is returned when the metrics server of a tuning pod has no progress to report.
+var errTuningProgressNotFound = errors.New("tuning progress not found")
+
+var (
+	// tuningProgressInterval is the interval to scrape the progress of a running tuning job.
+	tuningProgressInterval = 30 * time.Second
+	// tuningProgressStartTimeout bounds the time for a tuning pod to load the model and the dataset and start the
+	// training. A pod that has not reported its progress since is assumed to run an image that cannot report it.
+	tuningProgressStartTimeout = 20 * time.Minute
+
+	tuningProgressHTTPClient = &http.Client{Timeout: 5 * time.Second}
+	// scrapeTuningProgress fetches the training progress from the metrics server of a tuning pod. It is a variable
+	// for testing.
+	scrapeTuningProgress = func(ctx context.Context, pod *corev1.Pod) (*tuning.Progress, error) {
+		url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, tuning.Port5000, tuning.ProgressPath)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := tuningProgressHTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			// Either the training has not started yet, or the image predates the progress endpoint.
+			return nil, errTuningProgressNotFound
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+		progress := &tuning.Progress{}
+		if err := json.NewDecoder(resp.Body).Decode(progress); err != nil {
+			return nil, fmt.Errorf("failed to decode progress from %s: %w", url, err)
+		}
+		return progress, nil
+	}
+)
+
+// updateTuningProgress scrapes the progress of the running tuning job into the tuning status of the workspace, and
+// records an event when an epoch is completed, a checkpoint is saved or the job resumes from a checkpoint. The
+// progress is best effort, a tuning pod that does not report it is not an error.
+func (c *WorkspaceReconciler) updateTuningProgress(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	progress, err := c.getTuningProgress(ctx, wObj)
+	if err != nil || progress == nil {
+		return err
+	}
+
+	var previous *kaitov1alpha1.TuningProgress
+	var previousResumeCount int32
+	if wObj.Status.Tuning != nil {
+		previous = wObj.Status.Tuning.Progress
+		previousResumeCount = wObj.Status.Tuning.ResumeCount
+	}
+	current := progress.ToTuningProgress()
+	resumeCount := max(progress.ResumeCount, previousResumeCount)
+	if reflect.DeepEqual(previous, current) && resumeCount == previousResumeCount {
+		return nil
+	}
+
+	setProgress := func(status *kaitov1alpha1.WorkspaceStatus) {
+		if status.Tuning == nil {
+			status.Tuning = &kaitov1alpha1.TuningStatus{}
+		}
+		status.Tuning.Progress = current
+		status.Tuning.ResumeCount = resumeCount
+	}
+	if err := c.updateWorkspaceStatusWith(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, setProgress); err != nil {
+		return err
+	}
+	setProgress(&wObj.Status)
+
+	if resumeCount > previousResumeCount && progress.ResumedFromCheckpoint != nil {
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, tuningResumedReason, "Tuning resumed from checkpoint %s after the pod was recreated, %d resumes so far",
+			*progress.ResumedFromCheckpoint, resumeCount)
+	}
+	if epoch := completedEpochs(current); epoch > completedEpochs(previous) {
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, tuningEpochCompletedReason, "Epoch %d of %d completed at step %d, training loss %s",
+			epoch, current.TotalEpochs, current.Step, current.TrainingLoss)
+	}
+	if current.LastCheckpoint != "" && (previous == nil || previous.LastCheckpoint != current.LastCheckpoint) &&
+		(progress.ResumedFromCheckpoint == nil || *progress.ResumedFromCheckpoint != current.LastCheckpoint) {
+		c.Recorder.Eventf(wObj, corev1.EventTypeNormal, tuningCheckpointSavedReason, "Checkpoint %s saved", current.LastCheckpoint)
+	}
+	return nil
+}
+
+// getTuningProgress returns the progress reported by the first running tuning pod of the workspace, or nil if no
+// pod reports it yet. A warning event is recorded if a pod still reports no progress long after it started.
+func (c *WorkspaceReconciler) getTuningProgress(ctx context.Context, wObj *kaitov1alpha1.Workspace) (*tuning.Progress, error) {
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1alpha1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return nil, err
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		progress, err := scrapeTuningProgress(ctx, pod)
+		if errors.Is(err, errTuningProgressNotFound) {
+			// Once a progress has been reported, the image is known to report it, e.g., a pod resumed from a
+			// checkpoint has not reached its first step yet.
+			if pod.Status.StartTime != nil && time.Since(pod.Status.StartTime.Time) > tuningProgressStartTimeout &&
+				(wObj.Status.Tuning == nil || wObj.Status.Tuning.Progress == nil) {
+				c.Recorder.Eventf(wObj, corev1.EventTypeWarning, tuningProgressMissingReason,
+					"Pod %s has not reported the tuning progress %s after it started, its preset image may not support it",
+					pod.Name, tuningProgressStartTimeout)
+			}
+			continue
+		}
+		if err != nil {
+			klog.ErrorS(err, "failed to scrape tuning progress", "workspace", klog.KObj(wObj), "pod", klog.KObj(pod))
+			continue
+		}
+		if progress != nil {
+			return progress, nil
+		}
+	}
+	return nil, nil
+}
+
+// completedEpochs returns the number of whole epochs completed according to the progress.
+func completedEpochs(progress *kaitov1alpha1.TuningProgress) int64 {
+	if progress == nil {
+		return 0
+	}
+	epoch, err := strconv.ParseFloat(progress.Epoch, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(math.Floor(epoch))
+}
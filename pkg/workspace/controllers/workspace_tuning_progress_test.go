@@ -0,0 +1,162 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/tuning"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdateTuningProgress(t *testing.T) {
+	tuningPod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "testWorkspace-abcde",
+			Namespace: "kaito",
+			Labels:    map[string]string{v1alpha1.LabelWorkspaceName: "testWorkspace"},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+		},
+	}
+	statusMocks := func(c *test.MockClient) {
+		c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+		c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+	}
+
+	testcases := map[string]struct {
+		previous         *v1alpha1.TuningProgress
+		startedAgo       time.Duration
+		progress         *tuning.Progress
+		scrapeErr        error
+		callMocks        func(c *test.MockClient)
+		expectedProgress *v1alpha1.TuningProgress
+		expectedResumes  int32
+		expectedEvents   []string
+	}{
+		"Training has not started": {
+			startedAgo: time.Minute,
+			scrapeErr:  errTuningProgressNotFound,
+			callMocks:  func(c *test.MockClient) {},
+		},
+		"Image does not report progress": {
+			startedAgo: time.Hour,
+			scrapeErr:  errTuningProgressNotFound,
+			callMocks:  func(c *test.MockClient) {},
+			expectedEvents: []string{
+				"Warning TuningProgressNotReported Pod testWorkspace-abcde has not reported the tuning progress 20m0s after it started, its preset image may not support it",
+			},
+		},
+		"Resumed training has not reached its first step": {
+			previous:         &v1alpha1.TuningProgress{Epoch: "1.00", TotalEpochs: 2, Step: 100, TotalSteps: 200},
+			startedAgo:       time.Hour,
+			scrapeErr:        errTuningProgressNotFound,
+			callMocks:        func(c *test.MockClient) {},
+			expectedProgress: &v1alpha1.TuningProgress{Epoch: "1.00", TotalEpochs: 2, Step: 100, TotalSteps: 200},
+		},
+		"First progress is reported": {
+			progress: &tuning.Progress{
+				Epoch: 0.5, NumEpochs: 2, Step: 50, TotalSteps: 200,
+				TrainLoss: lo.ToPtr(1.23456), LearningRate: lo.ToPtr(0.0002), StepsPerSecond: lo.ToPtr(0.5), ETASeconds: lo.ToPtr(300.0),
+			},
+			callMocks: statusMocks,
+			expectedProgress: &v1alpha1.TuningProgress{
+				Epoch: "0.50", TotalEpochs: 2, Step: 50, TotalSteps: 200,
+				TrainingLoss: "1.235", LearningRate: "0.0002", StepsPerSecond: "0.5",
+				EstimatedTimeRemaining: &v1.Duration{Duration: 5 * time.Minute},
+			},
+		},
+		"Epoch is completed and a checkpoint is saved": {
+			previous: &v1alpha1.TuningProgress{Epoch: "0.50", TotalEpochs: 2, Step: 50, TotalSteps: 200},
+			progress: &tuning.Progress{
+				Epoch: 1, NumEpochs: 2, Step: 100, TotalSteps: 200,
+				TrainLoss: lo.ToPtr(0.9), LastCheckpoint: lo.ToPtr("checkpoint-100"),
+			},
+			callMocks: statusMocks,
+			expectedProgress: &v1alpha1.TuningProgress{
+				Epoch: "1.00", TotalEpochs: 2, Step: 100, TotalSteps: 200,
+				TrainingLoss: "0.9", LastCheckpoint: "checkpoint-100",
+			},
+			expectedEvents: []string{
+				"Normal TuningEpochCompleted Epoch 1 of 2 completed at step 100, training loss 0.9",
+				"Normal TuningCheckpointSaved Checkpoint checkpoint-100 saved",
+			},
+		},
+		"Tuning resumes from a checkpoint": {
+			previous: &v1alpha1.TuningProgress{Epoch: "1.20", TotalEpochs: 2, Step: 120, TotalSteps: 200, LastCheckpoint: "checkpoint-100"},
+			progress: &tuning.Progress{
+				Epoch: 1, NumEpochs: 2, Step: 100, TotalSteps: 200, LastCheckpoint: lo.ToPtr("checkpoint-100"),
+				ResumeCount: 1, ResumedFromCheckpoint: lo.ToPtr("checkpoint-100"),
+			},
+			callMocks:        statusMocks,
+			expectedProgress: &v1alpha1.TuningProgress{Epoch: "1.00", TotalEpochs: 2, Step: 100, TotalSteps: 200, LastCheckpoint: "checkpoint-100"},
+			expectedResumes:  1,
+			expectedEvents: []string{
+				"Normal TuningResumed Tuning resumed from checkpoint checkpoint-100 after the pod was recreated, 1 resumes so far",
+			},
+		},
+		"Unchanged progress is not updated": {
+			previous: &v1alpha1.TuningProgress{Epoch: "1.00", TotalEpochs: 2, Step: 100, TotalSteps: 200, LastCheckpoint: "checkpoint-100"},
+			progress: &tuning.Progress{
+				Epoch: 1, NumEpochs: 2, Step: 100, TotalSteps: 200, LastCheckpoint: lo.ToPtr("checkpoint-100"),
+			},
+			callMocks:        func(c *test.MockClient) {},
+			expectedProgress: &v1alpha1.TuningProgress{Epoch: "1.00", TotalEpochs: 2, Step: 100, TotalSteps: 200, LastCheckpoint: "checkpoint-100"},
+		},
+	}
+
+	originalScrape := scrapeTuningProgress
+	defer func() { scrapeTuningProgress = originalScrape }()
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			if tc.previous != nil {
+				workspace.Status.Tuning = &v1alpha1.TuningStatus{Progress: tc.previous}
+			}
+
+			pod := tuningPod.DeepCopy()
+			pod.Status.StartTime = &v1.Time{Time: time.Now().Add(-tc.startedAgo)}
+			mockClient := test.NewClient()
+			mockClient.CreateMapWithType(&corev1.PodList{})[client.ObjectKeyFromObject(pod)] = pod
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			tc.callMocks(mockClient)
+			scrapeTuningProgress = func(_ context.Context, _ *corev1.Pod) (*tuning.Progress, error) {
+				return tc.progress, tc.scrapeErr
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+
+			assert.NilError(t, reconciler.updateTuningProgress(context.Background(), workspace))
+			if tc.expectedProgress == nil {
+				assert.Assert(t, workspace.Status.Tuning == nil)
+			} else {
+				assert.DeepEqual(t, tc.expectedProgress, workspace.Status.Tuning.Progress)
+				assert.Equal(t, tc.expectedResumes, workspace.Status.Tuning.ResumeCount)
+			}
+			assert.Equal(t, len(tc.expectedEvents), len(recorder.Events))
+			for _, event := range tc.expectedEvents {
+				assert.Equal(t, event, <-recorder.Events)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
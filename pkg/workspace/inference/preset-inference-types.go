@@ -56,6 +56,8 @@ var (
 
 	DefaultVLLMCommand         = "python3 /workspace/vllm/inference_api.py"
 	DefautTransformersMainFile = "/workspace/tfs/inference_api.py"
+	DefaultLlamaCppImage       = "ghcr.io/ggerganov/llama.cpp:server"
+	DefaultLlamaCppCommand     = "/app/llama-server"
 
 	DefaultImagePullSecrets = []corev1.LocalObjectReference{}
 )
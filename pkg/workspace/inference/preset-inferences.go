@@ -14,66 +14,36 @@ import (
 	"github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const (
-	ProbePath = "/health"
-	Port5000  = 5000
-)
-
-var (
-	containerPorts = []corev1.ContainerPort{{
-		ContainerPort: int32(Port5000),
-	},
-	}
-
-	livenessProbe = &corev1.Probe{
-		ProbeHandler: corev1.ProbeHandler{
-			HTTPGet: &corev1.HTTPGetAction{
-				Port: intstr.FromInt(Port5000),
-				Path: ProbePath,
-			},
-		},
-		InitialDelaySeconds: 600, // 10 minutes
-		PeriodSeconds:       10,
-	}
-
-	readinessProbe = &corev1.Probe{
-		ProbeHandler: corev1.ProbeHandler{
-			HTTPGet: &corev1.HTTPGetAction{
-				Port: intstr.FromInt(Port5000),
-				Path: ProbePath,
-			},
-		},
-		InitialDelaySeconds: 30,
-		PeriodSeconds:       10,
-	}
-
-	tolerations = []corev1.Toleration{
-		{
+// gpuTolerations returns the tolerations of the taints of the GPU nodes of the vendors.
+func gpuTolerations(gpuVendors ...string) []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	for _, vendor := range gpuVendors {
+		tolerations = append(tolerations, corev1.Toleration{
 			Effect:   corev1.TaintEffectNoSchedule,
 			Operator: corev1.TolerationOpExists,
-			Key:      resources.CapacityNvidiaGPU,
-		},
-		{
-			Effect:   corev1.TaintEffectNoSchedule,
-			Value:    consts.GPUString,
-			Key:      consts.SKUString,
-			Operator: corev1.TolerationOpEqual,
-		},
-	}
-)
+			Key:      string(sku.GetGPUVendorConfig(vendor).ResourceName),
+		})
+	}
+	return append(tolerations, corev1.Toleration{
+		Effect:   corev1.TaintEffectNoSchedule,
+		Value:    consts.GPUString,
+		Key:      consts.SKUString,
+		Operator: corev1.TolerationOpEqual,
+	})
+}
 
 func updateTorchParamsForDistributedInference(ctx context.Context, kubeClient client.Client, wObj *kaitov1alpha1.Workspace, inferenceParam *model.PresetParam) error {
 	runtimeName := v1alpha1.GetWorkspaceRuntimeName(wObj)
-	if runtimeName != model.RuntimeNameHuggingfaceTransformers {
+	if r := model.GetRuntime(runtimeName); r == nil || !r.SupportsFeature(model.RuntimeFeatureDistributedInference) {
 		return fmt.Errorf("distributed inference is not supported for runtime %s", runtimeName)
 	}
 
@@ -101,7 +71,9 @@ func updateTorchParamsForDistributedInference(ctx context.Context, kubeClient cl
 	return nil
 }
 
-func GetInferenceImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, presetObj *model.PresetParam) (string, []corev1.LocalObjectReference) {
+// GetInferenceImageInfo returns the inference image of the preset, which is the variant built for the GPU vendor unless
+// the image is private, and its pull secrets.
+func GetInferenceImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, presetObj *model.PresetParam, gpuVendor string) (string, []corev1.LocalObjectReference) {
 	imagePullSecretRefs := []corev1.LocalObjectReference{}
 	// Check if the workspace preset's access mode is private
 	if len(workspaceObj.Inference.Adapters) > 0 {
@@ -119,17 +91,71 @@ func GetInferenceImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 		return imageName, imagePullSecretRefs
 	} else {
 		imageName := string(workspaceObj.Inference.Preset.Name)
-		imageTag := presetObj.Tag
+		imageTag := kaitov1alpha1.GetPresetTag(workspaceObj.Inference.Preset, presetObj.Tag)
 		registryName := os.Getenv("PRESET_REGISTRY_NAME")
-		imageName = fmt.Sprintf("%s/kaito-%s:%s", registryName, imageName, imageTag)
+		imageName = fmt.Sprintf("%s/kaito-%s:%s%s", registryName, imageName, imageTag, sku.GetGPUVendorConfig(gpuVendor).ImageTagSuffix)
 
 		return imageName, imagePullSecretRefs
 	}
 }
 
+// IsCPUInference returns whether the preset model of the workspace is served on CPUs instead of GPUs.
+func IsCPUInference(workspaceObj *kaitov1alpha1.Workspace) bool {
+	if workspaceObj.Inference == nil || workspaceObj.Inference.Preset == nil {
+		return false
+	}
+	runtime := model.GetRuntime(kaitov1alpha1.GetWorkspaceRuntimeName(workspaceObj))
+	return runtime != nil && runtime.SupportsFeature(model.RuntimeFeatureCPU)
+}
+
+// cpuResourceRequirements returns the CPU and memory requirements of the preset for CPU inference.
+func cpuResourceRequirements(inferenceParam *model.PresetParam) corev1.ResourceRequirements {
+	resourceReq := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+	if inferenceParam.CPURequirement != "" {
+		resourceReq.Requests[corev1.ResourceCPU] = resource.MustParse(inferenceParam.CPURequirement)
+	}
+	if inferenceParam.MemoryRequirement != "" {
+		resourceReq.Requests[corev1.ResourceMemory] = resource.MustParse(inferenceParam.MemoryRequirement)
+		resourceReq.Limits[corev1.ResourceMemory] = resource.MustParse(inferenceParam.MemoryRequirement)
+	}
+	return resourceReq
+}
+
+// GetWorkspaceRuntime returns the runtime serving the preset model of the workspace.
+func GetWorkspaceRuntime(workspaceObj *kaitov1alpha1.Workspace) (model.Runtime, error) {
+	runtimeName := kaitov1alpha1.GetWorkspaceRuntimeName(workspaceObj)
+	runtime := model.GetRuntime(runtimeName)
+	if runtime == nil {
+		return nil, fmt.Errorf("runtime %s is not supported", runtimeName)
+	}
+	return runtime, nil
+}
+
 func CreatePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string,
+	model model.Model, kubeClient client.Client) (client.Object, error) {
+	depObj, err := GeneratePresetInference(ctx, workspaceObj, revisionNum, model, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	err = resources.CreateResource(ctx, depObj, kubeClient)
+	if client.IgnoreAlreadyExists(err) != nil {
+		return nil, err
+	}
+	return depObj, nil
+}
+
+// GeneratePresetInference renders the inference workload of the preset model, a StatefulSet if the model
+// supports distributed inference and a Deployment otherwise.
+func GeneratePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string,
 	model model.Model, kubeClient client.Client) (client.Object, error) {
 	inferenceParam := model.GetInferenceParameters().DeepCopy()
+	runtime, err := GetWorkspaceRuntime(workspaceObj)
+	if err != nil {
+		return nil, err
+	}
 
 	if model.SupportDistributedInference() {
 		if err := updateTorchParamsForDistributedInference(ctx, kubeClient, workspaceObj, inferenceParam); err != nil { //
@@ -139,18 +165,33 @@ func CreatePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 	}
 
 	// resource requirements
-	skuNumGPUs, err := utils.GetSKUNumGPUs(ctx, kubeClient, workspaceObj.Status.WorkerNodes,
-		workspaceObj.Resource.InstanceType, inferenceParam.GPUCountRequirement)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get SKU num GPUs: %v", err)
-	}
-	resourceReq := corev1.ResourceRequirements{
-		Requests: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
-		},
-		Limits: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
-		},
+	skuNumGPUs, gpuVendor := "0", ""
+	var resourceReq corev1.ResourceRequirements
+	var tolerations []corev1.Toleration
+	if IsCPUInference(workspaceObj) {
+		resourceReq = cpuResourceRequirements(inferenceParam)
+		// The nodes created by Kaito are tainted regardless of their instance type.
+		tolerations = gpuTolerations()
+	} else {
+		skuNumGPUs, err = utils.GetSKUNumGPUs(ctx, kubeClient, workspaceObj.Status.WorkerNodes,
+			kaitov1alpha1.GetWorkspaceInstanceType(workspaceObj), inferenceParam.GPUCountRequirement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get SKU num GPUs: %v", err)
+		}
+		gpuVendor = utils.GetSKUGPUVendor(ctx, kubeClient, workspaceObj.Status.WorkerNodes, kaitov1alpha1.GetWorkspaceInstanceType(workspaceObj))
+		if !sku.IsEnabledGPUVendor(gpuVendor) {
+			return nil, fmt.Errorf("%s GPUs are disabled by the %s feature gate", gpuVendor, sku.GetGPUVendorConfig(gpuVendor).FeatureGate)
+		}
+		gpuResource := sku.GetGPUVendorConfig(gpuVendor).ResourceName
+		resourceReq = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				gpuResource: resource.MustParse(skuNumGPUs),
+			},
+			Limits: corev1.ResourceList{
+				gpuResource: resource.MustParse(skuNumGPUs),
+			},
+		}
+		tolerations = gpuTolerations(gpuVendor)
 	}
 	skuGPUCount, _ := strconv.Atoi(skuNumGPUs)
 
@@ -165,28 +206,43 @@ func CreatePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 	if shmVolumeMount.Name != "" {
 		volumeMounts = append(volumeMounts, shmVolumeMount)
 	}
+	if workspaceObj.Inference.Preset.HuggingFace != nil {
+		weightsVolume, weightsVolumeMount := utils.ConfigModelWeightsVolume()
+		volumes = append(volumes, weightsVolume)
+		volumeMounts = append(volumeMounts, weightsVolumeMount)
+	}
 	if len(workspaceObj.Inference.Adapters) > 0 {
 		adapterVolume, adapterVolumeMount := utils.ConfigAdapterVolume()
 		volumes = append(volumes, adapterVolume)
 		volumeMounts = append(volumeMounts, adapterVolumeMount)
+		// The secrets of the adapter artifacts are only mounted by the init containers that pull them.
+		for i, adapter := range workspaceObj.Inference.Adapters {
+			if adapter.Source.ImageFormat == kaitov1alpha1.ImageFormatArtifact && len(adapter.Source.ImagePullSecrets) > 0 {
+				secretVolume, _ := utils.ConfigAdapterPullSecretVolume(i, adapter.Source.ImagePullSecrets[0])
+				volumes = append(volumes, secretVolume)
+			}
+		}
 	}
 
 	// inference command
-	runtimeName := kaitov1alpha1.GetWorkspaceRuntimeName(workspaceObj)
-	commands := inferenceParam.GetInferenceCommand(runtimeName, skuNumGPUs)
+	commands := runtime.GetInferenceCommand(inferenceParam, skuNumGPUs)
+	containerPorts := []corev1.ContainerPort{{ContainerPort: runtime.Port()}}
+	livenessProbe, readinessProbe := runtime.LivenessProbe(), runtime.ReadinessProbe()
 
-	image, imagePullSecrets := GetInferenceImageInfo(ctx, workspaceObj, inferenceParam)
+	image, imagePullSecrets := GetInferenceImageInfo(ctx, workspaceObj, inferenceParam, gpuVendor)
+	if runtimeImage := runtime.GetImage(inferenceParam); runtimeImage != "" {
+		image = runtimeImage
+	}
 
 	var depObj client.Object
 	if model.SupportDistributedInference() {
-		depObj = manifests.GenerateStatefulSetManifest(ctx, workspaceObj, image, imagePullSecrets, *workspaceObj.Resource.Count, commands,
+		depObj = manifests.GenerateStatefulSetManifest(ctx, workspaceObj, revisionNum, image, imagePullSecrets, *workspaceObj.Resource.Count, commands,
 			containerPorts, livenessProbe, readinessProbe, resourceReq, tolerations, volumes, volumeMounts)
 	} else {
 		depObj = manifests.GenerateDeploymentManifest(ctx, workspaceObj, revisionNum, image, imagePullSecrets, *workspaceObj.Resource.Count, commands,
 			containerPorts, livenessProbe, readinessProbe, resourceReq, tolerations, volumes, volumeMounts)
 	}
-	err = resources.CreateResource(ctx, depObj, kubeClient)
-	if client.IgnoreAlreadyExists(err) != nil {
+	if err := resources.SetLastAppliedConfiguration(depObj); err != nil {
 		return nil, err
 	}
 	return depObj, nil
@@ -15,9 +15,11 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/test"
 
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -216,6 +218,36 @@ func TestCreatePresetInference(t *testing.T) {
 	}
 }
 
+func TestCreatePresetInferenceOnCPU(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	mockClient := test.NewClient()
+	mockClient.On("Create", mock.IsType(context.TODO()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+
+	workspace := test.MockWorkspaceWithPresetLlamaCpp.DeepCopy()
+	assert.True(t, IsCPUInference(workspace))
+	assert.False(t, IsCPUInference(test.MockWorkspaceWithPresetVLLM))
+
+	createdObject, err := CreatePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, plugin.KaitoModelRegister.MustGet("test-model"), mockClient)
+	assert.NoError(t, err)
+	podSpec := createdObject.(*appsv1.Deployment).Spec.Template.Spec
+	container := podSpec.Containers[0]
+
+	assert.Equal(t, "ghcr.io/ggerganov/llama.cpp:server", container.Image)
+	assert.Equal(t, "/bin/sh -c /app/llama-server --hf-file mymodel-q4.gguf --hf-repo test/mymodel-gguf --host 0.0.0.0 --metrics --port 5000 --threads 4",
+		strings.Join(container.Command, " "))
+	assert.Equal(t, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}, container.Resources.Requests)
+	assert.Equal(t, corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}, container.Resources.Limits)
+	for _, toleration := range podSpec.Tolerations {
+		assert.NotEqual(t, "nvidia.com/gpu", toleration.Key)
+	}
+}
+
 func toParameterMap(in []string) map[string]string {
 	ret := make(map[string]string)
 	for _, eachToken := range in {
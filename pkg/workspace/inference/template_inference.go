@@ -6,14 +6,28 @@ import (
 	"context"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateTemplateInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, kubeClient client.Client) (client.Object, error) {
-	depObj := manifests.GenerateDeploymentManifestWithPodTemplate(ctx, workspaceObj, tolerations)
-	err := resources.CreateResource(ctx, client.Object(depObj), kubeClient)
+// GenerateTemplateInference renders the inference workload from the pod template of the workspace.
+func GenerateTemplateInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string) (client.Object, error) {
+	// The GPU resources are requested by the pod template, which may run on the GPU nodes of any vendor.
+	depObj := manifests.GenerateDeploymentManifestWithPodTemplate(ctx, workspaceObj, revisionNum, gpuTolerations(sku.SupportedGPUVendors()...))
+	if err := resources.SetLastAppliedConfiguration(depObj); err != nil {
+		return nil, err
+	}
+	return depObj, nil
+}
+
+func CreateTemplateInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string, kubeClient client.Client) (client.Object, error) {
+	depObj, err := GenerateTemplateInference(ctx, workspaceObj, revisionNum)
+	if err != nil {
+		return nil, err
+	}
+	err = resources.CreateResource(ctx, depObj, kubeClient)
 	if client.IgnoreAlreadyExists(err) != nil {
 		return nil, err
 	}
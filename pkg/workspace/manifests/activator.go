@@ -0,0 +1,167 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package manifests
+
+import (
+	"fmt"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/activator"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ActivatorProxyPort is the container port the activator receives the inference requests on.
+	ActivatorProxyPort = int32(8080)
+	// ActivatorStatsPort is the container port the activator reports its request activity on.
+	ActivatorStatsPort = int32(8081)
+)
+
+// GetActivatorName returns the name of the activator objects of the workspace: its deployment, its service, and the
+// service account, role and role binding of the activator pods.
+func GetActivatorName(workspaceObj *kaitov1alpha1.Workspace) string {
+	return workspaceObj.Name + "-activator"
+}
+
+func activatorObjectMeta(workspaceObj *kaitov1alpha1.Workspace) v1.ObjectMeta {
+	return v1.ObjectMeta{
+		Name:      GetActivatorName(workspaceObj),
+		Namespace: workspaceObj.Namespace,
+		OwnerReferences: []v1.OwnerReference{
+			{
+				APIVersion: kaitov1alpha1.GroupVersion.String(),
+				Kind:       "Workspace",
+				UID:        workspaceObj.UID,
+				Name:       workspaceObj.Name,
+				Controller: &controller,
+			},
+		},
+	}
+}
+
+// GenerateActivatorDeploymentManifest generates the deployment of the activator that forwards the requests to the
+// service of the workspace and holds them while the workspace is scaled to zero. The activator watches the inference
+// pods selected by the service of the workspace and forwards the requests once one of them is ready.
+func GenerateActivatorDeploymentManifest(workspaceObj *kaitov1alpha1.Workspace, imageName string, isStatefulSet bool) *appsv1.Deployment {
+	selector := map[string]string{
+		kaitov1alpha1.LabelActivatorName: workspaceObj.Name,
+	}
+	upstream := fmt.Sprintf("http://%s:%d", utils.GetServiceHost(workspaceObj.Name, workspaceObj.Namespace), InferenceServicePort)
+	upstreamSelector := labels.SelectorFromSet(GetServiceSelector(workspaceObj, isStatefulSet)).String()
+
+	return &appsv1.Deployment{
+		ObjectMeta: activatorObjectMeta(workspaceObj),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: lo.ToPtr(int32(1)),
+			Selector: &v1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: selector},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: GetActivatorName(workspaceObj),
+					Containers: []corev1.Container{
+						{
+							Name:  "activator",
+							Image: imageName,
+							Command: []string{
+								"/activator",
+								"--upstream=" + upstream,
+								"--namespace=" + workspaceObj.Namespace,
+								"--upstream-selector=" + upstreamSelector,
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "http", ContainerPort: ActivatorProxyPort},
+								{Name: "stats", ContainerPort: ActivatorStatsPort},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: activator.HealthPath,
+										Port: intstr.FromInt32(ActivatorStatsPort),
+									},
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("50m"),
+									corev1.ResourceMemory: resource.MustParse("64Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GenerateActivatorServiceManifest generates the service clients send their requests to when the workspace has an
+// idle policy, so that the requests keep the workspace active and bring it back after it is scaled to zero.
+func GenerateActivatorServiceManifest(workspaceObj *kaitov1alpha1.Workspace) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: activatorObjectMeta(workspaceObj),
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       InferenceServicePort,
+					TargetPort: intstr.FromInt32(ActivatorProxyPort),
+				},
+			},
+			Selector: map[string]string{
+				kaitov1alpha1.LabelActivatorName: workspaceObj.Name,
+			},
+		},
+	}
+}
+
+// GenerateActivatorServiceAccountManifest generates the service account of the activator pods.
+func GenerateActivatorServiceAccountManifest(workspaceObj *kaitov1alpha1.Workspace) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: activatorObjectMeta(workspaceObj),
+	}
+}
+
+// GenerateActivatorRoleManifest generates the role allowing the activator to watch the pods of the namespace of the
+// workspace for their readiness.
+func GenerateActivatorRoleManifest(workspaceObj *kaitov1alpha1.Workspace) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: activatorObjectMeta(workspaceObj),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+// GenerateActivatorRoleBindingManifest generates the binding of the activator role to the activator service account.
+func GenerateActivatorRoleBindingManifest(workspaceObj *kaitov1alpha1.Workspace) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: activatorObjectMeta(workspaceObj),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     GetActivatorName(workspaceObj),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      GetActivatorName(workspaceObj),
+				Namespace: workspaceObj.Namespace,
+			},
+		},
+	}
+}
@@ -6,6 +6,8 @@ package manifests
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
 
 	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/utils/pointer"
@@ -13,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/huggingface"
+	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -21,6 +25,9 @@ import (
 
 var controller = true
 
+// InferenceServicePort is the port of the HTTP API exposed by the inference service.
+const InferenceServicePort = int32(80)
+
 func GenerateHeadlessServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace) *corev1.Service {
 	serviceName := fmt.Sprintf("%s-headless", workspaceObj.Name)
 	selector := map[string]string{
@@ -57,7 +64,8 @@ func GenerateHeadlessServiceManifest(ctx context.Context, workspaceObj *kaitov1a
 	}
 }
 
-func GenerateServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, serviceType corev1.ServiceType, isStatefulSet bool) *corev1.Service {
+// GetServiceSelector returns the selector of the inference pods backing the service of the workspace.
+func GetServiceSelector(workspaceObj *kaitov1alpha1.Workspace, isStatefulSet bool) map[string]string {
 	selector := map[string]string{
 		kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
 	}
@@ -66,6 +74,11 @@ func GenerateServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Wo
 		podNameForIndex0 := fmt.Sprintf("%s-0", workspaceObj.Name)
 		selector["statefulset.kubernetes.io/pod-name"] = podNameForIndex0
 	}
+	return selector
+}
+
+func GenerateServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, serviceType corev1.ServiceType, isStatefulSet bool, targetPort int32) *corev1.Service {
+	selector := GetServiceSelector(workspaceObj, isStatefulSet)
 
 	return &corev1.Service{
 		ObjectMeta: v1.ObjectMeta{
@@ -88,8 +101,8 @@ func GenerateServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Wo
 				{
 					Name:       "http",
 					Protocol:   corev1.ProtocolTCP,
-					Port:       80,
-					TargetPort: intstr.FromInt32(5000),
+					Port:       InferenceServicePort,
+					TargetPort: intstr.FromInt32(targetPort),
 				},
 				// Torch NCCL Port
 				{
@@ -107,19 +120,28 @@ func GenerateServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Wo
 	}
 }
 
-func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, imageName string,
-	imagePullSecretRefs []corev1.LocalObjectReference, replicas int, commands []string, containerPorts []corev1.ContainerPort,
-	livenessProbe, readinessProbe *corev1.Probe, resourceRequirements corev1.ResourceRequirements,
-	tolerations []corev1.Toleration, volumes []corev1.Volume, volumeMount []corev1.VolumeMount) *appsv1.StatefulSet {
-
-	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(workspaceObj.Resource.LabelSelector.MatchLabels))
-	for key, value := range workspaceObj.Resource.LabelSelector.MatchLabels {
+// generateNodeRequirements converts the label selector of the workspace to node affinity requirements.
+// The requirements are sorted by key so that the rendered workload is stable across reconciles.
+func generateNodeRequirements(workspaceObj *kaitov1alpha1.Workspace) []corev1.NodeSelectorRequirement {
+	keys := lo.Keys(workspaceObj.Resource.LabelSelector.MatchLabels)
+	sort.Strings(keys)
+	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(keys))
+	for _, key := range keys {
 		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
 			Key:      key,
 			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{value},
+			Values:   []string{workspaceObj.Resource.LabelSelector.MatchLabels[key]},
 		})
 	}
+	return nodeRequirements
+}
+
+func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string, imageName string,
+	imagePullSecretRefs []corev1.LocalObjectReference, replicas int, commands []string, containerPorts []corev1.ContainerPort,
+	livenessProbe, readinessProbe *corev1.Probe, resourceRequirements corev1.ResourceRequirements,
+	tolerations []corev1.Toleration, volumes []corev1.Volume, volumeMount []corev1.VolumeMount) *appsv1.StatefulSet {
+
+	nodeRequirements := generateNodeRequirements(workspaceObj)
 
 	selector := map[string]string{
 		kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
@@ -141,6 +163,9 @@ func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha
 					Controller: &controller,
 				},
 			},
+			Annotations: map[string]string{
+				kaitov1alpha1.WorkspaceRevisionAnnotation: revisionNum,
+			},
 		},
 		Spec: appsv1.StatefulSetSpec{
 			Replicas:            lo.ToPtr(int32(replicas)),
@@ -216,6 +241,20 @@ func GenerateTuningJobManifest(ctx context.Context, wObj *kaitov1alpha1.Workspac
 	}, sidecarContainers...)
 
 	var numBackoff int32
+	var podFailurePolicy *batchv1.PodFailurePolicy
+	if wObj.Tuning != nil && wObj.Tuning.Checkpoint != nil {
+		// A pod that is evicted, preempted or lost with its node is recreated without counting against the backoff
+		// limit, and resumes from the latest checkpoint.
+		podFailurePolicy = &batchv1.PodFailurePolicy{
+			Rules: []batchv1.PodFailurePolicyRule{{
+				Action: batchv1.PodFailurePolicyActionIgnore,
+				OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{{
+					Type:   corev1.DisruptionTarget,
+					Status: corev1.ConditionTrue,
+				}},
+			}},
+		}
+	}
 	return &batchv1.Job{
 		TypeMeta: v1.TypeMeta{
 			APIVersion: "batch/v1",
@@ -239,7 +278,8 @@ func GenerateTuningJobManifest(ctx context.Context, wObj *kaitov1alpha1.Workspac
 			},
 		},
 		Spec: batchv1.JobSpec{
-			BackoffLimit: &numBackoff, // default is 6. A failed tuning job is unlikely to be self-recoverable, no need to recreate the pod.
+			BackoffLimit:     &numBackoff, // default is 6. A failed tuning job is unlikely to be self-recoverable, no need to recreate the pod.
+			PodFailurePolicy: podFailurePolicy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: v1.ObjectMeta{
 					Labels: labels,
@@ -262,14 +302,7 @@ func GenerateDeploymentManifest(ctx context.Context, workspaceObj *kaitov1alpha1
 	livenessProbe, readinessProbe *corev1.Probe, resourceRequirements corev1.ResourceRequirements,
 	tolerations []corev1.Toleration, volumes []corev1.Volume, volumeMount []corev1.VolumeMount) *appsv1.Deployment {
 
-	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(workspaceObj.Resource.LabelSelector.MatchLabels))
-	for key, value := range workspaceObj.Resource.LabelSelector.MatchLabels {
-		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
-			Key:      key,
-			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{value},
-		})
-	}
+	nodeRequirements := generateNodeRequirements(workspaceObj)
 
 	selector := map[string]string{
 		kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
@@ -282,6 +315,9 @@ func GenerateDeploymentManifest(ctx context.Context, workspaceObj *kaitov1alpha1
 	if len(workspaceObj.Inference.Adapters) > 0 {
 		initContainers, envs = GenerateInitContainers(workspaceObj, volumeMount)
 	}
+	if workspaceObj.Inference.Preset != nil && workspaceObj.Inference.Preset.HuggingFace != nil {
+		initContainers = append([]corev1.Container{GenerateModelDownloadInitContainer(workspaceObj, imageName)}, initContainers...)
+	}
 
 	return &appsv1.Deployment{
 		ObjectMeta: v1.ObjectMeta{
@@ -359,7 +395,7 @@ func GenerateInitContainers(wObj *kaitov1alpha1.Workspace, volumeMount []corev1.
 	var initContainers []corev1.Container
 	var envs []corev1.EnvVar
 	if len(wObj.Inference.Adapters) > 0 {
-		for _, adapter := range wObj.Inference.Adapters {
+		for i, adapter := range wObj.Inference.Adapters {
 			initContainer := corev1.Container{
 				Name:            adapter.Source.Name,
 				Image:           adapter.Source.Image,
@@ -367,6 +403,9 @@ func GenerateInitContainers(wObj *kaitov1alpha1.Workspace, volumeMount []corev1.
 				VolumeMounts:    volumeMount,
 				ImagePullPolicy: corev1.PullAlways,
 			}
+			if adapter.Source.ImageFormat == kaitov1alpha1.ImageFormatArtifact {
+				initContainer = generateAdapterArtifactInitContainer(i, adapter.Source, volumeMount)
+			}
 			initContainers = append(initContainers, initContainer)
 			env := corev1.EnvVar{
 				Name:  adapter.Source.Name,
@@ -379,15 +418,58 @@ func GenerateInitContainers(wObj *kaitov1alpha1.Workspace, volumeMount []corev1.
 	return initContainers, envs
 }
 
-func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, tolerations []corev1.Toleration) *appsv1.Deployment {
-	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(workspaceObj.Resource.LabelSelector.MatchLabels))
-	for key, value := range workspaceObj.Resource.LabelSelector.MatchLabels {
-		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
-			Key:      key,
-			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{value},
+// generateAdapterArtifactInitContainer returns the init container that pulls the files of the adapter artifact with
+// the oras CLI, so that the artifact does not need to be an image that can run.
+func generateAdapterArtifactInitContainer(index int, source *kaitov1alpha1.DataSource, volumeMount []corev1.VolumeMount) corev1.Container {
+	volumeMounts := append([]corev1.VolumeMount{}, volumeMount...)
+	withCredentials := len(source.ImagePullSecrets) > 0
+	if withCredentials {
+		_, secretVolumeMount := utils.ConfigAdapterPullSecretVolume(index, source.ImagePullSecrets[0])
+		volumeMounts = append(volumeMounts, secretVolumeMount)
+	}
+	return corev1.Container{
+		Name:         source.Name,
+		Image:        utils.GetOrasImage(),
+		Command:      []string{"/bin/sh", "-c", utils.GetOrasPullCommand(source.Image, fmt.Sprintf("%s/%s", utils.DefaultAdapterVolumePath, source.Name), withCredentials)},
+		VolumeMounts: volumeMounts,
+	}
+}
+
+// GenerateModelDownloadInitContainer returns the init container that downloads the weights of the model repository
+// named by the generic huggingface preset, using the runtime image that the weights are served with.
+func GenerateModelDownloadInitContainer(wObj *kaitov1alpha1.Workspace, imageName string) corev1.Container {
+	hf := wObj.Inference.Preset.HuggingFace
+	revision := hf.Revision
+	if revision == "" {
+		revision = huggingface.DefaultRevision
+	}
+	_, weightsVolumeMount := utils.ConfigModelWeightsVolume()
+
+	var envs []corev1.EnvVar
+	if hf.TokenSecretRef != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:      "HF_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: hf.TokenSecretRef.DeepCopy()},
 		})
 	}
+	// Download from the same mirror as the metadata of the model, if any.
+	if endpoint := os.Getenv("HF_ENDPOINT"); endpoint != "" {
+		envs = append(envs, corev1.EnvVar{Name: "HF_ENDPOINT", Value: endpoint})
+	}
+
+	return corev1.Container{
+		Name:  "model-download",
+		Image: imageName,
+		// The pickled weights are skipped because the safetensors weights are served.
+		Command: []string{"huggingface-cli", "download", hf.ModelID, "--revision", revision,
+			"--local-dir", weightsVolumeMount.MountPath, "--exclude", "*.bin", "*.pth", "*.pt"},
+		Env:          envs,
+		VolumeMounts: []corev1.VolumeMount{weightsVolumeMount},
+	}
+}
+
+func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string, tolerations []corev1.Toleration) *appsv1.Deployment {
+	nodeRequirements := generateNodeRequirements(workspaceObj)
 
 	templateCopy := workspaceObj.Inference.Template.DeepCopy()
 
@@ -433,6 +515,9 @@ func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj
 					Controller: &controller,
 				},
 			},
+			Annotations: map[string]string{
+				kaitov1alpha1.WorkspaceRevisionAnnotation: revisionNum,
+			},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: lo.ToPtr(int32(*workspaceObj.Resource.Count)),
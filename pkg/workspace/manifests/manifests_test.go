@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 
 	"testing"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -23,8 +26,9 @@ func TestGenerateStatefulSetManifest(t *testing.T) {
 		workspace := test.MockWorkspaceWithPreset
 
 		obj := GenerateStatefulSetManifest(context.TODO(), workspace,
-			"",  //imageName
-			nil, //imagePullSecretRefs
+			test.MockWorkspaceWithPresetHash, //revisionNum
+			"",                               //imageName
+			nil,                              //imagePullSecretRefs
 			*workspace.Resource.Count,
 			nil, //commands
 			nil, //containerPorts
@@ -101,12 +105,51 @@ func TestGenerateDeploymentManifest(t *testing.T) {
 	})
 }
 
+func TestGenerateTuningJobManifest(t *testing.T) {
+	t.Run("generate job without checkpoints", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Tuning = &kaitov1alpha1.TuningSpec{}
+
+		obj := GenerateTuningJobManifest(context.TODO(), workspace, "1", "", nil, 1, nil, nil, nil, nil,
+			v1.ResourceRequirements{}, nil, nil, nil, nil, nil, nil)
+
+		if *obj.Spec.BackoffLimit != 0 {
+			t.Errorf("backoff limit is wrong")
+		}
+		if obj.Spec.PodFailurePolicy != nil {
+			t.Errorf("pod failure policy is not expected")
+		}
+	})
+
+	t.Run("generate job with checkpoints", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Tuning = &kaitov1alpha1.TuningSpec{
+			Checkpoint: &kaitov1alpha1.CheckpointSpec{
+				Volume: &v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints"},
+				},
+			},
+		}
+
+		obj := GenerateTuningJobManifest(context.TODO(), workspace, "1", "", nil, 1, nil, nil, nil, nil,
+			v1.ResourceRequirements{}, nil, nil, nil, nil, nil, nil)
+
+		if obj.Spec.PodFailurePolicy == nil || len(obj.Spec.PodFailurePolicy.Rules) != 1 {
+			t.Fatalf("pod failure policy is missing")
+		}
+		rule := obj.Spec.PodFailurePolicy.Rules[0]
+		if rule.Action != batchv1.PodFailurePolicyActionIgnore || rule.OnPodConditions[0].Type != v1.DisruptionTarget {
+			t.Errorf("disruptions are not ignored")
+		}
+	})
+}
+
 func TestGenerateDeploymentManifestWithPodTemplate(t *testing.T) {
 	t.Run("generate deployment with pod template", func(t *testing.T) {
 
 		workspace := test.MockWorkspaceWithInferenceTemplate
 
-		obj := GenerateDeploymentManifestWithPodTemplate(context.TODO(), workspace, nil)
+		obj := GenerateDeploymentManifestWithPodTemplate(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, nil)
 
 		appSelector := map[string]string{
 			kaitov1alpha1.LabelWorkspaceName: workspace.Name,
@@ -129,6 +172,94 @@ func TestGenerateDeploymentManifestWithPodTemplate(t *testing.T) {
 	})
 }
 
+func TestGenerateModelDownloadInitContainer(t *testing.T) {
+	t.Run("generate deployment with model download", func(t *testing.T) {
+
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Inference.Preset.HuggingFace = &kaitov1alpha1.HuggingFaceModelSpec{
+			ModelID: "mistralai/Mistral-7B-Instruct-v0.3",
+			TokenSecretRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "hf-token"},
+				Key:                  "token",
+			},
+		}
+
+		obj := GenerateDeploymentManifest(context.TODO(), workspace, test.MockWorkspaceWithPresetHash,
+			"kaito-huggingface:0.0.1", //imageName
+			nil,                       //imagePullSecretRefs
+			*workspace.Resource.Count,
+			nil, //commands
+			nil, //containerPorts
+			nil, //livenessProbe
+			nil, //readinessProbe
+			v1.ResourceRequirements{},
+			nil, //tolerations
+			nil, //volumes
+			nil, //volumeMount
+		)
+
+		initContainers := obj.Spec.Template.Spec.InitContainers
+		if len(initContainers) != 1 {
+			t.Fatalf("expected 1 init container, got %d", len(initContainers))
+		}
+		container := initContainers[0]
+		if container.Image != "kaito-huggingface:0.0.1" {
+			t.Errorf("init container image is wrong: %s", container.Image)
+		}
+		expectedCommand := []string{"huggingface-cli", "download", "mistralai/Mistral-7B-Instruct-v0.3", "--revision", "main",
+			"--local-dir", "/workspace/weights", "--exclude", "*.bin", "*.pth", "*.pt"}
+		if !reflect.DeepEqual(expectedCommand, container.Command) {
+			t.Errorf("init container command is wrong: %v", container.Command)
+		}
+		if len(container.Env) != 1 || container.Env[0].Name != "HF_TOKEN" ||
+			!reflect.DeepEqual(workspace.Inference.Preset.HuggingFace.TokenSecretRef, container.Env[0].ValueFrom.SecretKeyRef) {
+			t.Errorf("init container token env is wrong: %v", container.Env)
+		}
+	})
+}
+
+func TestGenerateInitContainers(t *testing.T) {
+	t.Run("generate init containers of adapter images and artifacts", func(t *testing.T) {
+		strength := "0.5"
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Inference.Adapters = []kaitov1alpha1.AdapterSpec{
+			{
+				Source:   &kaitov1alpha1.DataSource{Name: "adapter-image", Image: "myregistry.azurecr.io/adapter-image:0.0.1"},
+				Strength: &strength,
+			},
+			{
+				Source: &kaitov1alpha1.DataSource{Name: "adapter-artifact", Image: "myregistry.azurecr.io/adapter-artifact:0.0.1",
+					ImagePullSecrets: []string{"pull-secret"}, ImageFormat: kaitov1alpha1.ImageFormatArtifact},
+				Strength: &strength,
+			},
+		}
+		volumeMounts := []v1.VolumeMount{{Name: "adapter-volume", MountPath: "/mnt/adapter"}}
+
+		initContainers, envs := GenerateInitContainers(workspace, volumeMounts)
+		if len(initContainers) != 2 || len(envs) != 2 {
+			t.Fatalf("expected 2 init containers and envs, got %d and %d", len(initContainers), len(envs))
+		}
+		if initContainers[0].Image != "myregistry.azurecr.io/adapter-image:0.0.1" || len(initContainers[0].VolumeMounts) != 1 {
+			t.Errorf("init container of the adapter image is wrong: %v", initContainers[0])
+		}
+		artifactContainer := initContainers[1]
+		if artifactContainer.Image != utils.DefaultOrasImage {
+			t.Errorf("init container image of the adapter artifact is wrong: %s", artifactContainer.Image)
+		}
+		expectedCommand := []string{"/bin/sh", "-c", "oras pull myregistry.azurecr.io/adapter-artifact:0.0.1 --output /mnt/adapter/adapter-artifact --registry-config /tmp/.docker/config/config.json"}
+		if !reflect.DeepEqual(expectedCommand, artifactContainer.Command) {
+			t.Errorf("init container command of the adapter artifact is wrong: %v", artifactContainer.Command)
+		}
+		expectedVolumeMounts := []v1.VolumeMount{volumeMounts[0], {Name: "adapter-1-docker-config", MountPath: "/tmp/.docker/config"}}
+		if !reflect.DeepEqual(expectedVolumeMounts, artifactContainer.VolumeMounts) {
+			t.Errorf("init container volume mounts of the adapter artifact are wrong: %v", artifactContainer.VolumeMounts)
+		}
+		if len(volumeMounts) != 1 {
+			t.Errorf("volume mounts of the main container must not change: %v", volumeMounts)
+		}
+	})
+}
+
 func kvInNodeRequirement(key, val string, nodeReq []v1.NodeSelectorRequirement) bool {
 	for _, each := range nodeReq {
 		if each.Key == key && each.Values[0] == val && each.Operator == v1.NodeSelectorOpIn {
@@ -144,7 +275,7 @@ func TestGenerateServiceManifest(t *testing.T) {
 	for _, isStatefulSet := range options {
 		t.Run(fmt.Sprintf("generate service, isStatefulSet %v", isStatefulSet), func(t *testing.T) {
 			workspace := test.MockWorkspaceWithPreset
-			obj := GenerateServiceManifest(context.TODO(), workspace, v1.ServiceTypeClusterIP, isStatefulSet)
+			obj := GenerateServiceManifest(context.TODO(), workspace, v1.ServiceTypeClusterIP, isStatefulSet, 5000)
 
 			svcSelector := map[string]string{
 				kaitov1alpha1.LabelWorkspaceName: workspace.Name,
@@ -179,3 +310,32 @@ func TestGenerateHeadlessServiceManifest(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerateActivatorDeploymentManifest(t *testing.T) {
+	t.Setenv(consts.ClusterDomainEnvVar, "example.local")
+	options := []bool{true, false}
+
+	for _, isStatefulSet := range options {
+		t.Run(fmt.Sprintf("generate activator deployment, isStatefulSet %v", isStatefulSet), func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset
+			obj := GenerateActivatorDeploymentManifest(workspace, "kaito/workspace:test", isStatefulSet)
+
+			upstreamSelector := fmt.Sprintf("%s=%s", kaitov1alpha1.LabelWorkspaceName, workspace.Name)
+			if isStatefulSet {
+				upstreamSelector = fmt.Sprintf("%s,statefulset.kubernetes.io/pod-name=%s-0", upstreamSelector, workspace.Name)
+			}
+			command := []string{
+				"/activator",
+				fmt.Sprintf("--upstream=http://%s.%s.svc.example.local:80", workspace.Name, workspace.Namespace),
+				"--namespace=" + workspace.Namespace,
+				"--upstream-selector=" + upstreamSelector,
+			}
+			if !reflect.DeepEqual(command, obj.Spec.Template.Spec.Containers[0].Command) {
+				t.Errorf("activator command is wrong: %v", obj.Spec.Template.Spec.Containers[0].Command)
+			}
+			if obj.Spec.Template.Spec.ServiceAccountName != GetActivatorName(workspace) {
+				t.Errorf("activator service account is wrong")
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
@@ -30,6 +31,16 @@ const (
 	TuningFile              = "/workspace/tfs/fine_tuning.py"
 	DefaultBaseDir          = "/mnt"
 	DefaultOutputVolumePath = "/mnt/output"
+
+	// DefaultCheckpointVolumePath is where the checkpoint volume is mounted in the tuning container.
+	DefaultCheckpointVolumePath = "/mnt/checkpoints"
+	// DefaultCheckpointInterval is the number of training steps between two checkpoints if the interval is not set.
+	DefaultCheckpointInterval = 500
+
+	// CheckpointDirEnvVar and CheckpointStepsEnvVar tell the tuning script where and how often to save checkpoints.
+	// The script resumes from the latest checkpoint in the directory if there is one.
+	CheckpointDirEnvVar   = "CHECKPOINT_DIR"
+	CheckpointStepsEnvVar = "CHECKPOINT_STEPS"
 )
 
 var (
@@ -66,7 +77,9 @@ func getInstanceGPUCount(sku string) int {
 	return gpuConfig.GPUCount
 }
 
-func GetTuningImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, presetObj *model.PresetParam) (string, []corev1.LocalObjectReference) {
+// GetTuningImageInfo returns the tuning image of the preset, which is the variant built for the GPU vendor unless the
+// image is private, and its pull secrets.
+func GetTuningImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, presetObj *model.PresetParam, gpuVendor string) (string, []corev1.LocalObjectReference) {
 	imagePullSecretRefs := []corev1.LocalObjectReference{}
 	// Check if the workspace preset's access mode is private
 	if string(workspaceObj.Tuning.Preset.AccessMode) == string(kaitov1alpha1.ModelImageAccessModePrivate) {
@@ -77,9 +90,9 @@ func GetTuningImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 		return imageName, imagePullSecretRefs
 	} else {
 		imageName := string(workspaceObj.Tuning.Preset.Name)
-		imageTag := presetObj.Tag
+		imageTag := kaitov1alpha1.GetPresetTag(workspaceObj.Tuning.Preset, presetObj.Tag)
 		registryName := os.Getenv("PRESET_REGISTRY_NAME")
-		imageName = fmt.Sprintf("%s/kaito-%s:%s", registryName, imageName, imageTag)
+		imageName = fmt.Sprintf("%s/kaito-%s:%s%s", registryName, imageName, imageTag, sku.GetGPUVendorConfig(gpuVendor).ImageTagSuffix)
 		return imageName, imagePullSecretRefs
 	}
 }
@@ -207,6 +220,30 @@ while true; do
 done`, outputDir, image, image)
 }
 
+func orasSidecarScriptPushArtifact(outputDir, image string, withCredentials bool) string {
+	return fmt.Sprintf(`
+while true; do
+  FILE_PATH=$(find %s -name 'fine_tuning_completed.txt')
+  if [ ! -z "$FILE_PATH" ]; then
+    echo "FOUND TRAINING COMPLETED FILE at $FILE_PATH"
+    cd "$(dirname "$FILE_PATH")"
+
+    while true; do
+      if %s; then
+        echo "Upload complete"
+        # Signal completion
+        touch /tmp/upload_complete
+        exit 0
+      else
+        echo "Push failed, retrying in 30 seconds..."
+        sleep 30
+      fi
+    done
+  fi
+  sleep 10  # Check every 10 seconds
+done`, outputDir, utils.GetOrasPushCommand(image, []string{"adapter_config.json", "adapter_model.safetensors"}, withCredentials))
+}
+
 // PrepareOutputDir ensures the output directory is within the base directory.
 func PrepareOutputDir(outputDir string) (string, error) {
 	if outputDir == "" {
@@ -300,6 +337,10 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 
 	// Add shared volume for training output
 	trainingOutputVolume, trainingOutputVolumeMount, outputDir := SetupTrainingOutputVolume(ctx, cm)
+	if workspaceObj.Tuning.Output.Volume != nil {
+		trainingOutputVolume, trainingOutputVolumeMount = handleVolumeDataDestination(ctx, workspaceObj.Tuning.Output.Volume, outputDir,
+			GetTuningOutputPath(workspaceObj, revisionNum))
+	}
 	volumes = append(volumes, trainingOutputVolume)
 	volumeMounts = append(volumeMounts, trainingOutputVolumeMount)
 
@@ -309,7 +350,7 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 	}
 	volumes = append(volumes, dataSourceVolume)
 	volumeMounts = append(volumeMounts, dataSourceVolumeMount)
-	if initContainer.Name != "" {
+	if initContainer != nil {
 		initContainers = append(initContainers, *initContainer)
 	}
 
@@ -317,8 +358,10 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 	if err != nil {
 		return nil, err
 	}
-	volumes = append(volumes, dataDestVolume)
-	volumeMounts = append(volumeMounts, dataDestVolumeMount)
+	if dataDestVolume.Name != "" {
+		volumes = append(volumes, dataDestVolume)
+		volumeMounts = append(volumeMounts, dataDestVolumeMount)
+	}
 	if sidecarContainer != nil {
 		sidecarContainers = append(sidecarContainers, *sidecarContainer)
 	}
@@ -332,18 +375,29 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 	}
 
 	skuNumGPUs, err := utils.GetSKUNumGPUs(ctx, kubeClient, workspaceObj.Status.WorkerNodes,
-		workspaceObj.Resource.InstanceType, tuningObj.GPUCountRequirement)
+		kaitov1alpha1.GetWorkspaceInstanceType(workspaceObj), tuningObj.GPUCountRequirement)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get SKU num GPUs: %v", err)
 	}
 
-	commands, resourceReq := prepareTuningParameters(ctx, workspaceObj, modelCommand, tuningObj, skuNumGPUs)
-	tuningImage, tuningImagePullSecrets := GetTuningImageInfo(ctx, workspaceObj, tuningObj)
+	gpuVendor := utils.GetSKUGPUVendor(ctx, kubeClient, workspaceObj.Status.WorkerNodes, kaitov1alpha1.GetWorkspaceInstanceType(workspaceObj))
+	if !sku.IsEnabledGPUVendor(gpuVendor) {
+		return nil, fmt.Errorf("%s GPUs are disabled by the %s feature gate", gpuVendor, sku.GetGPUVendorConfig(gpuVendor).FeatureGate)
+	}
+	commands, resourceReq := prepareTuningParameters(ctx, workspaceObj, modelCommand, tuningObj, skuNumGPUs, gpuVendor)
+	tuningImage, tuningImagePullSecrets := GetTuningImageInfo(ctx, workspaceObj, tuningObj, gpuVendor)
 	if tuningImagePullSecrets != nil {
 		imagePullSecrets = append(imagePullSecrets, tuningImagePullSecrets...)
 	}
 
 	var envVars []corev1.EnvVar
+	if workspaceObj.Tuning.Checkpoint != nil {
+		checkpointVolume, checkpointVolumeMount, checkpointEnvVars := handleCheckpoint(ctx, workspaceObj.Tuning.Checkpoint,
+			GetTuningCheckpointPath(workspaceObj, revisionNum))
+		volumes = append(volumes, checkpointVolume)
+		volumeMounts = append(volumeMounts, checkpointVolumeMount)
+		envVars = append(envVars, checkpointEnvVars...)
+	}
 	presetName := strings.ToLower(string(workspaceObj.Tuning.Preset.Name))
 	// Append environment variable for default target modules if using Phi3 model
 	if strings.HasPrefix(presetName, "phi-3") {
@@ -354,7 +408,7 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 	}
 	// Add Expandable Memory Feature to reduce Peak GPU Mem Usage
 	envVars = append(envVars, corev1.EnvVar{
-		Name:  "PYTORCH_CUDA_ALLOC_CONF",
+		Name:  sku.GetGPUVendorConfig(gpuVendor).AllocConfEnv,
 		Value: "expandable_segments:True",
 	})
 	jobObj := manifests.GenerateTuningJobManifest(ctx, workspaceObj, revisionNum, tuningImage, imagePullSecrets, *workspaceObj.Resource.Count, commands,
@@ -367,7 +421,8 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 	return jobObj, nil
 }
 
-// Now there are two options for data destination 1. HostPath - 2. Image
+// Now there are two options for data destination 1. Volume - 2. Image, which is a container image or an artifact. The volume replaces the results volume of the
+// tuning job, see handleVolumeDataDestination, so only the image needs extra containers and volumes.
 func prepareDataDestination(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, outputDir string) (*corev1.Container, *corev1.LocalObjectReference, corev1.Volume, corev1.VolumeMount, error) {
 	var sidecarContainer *corev1.Container
 	var volume corev1.Volume
@@ -376,14 +431,84 @@ func prepareDataDestination(ctx context.Context, workspaceObj *kaitov1alpha1.Wor
 	switch {
 	case workspaceObj.Tuning.Output.Image != "":
 		image, secret := workspaceObj.Tuning.Output.Image, workspaceObj.Tuning.Output.ImagePushSecret
-		imagePushSecret = &corev1.LocalObjectReference{Name: secret}
-		sidecarContainer, volume, volumeMount = handleImageDataDestination(ctx, outputDir, image, secret)
-		// TODO: Future PR include
-		//case workspaceObj.Tuning.Output.Volume != nil:
+		if workspaceObj.Tuning.Output.ImageFormat == kaitov1alpha1.ImageFormatArtifact {
+			sidecarContainer, volume, volumeMount = handleArtifactDataDestination(ctx, outputDir, image, secret)
+		} else {
+			imagePushSecret = &corev1.LocalObjectReference{Name: secret}
+			sidecarContainer, volume, volumeMount = handleImageDataDestination(ctx, outputDir, image, secret)
+		}
 	}
 	return sidecarContainer, imagePushSecret, volume, volumeMount, nil
 }
 
+// handleVolumeDataDestination mounts the output volume at the output directory of the tuning job, so that the
+// adapters and checkpoints are written directly to the volume. Each run writes to its own directory of the volume.
+func handleVolumeDataDestination(ctx context.Context, output *corev1.VolumeSource, outputDir, outputPath string) (corev1.Volume, corev1.VolumeMount) {
+	volume, volumeMount := utils.ConfigResultsVolume(outputDir)
+	volume.VolumeSource = *output.DeepCopy()
+	volumeMount.SubPath = outputPath
+	return volume, volumeMount
+}
+
+// GetTuningOutputPath returns the directory in the output volume that contains the results of the tuning job of the
+// given revision of the workspace.
+func GetTuningOutputPath(workspaceObj *kaitov1alpha1.Workspace, revisionNum string) string {
+	return filepath.Join(workspaceObj.Name, revisionNum)
+}
+
+// GetTuningCheckpointPath returns the directory in the checkpoint volume that contains the checkpoints of the tuning
+// job of the given revision of the workspace. A recreated pod of the same job finds the checkpoints of its predecessor
+// there, while a new revision starts from scratch.
+func GetTuningCheckpointPath(workspaceObj *kaitov1alpha1.Workspace, revisionNum string) string {
+	return filepath.Join(GetTuningOutputPath(workspaceObj, revisionNum), "checkpoints")
+}
+
+// handleCheckpoint mounts the checkpoint volume into the tuning container and configures the tuning script to save
+// checkpoints to it, and to resume from the latest one when the pod is recreated.
+func handleCheckpoint(ctx context.Context, checkpoint *kaitov1alpha1.CheckpointSpec, checkpointPath string) (corev1.Volume, corev1.VolumeMount, []corev1.EnvVar) {
+	volume := corev1.Volume{
+		Name:         "checkpoint-volume",
+		VolumeSource: *checkpoint.Volume.DeepCopy(),
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      "checkpoint-volume",
+		MountPath: DefaultCheckpointVolumePath,
+		SubPath:   checkpointPath,
+	}
+	interval := checkpoint.Interval
+	if interval == 0 {
+		interval = DefaultCheckpointInterval
+	}
+	envVars := []corev1.EnvVar{
+		{Name: CheckpointDirEnvVar, Value: DefaultCheckpointVolumePath},
+		{Name: CheckpointStepsEnvVar, Value: fmt.Sprintf("%d", interval)},
+	}
+	return volume, volumeMount, envVars
+}
+
+// handleArtifactDataDestination pushes the adapter as an OCI artifact with an unprivileged oras sidecar, instead of
+// building a container image with docker.
+func handleArtifactDataDestination(ctx context.Context, outputDir, image, imagePushSecret string) (*corev1.Container, corev1.Volume, corev1.VolumeMount) {
+	var volume corev1.Volume
+	var volumeMount corev1.VolumeMount
+	if imagePushSecret != "" {
+		volume, volumeMount = utils.ConfigImagePushSecretVolume(imagePushSecret)
+	}
+	sidecarContainer := &corev1.Container{
+		Name:  "oras-sidecar",
+		Image: utils.GetOrasImage(),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.BoolPtr(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{orasSidecarScriptPushArtifact(outputDir, image, imagePushSecret != "")},
+	}
+	return sidecarContainer, volume, volumeMount
+}
+
 func handleImageDataDestination(ctx context.Context, outputDir, image, imagePushSecret string) (*corev1.Container, corev1.Volume, corev1.VolumeMount) {
 	sidecarContainer := &corev1.Container{
 		Name:  "docker-sidecar",
@@ -398,7 +523,7 @@ func handleImageDataDestination(ctx context.Context, outputDir, image, imagePush
 	return sidecarContainer, volume, volumeMount
 }
 
-// Now there are three options for DataSource: 1. URL - 2. HostPath - 3. Image
+// Now there are three options for DataSource: 1. URL - 2. Volume - 3. Image
 func prepareDataSource(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace) (*corev1.Container, []corev1.LocalObjectReference, corev1.Volume, corev1.VolumeMount, error) {
 	var initContainer *corev1.Container
 	var volume corev1.Volume
@@ -411,8 +536,8 @@ func prepareDataSource(ctx context.Context, workspaceObj *kaitov1alpha1.Workspac
 		initContainer, volume, volumeMount = handleImageDataSource(ctx, image)
 	case len(workspaceObj.Tuning.Input.URLs) > 0:
 		initContainer, volume, volumeMount = handleURLDataSource(ctx, workspaceObj)
-		// TODO: Future PR include
-		// case workspaceObj.Tuning.Input.Volume != nil:
+	case workspaceObj.Tuning.Input.Volume != nil:
+		volume, volumeMount = handleVolumeDataSource(ctx, workspaceObj.Tuning.Input)
 	}
 	return initContainer, imagePullSecrets, volume, volumeMount, nil
 }
@@ -436,6 +561,16 @@ func handleImageDataSource(ctx context.Context, image string) (*corev1.Container
 	return initContainer, volume, volumeMount
 }
 
+// handleVolumeDataSource mounts the volume that contains the data directly into the tuning container, so that large
+// datasets are not copied. The volume is mounted read-only to keep the source data intact.
+func handleVolumeDataSource(ctx context.Context, input *kaitov1alpha1.DataSource) (corev1.Volume, corev1.VolumeMount) {
+	volume, volumeMount := utils.ConfigDataVolume(nil)
+	volume.VolumeSource = *input.Volume.DeepCopy()
+	volumeMount.ReadOnly = true
+	volumeMount.SubPath = input.SubPath
+	return volume, volumeMount
+}
+
 func handleURLDataSource(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace) (*corev1.Container, corev1.Volume, corev1.VolumeMount) {
 	initContainer := &corev1.Container{
 		Name:  "data-downloader",
@@ -500,23 +635,24 @@ func prepareModelRunParameters(ctx context.Context, tuningObj *model.PresetParam
 // and sets the GPU resources required for tuning.
 // Returns the command and resource configuration.
 func prepareTuningParameters(ctx context.Context, wObj *kaitov1alpha1.Workspace, modelCommand string,
-	tuningObj *model.PresetParam, skuNumGPUs string) ([]string, corev1.ResourceRequirements) {
+	tuningObj *model.PresetParam, skuNumGPUs, gpuVendor string) ([]string, corev1.ResourceRequirements) {
 	hfParam := tuningObj.Transformers // Only support Huggingface for now
 	if hfParam.TorchRunParams == nil {
 		hfParam.TorchRunParams = make(map[string]string)
 	}
 	// Set # of processes to GPU Count
-	numProcesses := getInstanceGPUCount(wObj.Resource.InstanceType)
+	numProcesses := getInstanceGPUCount(kaitov1alpha1.GetWorkspaceInstanceType(wObj))
 	hfParam.TorchRunParams["num_processes"] = fmt.Sprintf("%d", numProcesses)
 	torchCommand := utils.BuildCmdStr(hfParam.BaseCommand, hfParam.TorchRunParams, hfParam.TorchRunRdzvParams)
 	commands := utils.ShellCmd(torchCommand + " " + modelCommand)
 
+	gpuResource := sku.GetGPUVendorConfig(gpuVendor).ResourceName
 	resourceRequirements := corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+			gpuResource: resource.MustParse(skuNumGPUs),
 		},
 		Limits: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+			gpuResource: resource.MustParse(skuNumGPUs),
 		},
 	}
 
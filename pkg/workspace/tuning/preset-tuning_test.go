@@ -14,12 +14,14 @@ import (
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
 )
@@ -86,6 +88,7 @@ func TestGetTuningImageInfo(t *testing.T) {
 		registryName string
 		wObj         *kaitov1alpha1.Workspace
 		presetObj    *model.PresetParam
+		gpuVendor    string
 		expected     string
 	}{
 		"Valid Registry and Parameters": {
@@ -120,12 +123,29 @@ func TestGetTuningImageInfo(t *testing.T) {
 			},
 			expected: "/kaito-testpreset:latest",
 		},
+		"ROCm image for AMD GPUs": {
+			registryName: "testregistry",
+			wObj: &kaitov1alpha1.Workspace{
+				Tuning: &kaitov1alpha1.TuningSpec{
+					Preset: &kaitov1alpha1.PresetSpec{
+						PresetMeta: kaitov1alpha1.PresetMeta{
+							Name: "testpreset",
+						},
+					},
+				},
+			},
+			presetObj: &model.PresetParam{
+				Tag: "latest",
+			},
+			gpuVendor: sku.GPUVendorAMD,
+			expected:  "testregistry/kaito-testpreset:latest-rocm",
+		},
 	}
 
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
 			os.Setenv("PRESET_REGISTRY_NAME", tc.registryName)
-			result, _ := GetTuningImageInfo(context.Background(), tc.wObj, tc.presetObj)
+			result, _ := GetTuningImageInfo(context.Background(), tc.wObj, tc.presetObj, tc.gpuVendor)
 			assert.Equal(t, tc.expected, result)
 		})
 	}
@@ -397,6 +417,53 @@ func TestHandleURLDataSource(t *testing.T) {
 	}
 }
 
+func TestHandleVolumeDataSource(t *testing.T) {
+	testcases := map[string]struct {
+		input               *kaitov1alpha1.DataSource
+		expectedVolume      corev1.Volume
+		expectedVolumeMount corev1.VolumeMount
+	}{
+		"Handle PVC Data Source": {
+			input: &kaitov1alpha1.DataSource{
+				Volume: &corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+			},
+			expectedVolume: corev1.Volume{
+				Name: "data-volume",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+			},
+			expectedVolumeMount: corev1.VolumeMount{Name: "data-volume", MountPath: utils.DefaultDataVolumePath, ReadOnly: true},
+		},
+		"Handle NFS Data Source with SubPath": {
+			input: &kaitov1alpha1.DataSource{
+				Volume: &corev1.VolumeSource{
+					NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports"},
+				},
+				SubPath: "datasets/alpaca",
+			},
+			expectedVolume: corev1.Volume{
+				Name: "data-volume",
+				VolumeSource: corev1.VolumeSource{
+					NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports"},
+				},
+			},
+			expectedVolumeMount: corev1.VolumeMount{Name: "data-volume", MountPath: utils.DefaultDataVolumePath, ReadOnly: true, SubPath: "datasets/alpaca"},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			volume, volumeMount := handleVolumeDataSource(context.Background(), tc.input)
+
+			assert.Equal(t, tc.expectedVolume, volume)
+			assert.Equal(t, tc.expectedVolumeMount, volumeMount)
+		})
+	}
+}
+
 func TestPrepareTuningParameters(t *testing.T) {
 	ctx := context.TODO()
 
@@ -439,7 +506,7 @@ func TestPrepareTuningParameters(t *testing.T) {
 
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
-			commands, resources := prepareTuningParameters(ctx, tc.workspaceObj, tc.modelCommand, tc.tuningObj, "2")
+			commands, resources := prepareTuningParameters(ctx, tc.workspaceObj, tc.modelCommand, tc.tuningObj, "2", sku.GPUVendorNvidia)
 			assert.Equal(t, tc.expectedCommands, commands)
 			assert.Equal(t, tc.expectedRequirements.Requests, resources.Requests)
 			assert.Equal(t, tc.expectedRequirements.Limits, resources.Limits)
@@ -484,3 +551,134 @@ func TestPrepareDataSource_ImageSource(t *testing.T) {
 	assert.Equal(t, expectedVolumeMount, volumeMount)
 	assert.Equal(t, expectedImagePullSecrets, imagePullSecrets)
 }
+
+func TestPrepareDataSource_VolumeSource(t *testing.T) {
+	ctx := context.TODO()
+
+	workspaceObj := &kaitov1alpha1.Workspace{
+		Tuning: &kaitov1alpha1.TuningSpec{
+			Input: &kaitov1alpha1.DataSource{
+				Volume: &corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "dataset-pvc"},
+				},
+				SubPath: "train",
+			},
+		},
+	}
+
+	initContainer, imagePullSecrets, volume, volumeMount, err := prepareDataSource(ctx, workspaceObj)
+
+	assert.NoError(t, err)
+	assert.Nil(t, initContainer)
+	assert.Empty(t, imagePullSecrets)
+	assert.Equal(t, "dataset-pvc", volume.PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, corev1.VolumeMount{Name: "data-volume", MountPath: "/mnt/data", ReadOnly: true, SubPath: "train"}, volumeMount)
+}
+
+func TestHandleVolumeDataDestination(t *testing.T) {
+	workspaceObj := &kaitov1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "tuning-phi-3"},
+		Tuning: &kaitov1alpha1.TuningSpec{
+			Output: &kaitov1alpha1.DataDestination{
+				Volume: &corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "results-pvc"},
+				},
+			},
+		},
+	}
+
+	outputPath := GetTuningOutputPath(workspaceObj, "3")
+	assert.Equal(t, "tuning-phi-3/3", outputPath)
+
+	volume, volumeMount := handleVolumeDataDestination(context.Background(), workspaceObj.Tuning.Output.Volume, DefaultOutputVolumePath, outputPath)
+	assert.Equal(t, corev1.Volume{
+		Name: "results-volume",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "results-pvc"},
+		},
+	}, volume)
+	assert.Equal(t, corev1.VolumeMount{Name: "results-volume", MountPath: DefaultOutputVolumePath, SubPath: "tuning-phi-3/3"}, volumeMount)
+}
+
+func TestHandleCheckpoint(t *testing.T) {
+	workspaceObj := &kaitov1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "tuning-phi-3"},
+		Tuning: &kaitov1alpha1.TuningSpec{
+			Checkpoint: &kaitov1alpha1.CheckpointSpec{
+				Volume: &corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints-pvc"},
+				},
+			},
+		},
+	}
+
+	checkpointPath := GetTuningCheckpointPath(workspaceObj, "3")
+	assert.Equal(t, "tuning-phi-3/3/checkpoints", checkpointPath)
+
+	volume, volumeMount, envVars := handleCheckpoint(context.Background(), workspaceObj.Tuning.Checkpoint, checkpointPath)
+	assert.Equal(t, corev1.Volume{
+		Name: "checkpoint-volume",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints-pvc"},
+		},
+	}, volume)
+	assert.Equal(t, corev1.VolumeMount{Name: "checkpoint-volume", MountPath: DefaultCheckpointVolumePath, SubPath: "tuning-phi-3/3/checkpoints"}, volumeMount)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: CheckpointDirEnvVar, Value: DefaultCheckpointVolumePath},
+		{Name: CheckpointStepsEnvVar, Value: "500"},
+	}, envVars)
+
+	workspaceObj.Tuning.Checkpoint.Interval = 100
+	_, _, envVars = handleCheckpoint(context.Background(), workspaceObj.Tuning.Checkpoint, checkpointPath)
+	assert.Contains(t, envVars, corev1.EnvVar{Name: CheckpointStepsEnvVar, Value: "100"})
+}
+
+func TestPrepareDataDestination_VolumeDestination(t *testing.T) {
+	workspaceObj := &kaitov1alpha1.Workspace{
+		Tuning: &kaitov1alpha1.TuningSpec{
+			Output: &kaitov1alpha1.DataDestination{
+				Volume: &corev1.VolumeSource{
+					NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/results"},
+				},
+			},
+		},
+	}
+
+	sidecarContainer, imagePushSecret, volume, _, err := prepareDataDestination(context.Background(), workspaceObj, DefaultOutputVolumePath)
+	assert.NoError(t, err)
+	assert.Nil(t, sidecarContainer)
+	assert.Nil(t, imagePushSecret)
+	assert.Empty(t, volume.Name)
+}
+
+func TestHandleArtifactDataDestination(t *testing.T) {
+	testcases := map[string]struct {
+		imagePushSecret    string
+		expectedVolumeName string
+		expectedCommand    string
+	}{
+		"Push with credentials": {
+			imagePushSecret:    "push-secret",
+			expectedVolumeName: "docker-config",
+			expectedCommand:    "oras push myregistry.azurecr.io/adapter:0.0.1 --artifact-type application/vnd.kaito.adapter.v1 --registry-config /tmp/.docker/config/config.json adapter_config.json adapter_model.safetensors",
+		},
+		"Push without credentials": {
+			expectedCommand: "oras push myregistry.azurecr.io/adapter:0.0.1 --artifact-type application/vnd.kaito.adapter.v1 adapter_config.json adapter_model.safetensors",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			sidecarContainer, volume, volumeMount := handleArtifactDataDestination(context.Background(), DefaultOutputVolumePath, "myregistry.azurecr.io/adapter:0.0.1", tc.imagePushSecret)
+
+			assert.Equal(t, "oras-sidecar", sidecarContainer.Name)
+			assert.Equal(t, utils.DefaultOrasImage, sidecarContainer.Image)
+			assert.Nil(t, sidecarContainer.SecurityContext.Privileged)
+			assert.False(t, *sidecarContainer.SecurityContext.AllowPrivilegeEscalation)
+			assert.Contains(t, sidecarContainer.Args[0], "find /mnt/output -name 'fine_tuning_completed.txt'")
+			assert.Contains(t, sidecarContainer.Args[0], tc.expectedCommand)
+			assert.Equal(t, tc.expectedVolumeName, volume.Name)
+			assert.Equal(t, tc.expectedVolumeName, volumeMount.Name)
+		})
+	}
+}
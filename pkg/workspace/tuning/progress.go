@@ -0,0 +1,63 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package tuning
+
+import (
+	"strconv"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProgressPath is the path of the endpoint of the metrics server of the tuning job that reports the training progress.
+const ProgressPath = "/progress"
+
+// Progress is the training progress reported by the metrics server of the tuning job. It is written by the progress
+// callback of the fine-tuning script.
+type Progress struct {
+	Epoch          float64  `json:"epoch"`
+	NumEpochs      float64  `json:"num_epochs"`
+	Step           int64    `json:"step"`
+	TotalSteps     int64    `json:"total_steps"`
+	TrainLoss      *float64 `json:"train_loss"`
+	EvalLoss       *float64 `json:"eval_loss"`
+	LearningRate   *float64 `json:"learning_rate"`
+	StepsPerSecond *float64 `json:"steps_per_second"`
+	ETASeconds     *float64 `json:"eta_seconds"`
+	LastCheckpoint *string  `json:"last_checkpoint"`
+	// ResumeCount is the number of times the job resumed from a checkpoint, and ResumedFromCheckpoint is the
+	// checkpoint the running pod resumed from, if any.
+	ResumeCount           int32   `json:"resume_count"`
+	ResumedFromCheckpoint *string `json:"resumed_from_checkpoint"`
+}
+
+// ToTuningProgress converts the progress to the tuning progress reported in the workspace status.
+func (p *Progress) ToTuningProgress() *kaitov1alpha1.TuningProgress {
+	progress := &kaitov1alpha1.TuningProgress{
+		Epoch:          strconv.FormatFloat(p.Epoch, 'f', 2, 64),
+		TotalEpochs:    int64(p.NumEpochs),
+		Step:           p.Step,
+		TotalSteps:     p.TotalSteps,
+		TrainingLoss:   formatMetric(p.TrainLoss),
+		EvalLoss:       formatMetric(p.EvalLoss),
+		LearningRate:   formatMetric(p.LearningRate),
+		StepsPerSecond: formatMetric(p.StepsPerSecond),
+	}
+	if p.ETASeconds != nil {
+		progress.EstimatedTimeRemaining = &metav1.Duration{Duration: time.Duration(*p.ETASeconds) * time.Second}
+	}
+	if p.LastCheckpoint != nil {
+		progress.LastCheckpoint = *p.LastCheckpoint
+	}
+	return progress
+}
+
+// formatMetric formats a training metric with four significant digits, or returns empty if it is not reported.
+func formatMetric(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'g', 4, 64)
+}
@@ -34,5 +34,6 @@ func NewWorkspaceCRDValidationWebhook(ctx context.Context, _ configmap.Watcher)
 }
 
 var WorkspaceResources = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
-	kaitov1alpha1.GroupVersion.WithKind("Workspace"): &kaitov1alpha1.Workspace{},
+	kaitov1alpha1.GroupVersion.WithKind("Workspace"):   &kaitov1alpha1.Workspace{},
+	kaitov1alpha1.GroupVersion.WithKind("ModelPreset"): &kaitov1alpha1.ModelPreset{},
 }
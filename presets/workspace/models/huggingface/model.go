@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+package huggingface
+
+import (
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/huggingface"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+)
+
+func init() {
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     huggingface.PresetName,
+		Instance: &hubBase,
+	})
+}
+
+var (
+	// PresetTagMap is the tag of the kaito-huggingface image, which is built from the huggingface entry of
+	// supported_models.yaml without weights.
+	PresetTagMap = map[string]string{
+		"HuggingFace": "0.0.1",
+	}
+
+	baseCommandPresetHuggingFaceInference = "accelerate launch"
+	huggingFaceRunParams                  = map[string]string{
+		"pipeline": "text-generation",
+	}
+)
+
+var hubBase huggingFaceBase
+
+// huggingFaceBase is the runtime image without model weights, which are downloaded from the model repository
+// named by the workspace when the inference workload starts. The requirements depend on the model repository and
+// are filled in by huggingface.NewModel.
+type huggingFaceBase struct{}
+
+func (*huggingFaceBase) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		ModelFamilyName:           "HuggingFace",
+		ImageAccessMode:           string(kaitov1alpha1.ModelImageAccessModePublic),
+		DiskStorageRequirement:    "50Gi",
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "0Gi",
+		PerGPUMemoryRequirement:   "0Gi",
+		RuntimeParam: model.RuntimeParam{
+			Transformers: model.HuggingfaceTransformersParam{
+				BaseCommand:       baseCommandPresetHuggingFaceInference,
+				TorchRunParams:    inference.DefaultAccelerateParams,
+				InferenceMainFile: inference.DefautTransformersMainFile,
+				ModelRunParams:    huggingFaceRunParams,
+			},
+			VLLM: model.VLLMParam{
+				BaseCommand:    inference.DefaultVLLMCommand,
+				ModelRunParams: map[string]string{},
+			},
+		},
+		// The weights are downloaded before the inference server starts.
+		ReadinessTimeout: time.Duration(60) * time.Minute,
+		Tag:              PresetTagMap["HuggingFace"],
+	}
+}
+
+func (*huggingFaceBase) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+
+func (*huggingFaceBase) SupportDistributedInference() bool {
+	return false
+}
+
+func (*huggingFaceBase) SupportTuning() bool {
+	return false
+}
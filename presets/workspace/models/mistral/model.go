@@ -27,8 +27,8 @@ var (
 	PresetMistral7BInstructModel = PresetMistral7BModel + "-instruct"
 
 	PresetMistralTagMap = map[string]string{
-		"Mistral7B":         "0.0.8",
-		"Mistral7BInstruct": "0.0.8",
+		"Mistral7B":         "0.0.10",
+		"Mistral7BInstruct": "0.0.10",
 	}
 
 	baseCommandPresetMistralInference = "accelerate launch"
@@ -42,6 +42,17 @@ var (
 		"dtype":         "float16",
 		"chat-template": "/workspace/chat_templates/mistral-instruct.jinja",
 	}
+	// mistralArchitecture is the architecture of the Mistral 7B models, used to estimate their GPU memory.
+	mistralArchitecture = model.ModelArchitecture{
+		ParameterCount:        7241732096,
+		DType:                 "float16",
+		NumHiddenLayers:       32,
+		HiddenSize:            4096,
+		IntermediateSize:      14336,
+		NumAttentionHeads:     32,
+		NumKeyValueHeads:      8,
+		MaxPositionEmbeddings: 32768,
+	}
 )
 
 var mistralA mistral7b
@@ -56,6 +67,7 @@ func (*mistral7b) GetInferenceParameters() *model.PresetParam {
 		GPUCountRequirement:       "1",
 		TotalGPUMemoryRequirement: "14Gi",
 		PerGPUMemoryRequirement:   "0Gi", // We run Mistral using native vertical model parallel, no per GPU memory requirement.
+		Architecture:              &mistralArchitecture,
 		RuntimeParam: model.RuntimeParam{
 			Transformers: model.HuggingfaceTransformersParam{
 				TorchRunParams:    inference.DefaultAccelerateParams,
@@ -113,6 +125,7 @@ func (*mistral7bInst) GetInferenceParameters() *model.PresetParam {
 		GPUCountRequirement:       "1",
 		TotalGPUMemoryRequirement: "16Gi",
 		PerGPUMemoryRequirement:   "0Gi", // We run mistral using native vertical model parallel, no per GPU memory requirement.
+		Architecture:              &mistralArchitecture,
 		RuntimeParam: model.RuntimeParam{
 			Transformers: model.HuggingfaceTransformersParam{
 				TorchRunParams:    inference.DefaultAccelerateParams,
@@ -42,11 +42,11 @@ var (
 	PresetPhi3_5MiniInstruct  = "phi-3.5-mini-instruct"
 
 	PresetPhiTagMap = map[string]string{
-		"Phi3Mini4kInstruct":     "0.0.3",
-		"Phi3Mini128kInstruct":   "0.0.3",
-		"Phi3Medium4kInstruct":   "0.0.3",
-		"Phi3Medium128kInstruct": "0.0.3",
-		"Phi3_5MiniInstruct":     "0.0.1",
+		"Phi3Mini4kInstruct":     "0.0.5",
+		"Phi3Mini128kInstruct":   "0.0.5",
+		"Phi3Medium4kInstruct":   "0.0.5",
+		"Phi3Medium128kInstruct": "0.0.5",
+		"Phi3_5MiniInstruct":     "0.0.3",
 	}
 
 	baseCommandPresetPhiInference = "accelerate launch"
@@ -73,6 +73,9 @@ func (*phi3Mini4KInst) GetInferenceParameters() *model.PresetParam {
 		GPUCountRequirement:       "1",
 		TotalGPUMemoryRequirement: "9Gi",
 		PerGPUMemoryRequirement:   "0Gi", // We run Phi using native vertical model parallel, no per GPU memory requirement.
+		// The 4-bit quantized model is served on CPUs by the llamacpp runtime.
+		CPURequirement:    "4",
+		MemoryRequirement: "8Gi",
 		RuntimeParam: model.RuntimeParam{
 			Transformers: model.HuggingfaceTransformersParam{
 				BaseCommand:       baseCommandPresetPhiInference,
@@ -85,6 +88,16 @@ func (*phi3Mini4KInst) GetInferenceParameters() *model.PresetParam {
 				ModelName:      "phi-3-mini-4k-instruct",
 				ModelRunParams: phiRunParamsVLLM,
 			},
+			LlamaCpp: model.LlamaCppParam{
+				Image:       inference.DefaultLlamaCppImage,
+				BaseCommand: inference.DefaultLlamaCppCommand,
+				ModelRepo:   "microsoft/Phi-3-mini-4k-instruct-gguf",
+				ModelFile:   "Phi-3-mini-4k-instruct-q4.gguf",
+				ModelName:   "phi-3-mini-4k-instruct",
+				ModelRunParams: map[string]string{
+					"ctx-size": "4096",
+				},
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 		Tag:              PresetPhiTagMap["Phi3Mini4kInstruct"],
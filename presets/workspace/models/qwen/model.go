@@ -22,7 +22,7 @@ var (
 	PresetQwen2_5Coder7BInstructModel = "qwen2.5-coder-7b-instruct"
 
 	PresetTagMap = map[string]string{
-		"Qwen2.5-Coder-7B-Instruct": "0.0.1",
+		"Qwen2.5-Coder-7B-Instruct": "0.0.2",
 	}
 
 	baseCommandPresetQwenInference = "accelerate launch"
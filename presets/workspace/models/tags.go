@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package models
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed supported_models.yaml
+var supportedModelsYAML []byte
+
+// supportedModels is the list of preset images in supported_models.yaml.
+type supportedModels struct {
+	Models []struct {
+		Name       string `yaml:"name"`
+		Tag        string `yaml:"tag"`
+		TagHistory []struct {
+			Tag         string `yaml:"tag"`
+			Description string `yaml:"description"`
+			// Runtimes are the runtimes the image of the tag can serve the model with, transformers if empty.
+			Runtimes []model.RuntimeName `yaml:"runtimes"`
+		} `yaml:"tagHistory"`
+	} `yaml:"models"`
+}
+
+func init() {
+	if err := registerPresetTags(supportedModelsYAML); err != nil {
+		panic(err)
+	}
+}
+
+// registerPresetTags registers the tag history of the preset images listed in supported_models.yaml, so that
+// workspaces can pin a released tag of their preset, and the runtimes that each tag can serve the model with.
+func registerPresetTags(data []byte) error {
+	var models supportedModels
+	if err := yaml.Unmarshal(data, &models); err != nil {
+		return fmt.Errorf("failed to parse the supported models: %w", err)
+	}
+	for _, m := range models.Models {
+		tags := []string{m.Tag}
+		for _, t := range m.TagHistory {
+			if t.Tag != m.Tag {
+				tags = append(tags, t.Tag)
+			}
+			runtimes := t.Runtimes
+			if len(runtimes) == 0 {
+				runtimes = []model.RuntimeName{model.RuntimeNameHuggingfaceTransformers}
+			}
+			for _, r := range runtimes {
+				if model.GetRuntime(r) == nil {
+					return fmt.Errorf("unsupported runtime %s of tag %s of %s", r, t.Tag, m.Name)
+				}
+			}
+			model.RegisterPresetTagRuntimes(m.Name, t.Tag, runtimes)
+		}
+		model.RegisterPresetTags(m.Name, tags)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPresetTags(t *testing.T) {
+	assert.Equal(t, []string{"0.0.5", "0.0.4", "0.0.3", "0.0.2", "0.0.1"}, model.GetPresetTags("phi-3-mini-4k-instruct", "0.0.3"))
+	// The skipped tag of the falcon-40b models is not released.
+	assert.False(t, model.IsKnownPresetTag("falcon-40b", "0.0.8", "0.0.4"))
+	assert.True(t, model.IsKnownPresetTag("falcon-40b-instruct", "0.0.8", "0.0.9"))
+
+	// The tags that predate vLLM only support the transformers runtime.
+	assert.False(t, model.PresetTagSupportsRuntime("falcon-7b", "0.0.6", model.RuntimeNameVLLM))
+	assert.True(t, model.PresetTagSupportsRuntime("falcon-7b", "0.0.6", model.RuntimeNameHuggingfaceTransformers))
+	assert.True(t, model.PresetTagSupportsRuntime("falcon-7b", "0.0.7", model.RuntimeNameVLLM))
+
+	assert.Error(t, registerPresetTags([]byte("models: {}")))
+	assert.Error(t, registerPresetTags([]byte("models: [{name: test, tag: 0.0.1, tagHistory: [{tag: 0.0.1, runtimes: [unknown]}]}]")))
+}
@@ -10,9 +10,11 @@ import torch
 from accelerate import Accelerator
 from dataset import DatasetManager
 from peft import LoraConfig, get_peft_model, prepare_model_for_kbit_training
+from progress import ProgressCallback
 from transformers import (AutoModelForCausalLM, AutoTokenizer,
                           BitsAndBytesConfig,
                           TrainerCallback, TrainerControl, TrainerState)
+from transformers.trainer_utils import IntervalStrategy, get_last_checkpoint
 from trl import SFTTrainer
 
 # Initialize logger
@@ -107,6 +109,33 @@ class EmptyCacheCallback(TrainerCallback):
         return control
 empty_cache_callback = EmptyCacheCallback()
 
+# Checkpoints are saved to CHECKPOINT_DIR, which outlives the pod, so that a recreated pod resumes the training
+# from the latest checkpoint instead of starting from scratch. The results are still saved to the output directory.
+CHECKPOINT_DIR = os.environ.get('CHECKPOINT_DIR')
+output_dir = ta_args.output_dir
+resume_from_checkpoint = None
+resume_count = 0
+if CHECKPOINT_DIR:
+    ta_args.output_dir = CHECKPOINT_DIR
+    ta_args.save_strategy = IntervalStrategy.STEPS
+    ta_args.save_steps = int(os.environ.get('CHECKPOINT_STEPS', 500))
+    if os.path.isdir(CHECKPOINT_DIR):
+        resume_from_checkpoint = get_last_checkpoint(CHECKPOINT_DIR)
+    if resume_from_checkpoint:
+        resume_count_path = os.path.join(CHECKPOINT_DIR, "resume_count.txt")
+        if os.path.exists(resume_count_path):
+            with open(resume_count_path) as f:
+                resume_count = int(f.read().strip() or 0)
+        resume_count += 1
+        if accelerator.is_main_process:
+            with open(resume_count_path, 'w') as f:
+                f.write(str(resume_count))
+        logger.info(f"Resuming from checkpoint {resume_from_checkpoint}, resume count {resume_count}")
+
+PROGRESS_FILE = os.environ.get('TRAINING_PROGRESS_FILE', '/tmp/training_progress.json')
+progress_callback = ProgressCallback(PROGRESS_FILE, resume_count,
+                                     os.path.basename(resume_from_checkpoint) if resume_from_checkpoint else None)
+
 # Prepare for training
 torch.cuda.set_device(accelerator.process_index)
 torch.cuda.empty_cache()
@@ -119,16 +148,16 @@ trainer = accelerator.prepare(SFTTrainer(
     args=ta_args,
     data_collator=dc_args,
     dataset_text_field=dm.dataset_text_field,
-    callbacks=[empty_cache_callback]
+    callbacks=[empty_cache_callback, progress_callback]
     # metrics = "tensorboard" or "wandb" # TODO
 ))
-trainer.train()
-os.makedirs(ta_args.output_dir, exist_ok=True)
-trainer.save_model(ta_args.output_dir)
+trainer.train(resume_from_checkpoint=resume_from_checkpoint)
+os.makedirs(output_dir, exist_ok=True)
+trainer.save_model(output_dir)
 
 # Write file to signify training completion
 timestamp = datetime.now().strftime("%Y-%m-%d-%H-%M-%S")
 logger.info("Fine-Tuning completed\n")
-completion_indicator_path = os.path.join(ta_args.output_dir, "fine_tuning_completed.txt")
+completion_indicator_path = os.path.join(output_dir, "fine_tuning_completed.txt")
 with open(completion_indicator_path, 'w') as f:
     f.write(f"Fine-Tuning completed at {timestamp}\n")
@@ -1,6 +1,7 @@
 # Copyright (c) Microsoft Corporation.
 # Licensed under the MIT license.
 
+import json
 import logging
 import os
 from typing import List, Optional
@@ -43,6 +44,22 @@ class MetricsResponse(BaseModel):
     gpu_info: Optional[List[GPUInfo]] = None
     cpu_info: Optional[CPUInfo] = None
 
+class ProgressResponse(BaseModel):
+    epoch: float = 0
+    num_epochs: float = 0
+    step: int = 0
+    total_steps: int = 0
+    train_loss: Optional[float] = None
+    eval_loss: Optional[float] = None
+    learning_rate: Optional[float] = None
+    steps_per_second: Optional[float] = None
+    eta_seconds: Optional[float] = None
+    last_checkpoint: Optional[str] = None
+    resume_count: int = 0
+    resumed_from_checkpoint: Optional[str] = None
+
+PROGRESS_FILE = os.environ.get('TRAINING_PROGRESS_FILE', '/tmp/training_progress.json')
+
 @app.get(
     "/metrics",
     response_model=MetricsResponse,
@@ -117,6 +134,31 @@ def get_metrics():
         logger.error(f"Error fetching metrics: {e}")
         raise HTTPException(status_code=500, detail=str(e))
 
+@app.get(
+    "/progress",
+    response_model=ProgressResponse,
+    summary="Training Progress Endpoint",
+    responses={
+        404: {
+            "description": "Training has not started",
+            "model": ErrorResponse,
+        }
+    }
+)
+def get_progress():
+    """
+    Provides the progress of the training written by the fine-tuning job, including the current epoch and step,
+    the latest losses and learning rate, the throughput and the estimated time remaining.
+    """
+    try:
+        with open(PROGRESS_FILE) as f:
+            return ProgressResponse(**json.load(f))
+    except FileNotFoundError:
+        raise HTTPException(status_code=404, detail="Training has not started")
+    except Exception as e:
+        logger.error(f"Error reading progress: {e}")
+        raise HTTPException(status_code=500, detail=str(e))
+
 if __name__ == "__main__":
     local_rank = int(os.environ.get("LOCAL_RANK", 0)) # Default to 0 if not set
     port = 5000 + local_rank # Adjust port based on local rank
@@ -1,11 +1,13 @@
 # Copyright (c) Microsoft Corporation.
 # Licensed under the MIT license.
 
+import json
 import os
 from unittest.mock import MagicMock, patch
 
 import pytest
 from fastapi.testclient import TestClient
+import metrics_server
 from metrics_server import CPUInfo, GPUInfo, MemoryInfo, MetricsResponse, app
 
 client = TestClient(app)
@@ -77,3 +79,28 @@ def test_metrics_endpoint_failure(set_env_vars):
         response = client.get("/metrics")
         assert response.status_code == 500
         assert response.json() == {"detail": "Test Exception"}
+
+def test_progress_endpoint(tmp_path):
+    progress_file = tmp_path / "training_progress.json"
+    progress_file.write_text(json.dumps({
+        "epoch": 1.5, "num_epochs": 3, "step": 150, "total_steps": 300,
+        "train_loss": 1.25, "learning_rate": 0.0002, "steps_per_second": 0.5, "eta_seconds": 300,
+        "last_checkpoint": "checkpoint-100", "resume_count": 1, "resumed_from_checkpoint": "checkpoint-100",
+    }))
+    with patch.object(metrics_server, "PROGRESS_FILE", str(progress_file)):
+        response = client.get("/progress")
+        assert response.status_code == 200
+        data = response.json()
+        assert data["epoch"] == 1.5
+        assert data["step"] == 150
+        assert data["total_steps"] == 300
+        assert data["train_loss"] == 1.25
+        assert data["eval_loss"] is None
+        assert data["last_checkpoint"] == "checkpoint-100"
+        assert data["resume_count"] == 1
+        assert data["resumed_from_checkpoint"] == "checkpoint-100"
+
+def test_progress_endpoint_not_started(tmp_path):
+    with patch.object(metrics_server, "PROGRESS_FILE", str(tmp_path / "missing.json")):
+        response = client.get("/progress")
+        assert response.status_code == 404
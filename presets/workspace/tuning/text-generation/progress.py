@@ -0,0 +1,65 @@
+# Copyright (c) Microsoft Corporation.
+# Licensed under the MIT license.
+import json
+import os
+import time
+from typing import Optional
+
+from transformers import TrainerCallback, TrainerControl, TrainerState
+
+
+class ProgressCallback(TrainerCallback):
+    """Writes the training progress to a file, which is served by the metrics server."""
+    def __init__(self, progress_file: str, resume_count: int = 0, resumed_from_checkpoint: Optional[str] = None):
+        self.progress_file = progress_file
+        self.start_time = None
+        # The steps restored from a checkpoint are not trained by this run, so they are excluded from the throughput.
+        self.start_step = 0
+        self.progress = {"resume_count": resume_count}
+        if resumed_from_checkpoint:
+            self.progress["resumed_from_checkpoint"] = resumed_from_checkpoint
+            self.progress["last_checkpoint"] = resumed_from_checkpoint
+
+    def write_progress(self, state: TrainerState):
+        if not state.is_world_process_zero:
+            return
+        elapsed = time.time() - self.start_time
+        trained_steps = state.global_step - self.start_step
+        steps_per_second = trained_steps / elapsed if elapsed > 0 and trained_steps > 0 else None
+        self.progress.update({
+            "epoch": state.epoch or 0,
+            "num_epochs": state.num_train_epochs,
+            "step": state.global_step,
+            "total_steps": state.max_steps,
+            "steps_per_second": steps_per_second,
+            "eta_seconds": (state.max_steps - state.global_step) / steps_per_second if steps_per_second else None,
+        })
+        # Replace the file atomically so that the metrics server never reads a partial file.
+        tmp_path = f"{self.progress_file}.tmp"
+        with open(tmp_path, 'w') as f:
+            json.dump(self.progress, f)
+        os.replace(tmp_path, self.progress_file)
+
+    def on_train_begin(self, args, state: TrainerState, control: TrainerControl, **kwargs):
+        # The state is already restored from the checkpoint when the training resumes.
+        self.start_time = time.time()
+        self.start_step = state.global_step
+        self.write_progress(state)
+        return control
+
+    def on_log(self, args, state: TrainerState, control: TrainerControl, logs=None, **kwargs):
+        logs = logs or {}
+        for key, name in (("loss", "train_loss"), ("eval_loss", "eval_loss"), ("learning_rate", "learning_rate")):
+            if key in logs:
+                self.progress[name] = logs[key]
+        self.write_progress(state)
+        return control
+
+    def on_epoch_end(self, args, state: TrainerState, control: TrainerControl, **kwargs):
+        self.write_progress(state)
+        return control
+
+    def on_save(self, args, state: TrainerState, control: TrainerControl, **kwargs):
+        self.progress["last_checkpoint"] = f"checkpoint-{state.global_step}"
+        self.write_progress(state)
+        return control
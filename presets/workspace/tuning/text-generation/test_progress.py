@@ -0,0 +1,62 @@
+# Copyright (c) Microsoft Corporation.
+# Licensed under the MIT license.
+import json
+from unittest.mock import patch
+
+from progress import ProgressCallback
+from transformers import TrainerControl, TrainerState
+
+
+def read_progress(path):
+    with open(path) as f:
+        return json.load(f)
+
+def test_progress_from_scratch(tmp_path):
+    progress_file = tmp_path / "training_progress.json"
+    callback = ProgressCallback(str(progress_file))
+    state = TrainerState(epoch=0, global_step=0, max_steps=200, num_train_epochs=2)
+
+    with patch("progress.time.time", return_value=1000):
+        callback.on_train_begin(None, state, TrainerControl())
+    data = read_progress(progress_file)
+    assert data["step"] == 0
+    assert data["steps_per_second"] is None
+    assert data["eta_seconds"] is None
+    assert data["resume_count"] == 0
+
+    state.global_step, state.epoch = 50, 0.5
+    with patch("progress.time.time", return_value=1100):
+        callback.on_log(None, state, TrainerControl(), logs={"loss": 1.5, "learning_rate": 0.0002})
+    data = read_progress(progress_file)
+    assert data["step"] == 50
+    assert data["steps_per_second"] == 0.5
+    assert data["eta_seconds"] == 300
+    assert data["train_loss"] == 1.5
+
+def test_progress_after_resume(tmp_path):
+    progress_file = tmp_path / "training_progress.json"
+    callback = ProgressCallback(str(progress_file), resume_count=1, resumed_from_checkpoint="checkpoint-100")
+    # The state restored from the checkpoint already includes the steps trained before the pod was recreated.
+    state = TrainerState(epoch=1.0, global_step=100, max_steps=200, num_train_epochs=2)
+
+    with patch("progress.time.time", return_value=1000):
+        callback.on_train_begin(None, state, TrainerControl())
+    data = read_progress(progress_file)
+    assert data["step"] == 100
+    assert data["steps_per_second"] is None
+    assert data["resume_count"] == 1
+    assert data["resumed_from_checkpoint"] == "checkpoint-100"
+    assert data["last_checkpoint"] == "checkpoint-100"
+
+    state.global_step = 110
+    with patch("progress.time.time", return_value=1020):
+        callback.on_log(None, state, TrainerControl(), logs={"loss": 0.9})
+    data = read_progress(progress_file)
+    # Only the 10 steps trained since the resume count towards the throughput.
+    assert data["steps_per_second"] == 0.5
+    assert data["eta_seconds"] == 180
+
+    state.global_step = 120
+    with patch("progress.time.time", return_value=1040):
+        callback.on_save(None, state, TrainerControl())
+    assert read_progress(progress_file)["last_checkpoint"] == "checkpoint-120"